@@ -0,0 +1,97 @@
+// Package api holds the OpenAPI 3 document of the HTTP API. The document is served on
+// /cxf/v1/openapi.json and is the source the Go client in package client is generated from.
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+//go:embed openapi.json
+var Spec []byte
+
+// methods are the OpenAPI operation keys of a path item.
+var methods = []string{"get", "put", "post", "delete", "patch", "head", "options"}
+
+// operation holds the fields of an operation Verify looks at.
+type operation struct {
+	Security *[]map[string][]string `json:"security"`
+}
+
+// Operations returns the operations of the spec as sorted "METHOD /path" strings, the
+// same form as the patterns registered on the server mux.
+func Operations() ([]string, error) {
+	operations, _, err := parse()
+	return operations, err
+}
+
+// parse returns the operations of the spec and the public ones, which override the
+// global security requirement with an empty one.
+func parse() (operations, public []string, err error) {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(Spec, &doc); err != nil {
+		return nil, nil, fmt.Errorf("parse openapi.json: %w", err)
+	}
+
+	for path, item := range doc.Paths {
+		for method, raw := range item {
+			if !slices.Contains(methods, method) {
+				continue
+			}
+			var op operation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, nil, fmt.Errorf("parse %s %s: %w", method, path, err)
+			}
+			name := strings.ToUpper(method) + " " + path
+			operations = append(operations, name)
+			if op.Security != nil && len(*op.Security) == 0 {
+				public = append(public, name)
+			}
+		}
+	}
+	slices.Sort(operations)
+	slices.Sort(public)
+	return operations, public, nil
+}
+
+// Verify compares the spec with the registered route patterns and lists the routes
+// missing from the spec, the operations no route serves and the operations whose
+// security doesn't match the public routes.
+func Verify(routes, publicRoutes []string) error {
+	operations, public, err := parse()
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, route := range routes {
+		if !slices.Contains(operations, route) {
+			problems = append(problems, "undocumented route "+route)
+		}
+	}
+	for _, operation := range operations {
+		if !slices.Contains(routes, operation) {
+			problems = append(problems, "no route for documented operation "+operation)
+		}
+	}
+	for _, route := range publicRoutes {
+		if slices.Contains(operations, route) && !slices.Contains(public, route) {
+			problems = append(problems, "public route "+route+" requires a token in the spec")
+		}
+	}
+	for _, operation := range public {
+		if slices.Contains(routes, operation) && !slices.Contains(publicRoutes, operation) {
+			problems = append(problems, "route "+operation+" requires a token but the spec makes it public")
+		}
+	}
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return fmt.Errorf("openapi.json is out of date with the routes:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// APITokenPrefix starts every personal API token, telling them apart from JWTs and
+// making leaked tokens easy to grep for.
+const APITokenPrefix = "exp_"
+
+// Scopes of personal API tokens. Read tokens may only call GET and HEAD routes.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// apiTokenDisplayLength is how much of a token is kept in clear to tell tokens apart.
+const apiTokenDisplayLength = len(APITokenPrefix) + 6
+
+// GenerateAPIToken returns a new personal API token, the hash to store in its place and
+// the prefix to show in listings. The token itself is never stored.
+func GenerateAPIToken() (token, hash, displayPrefix string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	token = APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return token, HashAPIToken(token), token[:apiTokenDisplayLength], nil
+}
+
+// HashAPIToken returns the SHA-256 of token. The tokens are random, so a fast hash is
+// enough to keep a leaked table from being usable.
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
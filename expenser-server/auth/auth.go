@@ -25,17 +25,31 @@ func CheckPasswordHash(password, hash string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
 
-func MakeJWT(userId uuid.UUID, tokenSecret []byte, expiresIn time.Duration) (string, error) {
-	signingKey := []byte(tokenSecret)
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Issuer:    "expenser",
-		IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
-		Subject:   userId.String(),
-	})
+// Roles a user can have. Routes restricted to a role check the role claim of the token.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// challengeAudience marks the challenge tokens of two-factor logins.
+const challengeAudience = "expenser-2fa"
+
+// Claims are the claims of the tokens issued on login.
+type Claims struct {
+	Role string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
 
-	return token.SignedString(signingKey)
+func (k *Keys) MakeJWT(userId uuid.UUID, role string, expiresIn time.Duration) (string, error) {
+	return k.sign(Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "expenser",
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
+			Subject:   userId.String(),
+		},
+	})
 }
 
 func GetBearerToken(header http.Header) (string, error) {
@@ -53,35 +67,78 @@ func GetBearerToken(header http.Header) (string, error) {
 	return splitAuth[1], nil
 }
 
-func ValidateJWT(tokenString string, tokenSecret []byte) (uuid.UUID, error) {
-	claimStruct := jwt.RegisteredClaims{}
+// ValidateJWT returns the user and the role of the token. Tokens issued before roles
+// existed carry no role claim and get RoleUser. Challenge tokens aren't access tokens
+// and are rejected.
+func (k *Keys) ValidateJWT(tokenString string) (uuid.UUID, string, error) {
+	claims, userID, err := k.parse(tokenString)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	if len(claims.Audience) > 0 {
+		return uuid.Nil, "", errors.New("not an access token")
+	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &claimStruct, func(t *jwt.Token) (any, error) {
-		return []byte(tokenSecret), nil
+	role := claims.Role
+	if role == "" {
+		role = RoleUser
+	}
+
+	return userID, role, nil
+}
+
+// MakeChallengeJWT returns the token a user with two-factor authentication gets for
+// their password. It only proves the password and is exchanged, with a code, for an
+// access token.
+func (k *Keys) MakeChallengeJWT(userId uuid.UUID, expiresIn time.Duration) (string, error) {
+	return k.sign(Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "expenser",
+			Audience:  jwt.ClaimStrings{challengeAudience},
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
+			Subject:   userId.String(),
+		},
 	})
+}
+
+// ValidateChallengeJWT returns the user of a token made by MakeChallengeJWT.
+func (k *Keys) ValidateChallengeJWT(tokenString string) (uuid.UUID, error) {
+	_, userID, err := k.parse(tokenString, jwt.WithAudience(challengeAudience))
+	return userID, err
+}
+
+func (k *Keys) parse(tokenString string, options ...jwt.ParserOption) (Claims, uuid.UUID, error) {
+	if k == nil {
+		return Claims{}, uuid.Nil, errors.New("no keys to verify the token")
+	}
+	claimStruct := Claims{}
+
+	options = append(options, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg(), jwt.SigningMethodHS256.Alg()}))
+	token, err := jwt.ParseWithClaims(tokenString, &claimStruct, k.keyFunc, options...)
 
 	if err != nil {
-		return uuid.Nil, err
+		return Claims{}, uuid.Nil, err
 	}
 
 	if !token.Valid {
-		return uuid.Nil, errors.New("invalid token")
+		return Claims{}, uuid.Nil, errors.New("invalid token")
 	}
 
 	userId, err := claimStruct.GetSubject()
 	if userId == "" || err != nil {
-		return uuid.Nil, err
+		return Claims{}, uuid.Nil, errors.New("token has no subject")
 	}
 
 	issuer, _ := claimStruct.GetIssuer()
 	if issuer != "expenser" {
-		return uuid.Nil, errors.New("invalid issuer")
+		return Claims{}, uuid.Nil, errors.New("invalid issuer")
 	}
 
 	userUUID, err := uuid.Parse(userId)
 	if err != nil {
-		return uuid.Nil, err
+		return Claims{}, uuid.Nil, err
 	}
 
-	return userUUID, nil
+	return claimStruct, userUUID, nil
 }
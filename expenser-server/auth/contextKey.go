@@ -9,10 +9,33 @@ import (
 // unexported type to prevent key collisions
 type contextKey string
 
-// exported key of the unexported type
-const UserIDKey = contextKey("userID")
+// exported keys of the unexported type
+const (
+	UserIDKey = contextKey("userID")
+	RoleKey   = contextKey("role")
+	// ScopeKey is only set for requests made with a personal API token.
+	ScopeKey = contextKey("scope")
+)
+
+// Identity is who a request is authenticated as. Scope is empty for login tokens, which
+// aren't limited, and set for personal API tokens.
+type Identity struct {
+	UserID uuid.UUID
+	Role   string
+	Scope  string
+}
 
 func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	id, ok := ctx.Value(UserIDKey).(uuid.UUID)
 	return id, ok
 }
+
+func RoleFromContext(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(RoleKey).(string)
+	return role, ok
+}
+
+func ScopeFromContext(ctx context.Context) (string, bool) {
+	scope, ok := ctx.Value(ScopeKey).(string)
+	return scope, ok
+}
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA key accepted for signing or verification.
+const minRSABits = 2048
+
+// Keys signs and verifies the login tokens. Tokens are signed with EdDSA or RS256,
+// depending on the signing key, and carry the kid of their key so several keys can be
+// trusted while rotating. Without a signing key tokens are signed with HS256 and the
+// shared secret, as before asymmetric keys were supported.
+type Keys struct {
+	signing   *key
+	verifying map[string]*key
+	// secret signs HS256 tokens when there is no signing key and, if set alongside
+	// one, keeps verifying the HS256 tokens issued before the switch.
+	secret []byte
+}
+
+type key struct {
+	id        string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	public    crypto.PublicKey
+	jwkParams map[string]string
+}
+
+// JWK is a public key in the JSON Web Key format.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadKeys reads the PEM signing key and verification keys from their files. The
+// verification files may hold public or private keys, the public key of the signing
+// key is always trusted. secret enables HS256, either as the only method when
+// signingKeyFile is empty or as a fallback accepted for verification.
+func LoadKeys(signingKeyFile string, verificationKeyFiles []string, secret string) (*Keys, error) {
+	keys := &Keys{verifying: map[string]*key{}}
+	if secret != "" {
+		keys.secret = []byte(secret)
+	}
+
+	if signingKeyFile != "" {
+		signing, err := readKey(signingKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if signing.private == nil {
+			return nil, fmt.Errorf("signing key %s: not a private key", signingKeyFile)
+		}
+		keys.signing = signing
+		keys.verifying[signing.id] = signing
+	}
+
+	for _, file := range verificationKeyFiles {
+		verifying, err := readKey(file)
+		if err != nil {
+			return nil, err
+		}
+		keys.verifying[verifying.id] = verifying
+	}
+
+	if keys.signing == nil && keys.secret == nil {
+		return nil, errors.New("either a signing key or a secret is required")
+	}
+	return keys, nil
+}
+
+// SigningKeyID returns the kid of the signing key, empty when signing with HS256.
+func (k *Keys) SigningKeyID() string {
+	if k.signing == nil {
+		return ""
+	}
+	return k.signing.id
+}
+
+// JWKS returns the public verification keys. The HS256 secret is never published.
+func (k *Keys) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range k.verifying {
+		jwk := JWK{
+			KeyType:   key.jwkParams["kty"],
+			KeyID:     key.id,
+			Algorithm: key.method.Alg(),
+			Use:       "sig",
+			Curve:     key.jwkParams["crv"],
+			X:         key.jwkParams["x"],
+			N:         key.jwkParams["n"],
+			E:         key.jwkParams["e"],
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	slices.SortFunc(jwks.Keys, func(a, b JWK) int { return strings.Compare(a.KeyID, b.KeyID) })
+	return jwks
+}
+
+func (k *Keys) sign(claims jwt.Claims) (string, error) {
+	if k.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(k.secret)
+	}
+
+	token := jwt.NewWithClaims(k.signing.method, claims)
+	token.Header["kid"] = k.signing.id
+	return token.SignedString(k.signing.private)
+}
+
+// keyFunc picks the verification key of a token from its kid, refusing any algorithm
+// other than the one of that key.
+func (k *Keys) keyFunc(token *jwt.Token) (any, error) {
+	if token.Method == jwt.SigningMethodHS256 {
+		if k.secret == nil {
+			return nil, errors.New("HS256 tokens aren't accepted")
+		}
+		return k.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.verifying[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("key %q doesn't sign %s tokens", kid, token.Method.Alg())
+	}
+	return key.public, nil
+}
+
+func readKey(file string) (*key, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM block found", file)
+	}
+
+	parsed, err := parsePEMKey(block)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", file, err)
+	}
+
+	var k *key
+	switch parsed := parsed.(type) {
+	case ed25519.PrivateKey:
+		k, err = newKey(parsed.Public())
+		if err == nil {
+			k.private = parsed
+		}
+	case *rsa.PrivateKey:
+		k, err = newKey(parsed.Public())
+		if err == nil {
+			k.private = parsed
+		}
+	default:
+		k, err = newKey(parsed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", file, err)
+	}
+	return k, nil
+}
+
+func parsePEMKey(block *pem.Block) (any, error) {
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
+
+// newKey returns the verification key of public with its kid, the RFC 7638 thumbprint of
+// its JWK, so the same key always gets the same kid.
+func newKey(public crypto.PublicKey) (*key, error) {
+	k := &key{public: public}
+	switch public := public.(type) {
+	case ed25519.PublicKey:
+		k.method = jwt.SigningMethodEdDSA
+		k.jwkParams = map[string]string{
+			"crv": "Ed25519",
+			"kty": "OKP",
+			"x":   base64.RawURLEncoding.EncodeToString(public),
+		}
+	case *rsa.PublicKey:
+		if public.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA keys must have at least %d bits, got %d", minRSABits, public.N.BitLen())
+		}
+		k.method = jwt.SigningMethodRS256
+		k.jwkParams = map[string]string{
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use Ed25519 or RSA", public)
+	}
+
+	// The thumbprint hashes the required members in lexicographic order, which is the
+	// order encoding/json writes map keys in.
+	canonical, err := json.Marshal(k.jwkParams)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(canonical)
+	k.id = base64.RawURLEncoding.EncodeToString(sum[:])
+	return k, nil
+}
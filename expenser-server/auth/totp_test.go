@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 seed of the RFC 6238 test vectors, "12345678901234567890",
+// in base32.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// rfc6238Vectors are the SHA1 test vectors of RFC 6238 appendix B, cut to the last six
+// of their eight digits.
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestValidateTOTPVectors(t *testing.T) {
+	for _, tt := range rfc6238Vectors {
+		step, ok := ValidateTOTP(rfc6238Secret, tt.code, time.Unix(tt.unix, 0), 0)
+		if !ok {
+			t.Errorf("code %s rejected at %d", tt.code, tt.unix)
+			continue
+		}
+		if want := tt.unix / totpPeriod; step != want {
+			t.Errorf("code %s at %d matched step %d, want %d", tt.code, tt.unix, step, want)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	// 050471 is the code of step 37037037, from 1111111110 to 1111111139.
+	const code = "050471"
+	tests := []struct {
+		name   string
+		unix   int64
+		wantOK bool
+	}{
+		{"two steps early", 1111111110 - 2*totpPeriod, false},
+		{"one step early", 1111111110 - totpPeriod, true},
+		{"first second of the step", 1111111110, true},
+		{"last second of the step", 1111111139, true},
+		{"one step late", 1111111139 + totpPeriod, true},
+		{"two steps late", 1111111139 + 2*totpPeriod, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(rfc6238Secret, code, time.Unix(tt.unix, 0), 0)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && step != 37037037 {
+				t.Errorf("step = %d, want 37037037", step)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPReplay(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step, ok := ValidateTOTP(rfc6238Secret, "050471", now, 0)
+	if !ok {
+		t.Fatal("first use rejected")
+	}
+	if _, ok := ValidateTOTP(rfc6238Secret, "050471", now, step); ok {
+		t.Error("code accepted again after its step was used")
+	}
+	if _, ok := ValidateTOTP(rfc6238Secret, "050471", now.Add(totpPeriod*time.Second), step); ok {
+		t.Error("code accepted again in the next step")
+	}
+	// The code of the previous step is still in the window but older than the last use.
+	if _, ok := ValidateTOTP(rfc6238Secret, "081804", now, step); ok {
+		t.Error("code of an earlier step accepted after a later one was used")
+	}
+	if _, ok := ValidateTOTP(rfc6238Secret, "050471", now, step-1); !ok {
+		t.Error("code rejected after only an earlier step was used")
+	}
+}
+
+func TestValidateTOTPInvalid(t *testing.T) {
+	now := time.Unix(59, 0)
+	tests := []struct {
+		name, secret, code string
+	}{
+		{"wrong code", rfc6238Secret, "287083"},
+		{"short code", rfc6238Secret, "28708"},
+		{"eight digits", rfc6238Secret, "94287082"},
+		{"invalid secret", "not base32!", "287082"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := ValidateTOTP(tt.secret, tt.code, now, 0); ok {
+				t.Errorf("ValidateTOTP(%q, %q) accepted", tt.secret, tt.code)
+			}
+		})
+	}
+	// Secrets are accepted in lower case, as some apps display them.
+	if _, ok := ValidateTOTP("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", "287082", now, 0); !ok {
+		t.Error("lower case secret rejected")
+	}
+}
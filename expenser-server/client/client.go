@@ -0,0 +1,157 @@
+// Package client is a typed Go client of the Expenser API for scripts and tools. The
+// methods in client_gen.go are generated from api/openapi.json by cmd/clientgen; run
+// `go generate ./client` after changing the spec.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client calls the API at BaseURL, like "http://localhost:8080". Token is sent as the
+// bearer token when set, the Login method returns one.
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+func New(baseURL, token string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Token:      token,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// Error is returned for every response outside 2xx. Problem holds the problem+json
+// body, when the server sent one.
+type Error struct {
+	StatusCode int
+	Problem    Problem
+}
+
+func (e *Error) Error() string {
+	detail := e.Problem.Detail
+	if detail == "" {
+		detail = http.StatusText(e.StatusCode)
+	}
+	if e.Problem.Code == "" {
+		return fmt.Sprintf("expenser: %d: %s", e.StatusCode, detail)
+	}
+	return fmt.Sprintf("expenser: %d %s: %s", e.StatusCode, e.Problem.Code, detail)
+}
+
+// do sends body as JSON and decodes the response into out, when they aren't nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := c.newRequest(ctx, method, path, query, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.send(req, out)
+}
+
+// upload posts the file as the multipart form field and decodes the response into out.
+func (c *Client) upload(ctx context.Context, path, field, filename string, file io.Reader, out any) error {
+	var payload bytes.Buffer
+	form := multipart.NewWriter(&payload)
+	part, err := form.CreateFormFile(field, filename)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("read %s: %w", filename, err)
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil, &payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return c.send(req, out)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	return req, nil
+}
+
+func (c *Client) send(req *http.Request, out any) error {
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		// A body that isn't problem+json, like a proxy error page, leaves Problem empty.
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr.Problem)
+		return apiErr
+	}
+	// Operations answering either with a body or without one leave out as it is.
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *string:
+		text, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		*out = string(text)
+		return nil
+	case *[]byte:
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		*out = data
+		return nil
+	default:
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decode %s %s response: %w", req.Method, req.URL.Path, err)
+		}
+		return nil
+	}
+}
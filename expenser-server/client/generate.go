@@ -0,0 +1,3 @@
+package client
+
+//go:generate go run ../cmd/clientgen -out client_gen.go -package client
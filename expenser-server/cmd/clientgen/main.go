@@ -0,0 +1,471 @@
+// Command clientgen generates the typed Go client in package client from api/openapi.json.
+// It first checks the spec against the routes the server registers and fails when they
+// disagree, so a route can't be added or removed without updating the spec.
+//
+//	go generate ./client
+//	go run ./cmd/clientgen -check
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/keertirajmalik/expenser/expenser-server/api"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/server"
+)
+
+type schema struct {
+	Ref                  string     `json:"$ref"`
+	Type                 string     `json:"type"`
+	Format               string     `json:"format"`
+	Description          string     `json:"description"`
+	Nullable             bool       `json:"nullable"`
+	Properties           properties `json:"properties"`
+	Required             []string   `json:"required"`
+	Items                *schema    `json:"items"`
+	AdditionalProperties any        `json:"additionalProperties"`
+}
+
+// properties keeps the order of the properties in the spec so the generated structs
+// list their fields in the same order.
+type properties struct {
+	names   []string
+	schemas map[string]*schema
+}
+
+func (p *properties) UnmarshalJSON(data []byte) error {
+	var schemas map[string]*schema
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		p.names = append(p.names, token.(string))
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return err
+		}
+	}
+	p.schemas = schemas
+	return nil
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+type content map[string]struct {
+	Schema *schema `json:"schema"`
+}
+
+type operation struct {
+	OperationID string      `json:"operationId"`
+	Summary     string      `json:"summary"`
+	Parameters  []parameter `json:"parameters"`
+	RequestBody *struct {
+		Content content `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content content `json:"content"`
+	} `json:"responses"`
+
+	method string
+	path   string
+}
+
+type document struct {
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+func main() {
+	out := flag.String("out", "client_gen.go", "file to write the client to")
+	pkg := flag.String("package", "client", "package name of the generated file")
+	check := flag.Bool("check", false, "only run the checks, don't write the client")
+	flag.Parse()
+
+	if err := api.Verify(server.Routes(), server.PublicRoutes()); err != nil {
+		log.Fatal(err)
+	}
+	if *check {
+		return
+	}
+
+	var doc document
+	if err := json.Unmarshal(api.Spec, &doc); err != nil {
+		log.Fatalf("parse openapi.json: %v", err)
+	}
+
+	source, err := generate(doc, *pkg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, source, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(doc document, pkg string) ([]byte, error) {
+	g := &generator{}
+
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		g.schemaType(name, doc.Components.Schemas[name])
+	}
+
+	var operations []*operation
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			op.method = strings.ToUpper(method)
+			op.path = path
+			operations = append(operations, op)
+		}
+	}
+	slices.SortFunc(operations, func(a, b *operation) int { return strings.Compare(a.OperationID, b.OperationID) })
+	for _, op := range operations {
+		if err := g.operation(op); err != nil {
+			return nil, err
+		}
+	}
+
+	var file bytes.Buffer
+	fmt.Fprintf(&file, "// Code generated by clientgen from api/openapi.json. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&file, "package %s\n\n", pkg)
+	fmt.Fprintf(&file, "import (\n")
+	for _, imp := range imports {
+		if bytes.Contains(g.buf.Bytes(), []byte(imp.use)) {
+			if strings.Contains(imp.path, ".") {
+				fmt.Fprintln(&file)
+			}
+			fmt.Fprintf(&file, "%q\n", imp.path)
+		}
+	}
+	fmt.Fprintf(&file, ")\n\n")
+	file.Write(g.buf.Bytes())
+
+	source, err := format.Source(file.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated client: %w\n%s", err, file.String())
+	}
+	return source, nil
+}
+
+// imports are the packages the generated code may need, added when it uses them.
+var imports = []struct{ path, use string }{
+	{"context", "context.Context"},
+	{"io", "io.Reader"},
+	{"net/http", "http.Method"},
+	{"net/url", "url."},
+	{"strconv", "strconv."},
+	{"time", "time.Time"},
+	{"github.com/shopspring/decimal", "decimal.Decimal"},
+}
+
+type generator struct {
+	buf bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) schemaType(name string, s *schema) {
+	if s.Description != "" {
+		g.printf("// %s is %s\n", name, lowerFirst(s.Description))
+	}
+	if s.Type != "object" || s.Properties.names == nil {
+		g.printf("type %s %s\n\n", name, goType(s))
+		return
+	}
+	g.printf("type %s struct {\n", name)
+	g.fields(s)
+	g.printf("}\n\n")
+}
+
+func (g *generator) fields(s *schema) {
+	for _, prop := range s.Properties.names {
+		field := s.Properties.schemas[prop]
+		if field.Description != "" {
+			g.printf("// %s\n", field.Description)
+		}
+		typ, tag := goType(field), prop
+		if !slices.Contains(s.Required, prop) {
+			tag += ",omitempty"
+			// omitempty never skips a struct, a pointer leaves optional ones out.
+			if field.Ref != "" || typ == "decimal.Decimal" || typ == "time.Time" {
+				typ = "*" + typ
+			}
+		}
+		g.printf("%s %s `json:%q`\n", goName(prop), typ, tag)
+	}
+}
+
+func (g *generator) operation(op *operation) error {
+	name := goName(op.OperationID)
+
+	var pathParams, queryParams []parameter
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "path":
+			pathParams = append(pathParams, param)
+		case "query":
+			queryParams = append(queryParams, param)
+		default:
+			return fmt.Errorf("%s: unsupported parameter location %q", op.OperationID, param.In)
+		}
+	}
+
+	if len(queryParams) > 0 {
+		g.printf("// %sParams are the query parameters of %s.\n", name, name)
+		g.printf("type %sParams struct {\n", name)
+		for _, param := range queryParams {
+			g.printf("%s %s\n", goName(param.Name), queryType(param.Schema))
+		}
+		g.printf("}\n\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, param := range pathParams {
+		args = append(args, param.Name+" string")
+	}
+
+	var body, multipart string
+	if op.RequestBody != nil {
+		for mediaType, media := range op.RequestBody.Content {
+			switch mediaType {
+			case "application/json":
+				body = "body"
+				args = append(args, "body "+goType(media.Schema))
+			case "multipart/form-data":
+				multipart = media.Schema.Properties.names[0]
+				args = append(args, "filename string", "file io.Reader")
+			default:
+				return fmt.Errorf("%s: unsupported request body %q", op.OperationID, mediaType)
+			}
+		}
+	}
+	if len(queryParams) > 0 {
+		args = append(args, fmt.Sprintf("params *%sParams", name))
+	}
+
+	result, text := responseType(op)
+	returns, zero := "error", ""
+	if result != "" {
+		returns = "(" + result + ", error)"
+		zero = "nil, "
+		if text {
+			zero = `"", `
+		}
+	}
+
+	g.printf("// %s calls %s %s. %s.\n", name, op.method, op.path, op.Summary)
+	g.printf("func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), returns)
+	g.printf("path := %s\n", pathExpr(op.path))
+
+	query := "nil"
+	if len(queryParams) > 0 {
+		query = "query"
+		g.printf("query := url.Values{}\nif params != nil {\n")
+		for _, param := range queryParams {
+			field := "params." + goName(param.Name)
+			switch queryType(param.Schema) {
+			case "int":
+				g.printf("if %s != 0 {\nquery.Set(%q, strconv.Itoa(%s))\n}\n", field, param.Name, field)
+			default:
+				g.printf("if %s != \"\" {\nquery.Set(%q, %s)\n}\n", field, param.Name, field)
+			}
+		}
+		g.printf("}\n")
+	}
+
+	out := "nil"
+	if result != "" {
+		if strings.HasPrefix(result, "*") {
+			g.printf("var result %s\n", strings.TrimPrefix(result, "*"))
+		} else {
+			g.printf("var result %s\n", result)
+		}
+		out = "&result"
+	}
+
+	call := fmt.Sprintf("c.do(ctx, http.Method%s, path, %s, %s, %s)", methodName(op.method), query, orNil(body), out)
+	if multipart != "" {
+		call = fmt.Sprintf("c.upload(ctx, path, %q, filename, file, %s)", multipart, out)
+	}
+
+	switch {
+	case result == "":
+		g.printf("return %s\n", call)
+	default:
+		g.printf("if err := %s; err != nil {\nreturn %serr\n}\n", call, zero)
+		if strings.HasPrefix(result, "*") {
+			g.printf("return &result, nil\n")
+		} else {
+			g.printf("return result, nil\n")
+		}
+	}
+	g.printf("}\n\n")
+	return nil
+}
+
+// responseType returns the Go type of the successful response of op, empty when it
+// has no body, and whether the body is plain text.
+func responseType(op *operation) (string, bool) {
+	for _, code := range sortedKeys(op.Responses) {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		for mediaType, media := range op.Responses[code].Content {
+			if mediaType == "text/plain" {
+				return "string", true
+			}
+			if media.Schema.Format == "binary" {
+				return "[]byte", false
+			}
+			typ := goType(media.Schema)
+			if media.Schema.Ref != "" {
+				typ = "*" + typ
+			}
+			return typ, false
+		}
+		return "", false
+	}
+	return "", false
+}
+
+func goType(s *schema) string {
+	if s.Ref != "" {
+		return s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+	}
+
+	var typ string
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			typ = "time.Time"
+		case "decimal":
+			typ = "decimal.Decimal"
+		default:
+			typ = "string"
+		}
+	case "integer":
+		typ = "int"
+	case "number":
+		typ = "float64"
+	case "boolean":
+		typ = "bool"
+	case "array":
+		return "[]" + goType(s.Items)
+	case "object":
+		switch additional := s.AdditionalProperties.(type) {
+		case map[string]any:
+			raw, _ := json.Marshal(additional)
+			var item schema
+			_ = json.Unmarshal(raw, &item)
+			return "map[string]" + goType(&item)
+		default:
+			return "map[string]any"
+		}
+	default:
+		typ = "any"
+	}
+	if s.Nullable {
+		return "*" + typ
+	}
+	return typ
+}
+
+func queryType(s *schema) string {
+	if s.Type == "integer" {
+		return "int"
+	}
+	return "string"
+}
+
+// pathExpr turns "/cxf/category/{id}/merge" into a Go expression escaping the path
+// parameters.
+func pathExpr(path string) string {
+	var parts []string
+	for path != "" {
+		start := strings.Index(path, "{")
+		if start < 0 {
+			parts = append(parts, fmt.Sprintf("%q", path))
+			break
+		}
+		end := strings.Index(path, "}")
+		parts = append(parts, fmt.Sprintf("%q", path[:start]), "url.PathEscape("+path[start+1:end]+")")
+		path = path[end+1:]
+	}
+	return strings.Join(parts, " + ")
+}
+
+func methodName(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
+
+// initialisms are written in capitals in Go names, like ParentID.
+var initialisms = map[string]string{"id": "ID", "url": "URL", "api": "API", "openapi": "OpenAPI"}
+
+func goName(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' }) {
+		if initialism, ok := initialisms[strings.ToLower(word)]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		b.WriteString(upperFirst(word))
+	}
+	return b.String()
+}
+
+func upperFirst(s string) string {
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func lowerFirst(s string) string {
+	runes := []rune(s)
+	if len(runes) > 1 && unicode.IsUpper(runes[1]) {
+		return s
+	}
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+func orNil(s string) string {
+	if s == "" {
+		return "nil"
+	}
+	return s
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
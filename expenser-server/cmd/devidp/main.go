@@ -0,0 +1,34 @@
+// Command devidp is a stand-in OpenID provider for developing and trying out the OIDC
+// login without a real identity provider. It signs in whoever types an email address,
+// so never expose it.
+//
+//	go run ./cmd/devidp -addr localhost:9000
+//
+// and start the server with OIDC_ISSUER=http://localhost:9000 OIDC_CLIENT_ID=expenser.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/keertirajmalik/expenser/expenser-server/oidc/oidctest"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:9000", "listen address")
+	issuer := flag.String("issuer", "", "issuer URL, http://<addr> by default")
+	clientID := flag.String("client-id", "expenser", "the only client accepted")
+	flag.Parse()
+
+	if *issuer == "" {
+		*issuer = "http://" + *addr
+	}
+	provider, err := oidctest.NewProvider(*issuer, *clientID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("devidp %s serving client %q on %s", *issuer, *clientID, *addr)
+	log.Fatal(http.ListenAndServe(*addr, provider))
+}
@@ -0,0 +1,301 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/shopspring/decimal"
+)
+
+// FileEnv names the environment variable pointing at the optional config file. The
+// -config flag takes precedence over it.
+const FileEnv = "EXPENSER_CONFIG"
+
+const redacted = "[REDACTED]"
+
+type Config struct {
+	Port        int
+	JWTSecret   string
+	JWT         JWT
+	TokenTTL    time.Duration
+	CORSOrigins []string
+	Database    Database
+	HTTP        HTTP
+	OIDC        OIDC
+	Tax         Tax
+	Log         Log
+}
+
+// JWT holds the PEM files of the asymmetric keys of the login tokens. Without a signing
+// key the tokens are signed with HS256 and JWTSecret.
+type JWT struct {
+	SigningKey       string
+	VerificationKeys []string
+}
+
+// OIDC configures logging in with an OpenID provider. It is off while Issuer is empty.
+type OIDC struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// UsernameClaim and NameClaim map ID token claims to the username and name of the
+	// users created on first login.
+	UsernameClaim string
+	NameClaim     string
+	// LinkExisting links a first login to the local account of the same username
+	// instead of refusing it.
+	LinkExisting bool
+	// PostLoginURL is where the browser is sent after logging in, with the access token
+	// in the fragment. The callback answers with JSON when it is empty.
+	PostLoginURL string
+}
+
+// Enabled reports whether OIDC login is configured.
+func (o OIDC) Enabled() bool {
+	return o.Issuer != ""
+}
+
+type Database struct {
+	Host              string
+	Port              int
+	Name              string
+	Username          string
+	Password          string
+	Schema            string
+	SSLMode           string
+	MaxConns          int
+	MinConns          int
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+}
+
+type HTTP struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+}
+
+// Tax holds the yearly caps of the deductions per tax section, as section=amount pairs
+// like 80C=150000.
+type Tax struct {
+	DeductionCaps []string
+}
+
+// Caps returns the cap per tax section. Sections are upper-cased without spaces, the
+// way tax sections of categories are stored.
+func (t Tax) Caps() (map[string]decimal.Decimal, error) {
+	caps := map[string]decimal.Decimal{}
+	for _, pair := range t.DeductionCaps {
+		section, amount, ok := strings.Cut(pair, "=")
+		section = strings.ToUpper(strings.ReplaceAll(section, " ", ""))
+		if !ok || section == "" {
+			return nil, fmt.Errorf("%q is not like 80C=150000", pair)
+		}
+		limit, err := decimal.NewFromString(strings.TrimSpace(amount))
+		if err != nil || !limit.IsPositive() {
+			return nil, fmt.Errorf("%q: the cap must be a positive amount", pair)
+		}
+		caps[section] = limit
+	}
+	return caps, nil
+}
+
+type Log struct {
+	Level  string
+	Format string
+}
+
+// ConnString returns the pgx connection string of the database.
+func (d Database) ConnString() string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(d.Username, d.Password),
+		Host:   fmt.Sprintf("%s:%d", d.Host, d.Port),
+		Path:   d.Name,
+	}
+	query := url.Values{}
+	query.Set("sslmode", d.SSLMode)
+	if d.Schema != "" {
+		query.Set("search_path", d.Schema)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// Load builds the configuration from, in increasing precedence, the defaults, the
+// optional config file, the environment and the command line flags in args.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	var c Config
+	for _, s := range settings {
+		if err := s.apply(&c, s.def); err != nil {
+			return Config{}, fmt.Errorf("default of %s: %w", s.key, err)
+		}
+	}
+
+	flags := flag.NewFlagSet("expenser", flag.ContinueOnError)
+	configFile := flags.String("config", getenv(FileEnv), "path of a TOML config file")
+	flagValues := map[string]*string{}
+	for _, s := range settings {
+		flagValues[s.key] = flags.String(s.flagName(), "", fmt.Sprintf("%s (env %s, default %q)", s.usage, s.env, s.def))
+	}
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if *configFile != "" {
+		if err := c.loadFile(*configFile); err != nil {
+			return Config{}, err
+		}
+	}
+
+	var errs []error
+	for _, s := range settings {
+		if value := getenv(s.env); value != "" {
+			if err := s.apply(&c, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.env, err))
+			}
+		}
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flagName() == f.Name {
+				if err := s.apply(&c, *flagValues[s.key]); err != nil {
+					errs = append(errs, fmt.Errorf("-%s: %w", f.Name, err))
+				}
+			}
+		}
+	})
+	if len(errs) > 0 {
+		return Config{}, errors.Join(errs...)
+	}
+
+	if err := c.Validate(); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	values, err := parseTOML(string(content))
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	var errs []error
+	for key, value := range values {
+		s, ok := settingByKey(key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("config file %s: unknown setting %q", path, key))
+			continue
+		}
+		apply := func() error { return s.apply(c, value.value) }
+		if value.array {
+			apply = func() error { return s.applyItems(c, value.items) }
+		}
+		if err := apply(); err != nil {
+			errs = append(errs, fmt.Errorf("config file %s: %s: %w", path, key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid setting at once.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Port > 0 && c.Port < 65536, "port must be between 1 and 65535, got %d", c.Port)
+	check(c.JWTSecret != "" || c.JWT.SigningKey != "", "jwt_secret or jwt.signing_key is required")
+	check(c.TokenTTL > 0, "token_ttl must be positive")
+	check(len(c.CORSOrigins) > 0, "cors_origins must not be empty")
+	for _, origin := range c.CORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		check(err == nil && u.Scheme != "" && u.Host != "", "cors_origins: %q is not an origin like https://example.com", origin)
+	}
+
+	if c.OIDC.Enabled() {
+		u, err := url.Parse(c.OIDC.Issuer)
+		check(err == nil && u.Scheme != "" && u.Host != "", "oidc.issuer: %q is not a URL", c.OIDC.Issuer)
+		check(c.OIDC.ClientID != "", "oidc.client_id is required with oidc.issuer")
+		u, err = url.Parse(c.OIDC.RedirectURL)
+		check(err == nil && u.Scheme != "" && u.Host != "", "oidc.redirect_url must be the absolute URL of /cxf/v1/oidc/callback")
+		check(slices.Contains(c.OIDC.Scopes, "openid"), "oidc.scopes must include openid")
+		check(c.OIDC.UsernameClaim != "", "oidc.username_claim is required")
+		// Only verified email addresses are safe to match with local usernames, other
+		// claims like preferred_username can be set to anything at the provider.
+		check(!c.OIDC.LinkExisting || c.OIDC.UsernameClaim == "email", "oidc.link_existing needs oidc.username_claim = \"email\", got %q", c.OIDC.UsernameClaim)
+	}
+
+	check(c.Database.Host != "", "database.host is required")
+	check(c.Database.Port > 0 && c.Database.Port < 65536, "database.port must be between 1 and 65535, got %d", c.Database.Port)
+	check(c.Database.Name != "", "database.name is required")
+	check(c.Database.Username != "", "database.username is required")
+	check(c.Database.MaxConns > 0, "database.max_conns must be positive")
+	check(c.Database.MinConns >= 0 && c.Database.MinConns <= c.Database.MaxConns, "database.min_conns must be between 0 and database.max_conns")
+	check(c.Database.MaxConnLifetime > 0, "database.max_conn_lifetime must be positive")
+	check(c.Database.MaxConnIdleTime > 0, "database.max_conn_idle_time must be positive")
+	check(c.Database.HealthCheckPeriod > 0, "database.health_check_period must be positive")
+
+	check(c.HTTP.ReadTimeout > 0, "http.read_timeout must be positive")
+	check(c.HTTP.ReadHeaderTimeout > 0, "http.read_header_timeout must be positive")
+	check(c.HTTP.WriteTimeout > 0, "http.write_timeout must be positive")
+	check(c.HTTP.IdleTimeout > 0, "http.idle_timeout must be positive")
+	check(c.HTTP.ShutdownTimeout > 0, "http.shutdown_timeout must be positive")
+
+	_, err := c.Tax.Caps()
+	check(err == nil, "tax.deduction_caps: %v", err)
+
+	_, err = logger.ParseLevel(c.Log.Level)
+	check(err == nil, "log.level: %v", err)
+	check(c.Log.Format == logger.FormatJSON || c.Log.Format == logger.FormatText, "log.format must be %s or %s", logger.FormatJSON, logger.FormatText)
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns every setting with secrets masked, for logging at startup.
+func (c Config) Redacted() map[string]any {
+	dump := map[string]any{}
+	for _, s := range settings {
+		value := s.format(&c)
+		if s.secret && value != "" {
+			value = redacted
+		}
+		dump[s.key] = value
+	}
+	return dump
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateOIDCLinkExisting(t *testing.T) {
+	tests := []struct {
+		name          string
+		usernameClaim string
+		linkExisting  string
+		wantErr       bool
+	}{
+		{"link by email", "email", "true", false},
+		{"link by another claim", "preferred_username", "true", true},
+		{"another claim without linking", "preferred_username", "false", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{
+				"JWT_SECRET":          "secret",
+				"DB_DATABASE":         "expenser",
+				"DB_USERNAME":         "expenser",
+				"OIDC_ISSUER":         "https://idp.example.com",
+				"OIDC_CLIENT_ID":      "expenser",
+				"OIDC_REDIRECT_URL":   "https://expenser.example.com/cxf/v1/oidc/callback",
+				"OIDC_USERNAME_CLAIM": tt.usernameClaim,
+				"OIDC_LINK_EXISTING":  tt.linkExisting,
+			}
+			_, err := Load(nil, func(key string) string { return env[key] })
+			if tt.wantErr != (err != nil && strings.Contains(err.Error(), "oidc.link_existing")) {
+				t.Errorf("Load error = %v, want a link_existing error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Load: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting describes one configuration value: its key in the config file, the
+// environment variable and flag overriding it, and the field it is stored in.
+type setting struct {
+	key    string
+	env    string
+	def    string
+	usage  string
+	secret bool
+	field  func(c *Config) any
+}
+
+var settings = []setting{
+	{key: "port", env: "PORT", def: "8080", usage: "HTTP port", field: func(c *Config) any { return &c.Port }},
+	{key: "jwt_secret", env: "JWT_SECRET", usage: "HS256 secret signing the access tokens, or accepted alongside jwt.signing_key", secret: true, field: func(c *Config) any { return &c.JWTSecret }},
+	{key: "jwt.signing_key", env: "JWT_SIGNING_KEY", usage: "PEM file of the Ed25519 or RSA key signing the access tokens, HS256 with jwt_secret when empty", field: func(c *Config) any { return &c.JWT.SigningKey }},
+	{key: "jwt.verification_keys", env: "JWT_VERIFICATION_KEYS", usage: "comma separated PEM files of further keys whose tokens are accepted", field: func(c *Config) any { return &c.JWT.VerificationKeys }},
+	{key: "token_ttl", env: "TOKEN_TTL", def: "1h", usage: "lifetime of access tokens", field: func(c *Config) any { return &c.TokenTTL }},
+	{key: "cors_origins", env: "CORS_ORIGIN", def: "*", usage: "comma separated origins allowed to call the API", field: func(c *Config) any { return &c.CORSOrigins }},
+
+	{key: "database.host", env: "DB_HOST", def: "localhost", usage: "database host", field: func(c *Config) any { return &c.Database.Host }},
+	{key: "database.port", env: "DB_PORT", def: "5432", usage: "database port", field: func(c *Config) any { return &c.Database.Port }},
+	{key: "database.name", env: "DB_DATABASE", usage: "database name", field: func(c *Config) any { return &c.Database.Name }},
+	{key: "database.username", env: "DB_USERNAME", usage: "database user", field: func(c *Config) any { return &c.Database.Username }},
+	{key: "database.password", env: "DB_PASSWORD", usage: "database password", secret: true, field: func(c *Config) any { return &c.Database.Password }},
+	{key: "database.schema", env: "DB_SCHEMA", def: "public", usage: "database schema", field: func(c *Config) any { return &c.Database.Schema }},
+	{key: "database.sslmode", env: "DB_SSLMODE", def: "disable", usage: "libpq sslmode", field: func(c *Config) any { return &c.Database.SSLMode }},
+	{key: "database.max_conns", env: "DB_MAX_CONNS", def: "10", usage: "maximum connections in the pool", field: func(c *Config) any { return &c.Database.MaxConns }},
+	{key: "database.min_conns", env: "DB_MIN_CONNS", def: "0", usage: "connections kept open when idle", field: func(c *Config) any { return &c.Database.MinConns }},
+	{key: "database.max_conn_lifetime", env: "DB_MAX_CONN_LIFETIME", def: "1h", usage: "age after which a connection is closed", field: func(c *Config) any { return &c.Database.MaxConnLifetime }},
+	{key: "database.max_conn_idle_time", env: "DB_MAX_CONN_IDLE_TIME", def: "30m", usage: "idle time after which a connection is closed", field: func(c *Config) any { return &c.Database.MaxConnIdleTime }},
+	{key: "database.health_check_period", env: "DB_HEALTH_CHECK_PERIOD", def: "1m", usage: "interval of the pool health checks", field: func(c *Config) any { return &c.Database.HealthCheckPeriod }},
+
+	{key: "http.read_timeout", env: "HTTP_READ_TIMEOUT", def: "10s", usage: "maximum duration for reading a request", field: func(c *Config) any { return &c.HTTP.ReadTimeout }},
+	{key: "http.read_header_timeout", env: "HTTP_READ_HEADER_TIMEOUT", def: "5s", usage: "maximum duration for reading request headers", field: func(c *Config) any { return &c.HTTP.ReadHeaderTimeout }},
+	{key: "http.write_timeout", env: "HTTP_WRITE_TIMEOUT", def: "10s", usage: "maximum duration for writing a response", field: func(c *Config) any { return &c.HTTP.WriteTimeout }},
+	{key: "http.idle_timeout", env: "HTTP_IDLE_TIMEOUT", def: "1m", usage: "keep-alive idle timeout", field: func(c *Config) any { return &c.HTTP.IdleTimeout }},
+	{key: "http.shutdown_timeout", env: "HTTP_SHUTDOWN_TIMEOUT", def: "5s", usage: "time given to in-flight requests on shutdown", field: func(c *Config) any { return &c.HTTP.ShutdownTimeout }},
+
+	{key: "oidc.issuer", env: "OIDC_ISSUER", usage: "issuer URL of the OpenID provider, OIDC login is off when empty", field: func(c *Config) any { return &c.OIDC.Issuer }},
+	{key: "oidc.client_id", env: "OIDC_CLIENT_ID", usage: "client id registered at the OpenID provider", field: func(c *Config) any { return &c.OIDC.ClientID }},
+	{key: "oidc.client_secret", env: "OIDC_CLIENT_SECRET", usage: "client secret, empty for public clients", secret: true, field: func(c *Config) any { return &c.OIDC.ClientSecret }},
+	{key: "oidc.redirect_url", env: "OIDC_REDIRECT_URL", usage: "absolute URL of /cxf/v1/oidc/callback as registered at the provider", field: func(c *Config) any { return &c.OIDC.RedirectURL }},
+	{key: "oidc.scopes", env: "OIDC_SCOPES", def: "openid,email,profile", usage: "comma separated scopes to request", field: func(c *Config) any { return &c.OIDC.Scopes }},
+	{key: "oidc.username_claim", env: "OIDC_USERNAME_CLAIM", def: "email", usage: "ID token claim used as the username", field: func(c *Config) any { return &c.OIDC.UsernameClaim }},
+	{key: "oidc.name_claim", env: "OIDC_NAME_CLAIM", def: "name", usage: "ID token claim used as the name", field: func(c *Config) any { return &c.OIDC.NameClaim }},
+	{key: "oidc.link_existing", env: "OIDC_LINK_EXISTING", def: "false", usage: "link first logins to the local account named by their verified email, needs username_claim email", field: func(c *Config) any { return &c.OIDC.LinkExisting }},
+	{key: "oidc.post_login_url", env: "OIDC_POST_LOGIN_URL", usage: "UI URL receiving the access token in its fragment after login", field: func(c *Config) any { return &c.OIDC.PostLoginURL }},
+
+	{key: "tax.deduction_caps", env: "TAX_DEDUCTION_CAPS", def: "80C=150000,80CCD(1B)=50000,80D=25000", usage: "comma separated yearly caps of the deductions per tax section, like 80C=150000", field: func(c *Config) any { return &c.Tax.DeductionCaps }},
+
+	{key: "log.level", env: "LOG_LEVEL", def: "info", usage: "minimum log level: debug, info, warn or error", field: func(c *Config) any { return &c.Log.Level }},
+	{key: "log.format", env: "LOG_FORMAT", def: "json", usage: "log format: json or text", field: func(c *Config) any { return &c.Log.Format }},
+}
+
+func settingByKey(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+// flagName derives the flag from the key, database.max_conns becomes -database.max-conns.
+func (s setting) flagName() string {
+	return strings.ReplaceAll(s.key, "_", "-")
+}
+
+func (s setting) apply(c *Config, value string) error {
+	switch field := s.field(c).(type) {
+	case *string:
+		*field = value
+	case *int:
+		if value == "" {
+			*field = 0
+			return nil
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		*field = parsed
+	case *time.Duration:
+		if value == "" {
+			*field = 0
+			return nil
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		*field = parsed
+	case *bool:
+		if value == "" {
+			*field = false
+			return nil
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		*field = parsed
+	case *[]string:
+		*field = splitList(value)
+	default:
+		panic(fmt.Sprintf("config: unsupported type %T for %s", field, s.key))
+	}
+	return nil
+}
+
+// applyItems sets a list setting to the items of a config file array, taken as they
+// are so items may contain commas.
+func (s setting) applyItems(c *Config, items []string) error {
+	field, ok := s.field(c).(*[]string)
+	if !ok {
+		return fmt.Errorf("expected a single value, got an array")
+	}
+	*field = nil
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			*field = append(*field, item)
+		}
+	}
+	return nil
+}
+
+func (s setting) format(c *Config) string {
+	switch field := s.field(c).(type) {
+	case *string:
+		return *field
+	case *int:
+		return strconv.Itoa(*field)
+	case *time.Duration:
+		return field.String()
+	case *bool:
+		return strconv.FormatBool(*field)
+	case *[]string:
+		return strings.Join(*field, ",")
+	default:
+		panic(fmt.Sprintf("config: unsupported type %T for %s", field, s.key))
+	}
+}
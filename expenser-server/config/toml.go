@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tomlValue is a value of the config file: a single value in the form the settings
+// parse from the environment, or the items of an array.
+type tomlValue struct {
+	value string
+	items []string
+	array bool
+}
+
+// parseTOML reads the subset of TOML the config file needs: [tables], key = value
+// pairs with string, integer, boolean or single line array values, and # comments.
+// The returned keys are dotted with their table.
+func parseTOML(content string) (map[string]tomlValue, error) {
+	values := map[string]tomlValue{}
+	table := ""
+
+	for number, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(stripComment(line))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated table header", number+1)
+			}
+			table = strings.TrimSpace(line[1 : len(line)-1])
+			if table == "" {
+				return nil, fmt.Errorf("line %d: empty table name", number+1)
+			}
+			continue
+		}
+
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", number+1)
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("line %d: missing key", number+1)
+		}
+		if table != "" {
+			key = table + "." + key
+		}
+		if _, exists := values[key]; exists {
+			return nil, fmt.Errorf("line %d: %s defined twice", number+1, key)
+		}
+
+		raw = strings.TrimSpace(raw)
+		if strings.HasPrefix(raw, "[") {
+			items, err := parseTOMLArray(raw)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", number+1, err)
+			}
+			values[key] = tomlValue{items: items, array: true}
+			continue
+		}
+		value, err := parseTOMLValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number+1, err)
+		}
+		values[key] = tomlValue{value: value}
+	}
+
+	return values, nil
+}
+
+func parseTOMLValue(raw string) (string, error) {
+	switch {
+	case raw == "":
+		return "", fmt.Errorf("missing value")
+	case strings.HasPrefix(raw, `"`):
+		return strconv.Unquote(raw)
+	case strings.HasPrefix(raw, "'"):
+		if len(raw) < 2 || !strings.HasSuffix(raw, "'") {
+			return "", fmt.Errorf("unterminated string %s", raw)
+		}
+		return raw[1 : len(raw)-1], nil
+	case strings.HasPrefix(raw, "["):
+		return "", fmt.Errorf("nested array %s", raw)
+	case raw == "true" || raw == "false":
+		return raw, nil
+	default:
+		if _, err := strconv.ParseInt(strings.ReplaceAll(raw, "_", ""), 10, 64); err != nil {
+			return "", fmt.Errorf("unsupported value %s", raw)
+		}
+		return strings.ReplaceAll(raw, "_", ""), nil
+	}
+}
+
+// parseTOMLArray parses the items of an array like ["a,b", 'c']. Commas inside quoted
+// items are part of the item.
+func parseTOMLArray(raw string) ([]string, error) {
+	if !strings.HasSuffix(raw, "]") {
+		return nil, fmt.Errorf("unterminated array %s", raw)
+	}
+
+	var items []string
+	add := func(item string) error {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			return nil
+		}
+		value, err := parseTOMLValue(item)
+		if err != nil {
+			return err
+		}
+		items = append(items, value)
+		return nil
+	}
+
+	inner := raw[1 : len(raw)-1]
+	start := 0
+	var quote rune
+	escaped := false
+	for i, c := range inner {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && c == '\\':
+			escaped = true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == ',':
+			if err := add(inner[start:i]); err != nil {
+				return nil, err
+			}
+			start = i + 1
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated string in array %s", raw)
+	}
+	if err := add(inner[start:]); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// stripComment drops a # comment that isn't inside a string.
+func stripComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, c := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && c == '\\':
+			escaped = true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]tomlValue
+	}{
+		{
+			name:    "keys and tables",
+			content: "port = 8080\n\n[database]\nhost = \"db\"\nmax_conns = 1_000\n\n[http]\nenabled = true\n",
+			want: map[string]tomlValue{
+				"port":               {value: "8080"},
+				"database.host":      {value: "db"},
+				"database.max_conns": {value: "1000"},
+				"http.enabled":       {value: "true"},
+			},
+		},
+		{
+			name:    "comments",
+			content: "# settings\nname = \"a # b\" # trailing\nliteral = 'c # d'\n",
+			want: map[string]tomlValue{
+				"name":    {value: "a # b"},
+				"literal": {value: "c # d"},
+			},
+		},
+		{
+			name:    "escaped quotes",
+			content: `name = "say \"hi\", # not a comment"`,
+			want: map[string]tomlValue{
+				"name": {value: `say "hi", # not a comment`},
+			},
+		},
+		{
+			name:    "arrays",
+			content: "origins = [\"a,b\", 'c, d', \"e\\\",f\"] # three\nempty = []\ntrailing = [\"x\", ]\n",
+			want: map[string]tomlValue{
+				"origins":  {items: []string{"a,b", "c, d", `e",f`}, array: true},
+				"empty":    {array: true},
+				"trailing": {items: []string{"x"}, array: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTOML(tt.content)
+			if err != nil {
+				t.Fatalf("parseTOML: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTOML = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"duplicate key", "port = 1\nport = 2", "line 2: port defined twice"},
+		{"duplicate key in table", "[database]\nhost = \"a\"\n[http]\n[database]\nhost = \"b\"", "line 5: database.host defined twice"},
+		{"unterminated table", "[database", "line 1: unterminated table header"},
+		{"empty table", "[ ]", "line 1: empty table name"},
+		{"missing value", "port =", "line 1: missing value"},
+		{"missing key", "= 1", "line 1: missing key"},
+		{"no assignment", "port", "line 1: expected key = value"},
+		{"unterminated array", "origins = [\"a\"", "unterminated array"},
+		{"unterminated string in array", "origins = [\"a, b]", "unterminated string in array"},
+		{"nested array", "origins = [[\"a\"]]", "nested array"},
+		{"unsupported value", "port = eighty", "unsupported value eighty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTOML(tt.content)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseTOML error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFileArrays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expenser.toml")
+	content := "cors_origins = [\"https://a.example\", \"https://b.example,c\"]\n[jwt]\nsigning_key = \"key.pem\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var c Config
+	if err := c.loadFile(path); err != nil {
+		t.Fatalf("loadFile: %v", err)
+	}
+	want := []string{"https://a.example", "https://b.example,c"}
+	if !reflect.DeepEqual(c.CORSOrigins, want) {
+		t.Errorf("cors_origins = %q, want %q", c.CORSOrigins, want)
+	}
+
+	if err := os.WriteFile(path, []byte("port = [1, 2]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.loadFile(path); err == nil || !strings.Contains(err.Error(), "got an array") {
+		t.Errorf("loadFile error = %v, want an array error", err)
+	}
+}
@@ -3,14 +3,15 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
-	_ "github.com/joho/godotenv/autoload"
+	"github.com/keertirajmalik/expenser/expenser-server/config"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/keertirajmalik/expenser/expenser-server/metrics"
 )
 
 // Service represents a service that interacts with a database.
@@ -19,7 +20,13 @@ type Service interface {
 
 	// Health returns a map of health status information.
 	// The keys and values in the map are service-specific.
-	Health() map[string]string
+	Health(ctx context.Context) map[string]string
+
+	// Ping checks that the database can be reached.
+	Ping(ctx context.Context) error
+
+	// MigrationVersion returns the newest migration applied to the database.
+	MigrationVersion(ctx context.Context) (int64, error)
 
 	// Close terminates the database connection.
 	// It returns an error if the connection cannot be closed.
@@ -27,35 +34,38 @@ type Service interface {
 }
 
 type service struct {
-	DB *pgxpool.Pool
+	DB   *pgxpool.Pool
+	name string
 }
 
-var (
-	database   = os.Getenv("DB_DATABASE")
-	password   = os.Getenv("DB_PASSWORD")
-	username   = os.Getenv("DB_USERNAME")
-	port       = os.Getenv("DB_PORT")
-	host       = os.Getenv("DB_HOST")
-	schema     = os.Getenv("DB_SCHEMA")
-	dbInstance *service
-)
+var dbInstance *service
 
-func New() Service {
+func New(cfg config.Database) Service {
 	// Reuse Connection
 	if dbInstance != nil {
 		return dbInstance
 	}
 
-	connStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable&search_path=%s", username, password, host, port, database, schema)
-	db, err := pgxpool.New(context.Background(), connStr)
+	poolConfig, err := pgxpool.ParseConfig(cfg.ConnString())
 	if err != nil {
-		log.Fatal(err)
+		logger.Error(context.Background(), "invalid database configuration", map[string]any{"error": err})
+		os.Exit(1)
 	}
+	poolConfig.MaxConns = int32(cfg.MaxConns)
+	poolConfig.MinConns = int32(cfg.MinConns)
+	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	poolConfig.ConnConfig.Tracer = metrics.QueryTracer{}
+
+	db, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
-		log.Fatal(err)
+		logger.Error(context.Background(), "failed to create the database pool", map[string]any{"error": err})
+		os.Exit(1)
 	}
 	dbInstance = &service{
-		DB: db,
+		DB:   db,
+		name: cfg.Name,
 	}
 
 	return dbInstance
@@ -63,15 +73,16 @@ func New() Service {
 
 func (s *service) GetConnection() *pgxpool.Pool {
     if dbInstance == nil {
-        log.Fatal("Database not initialized")
+        logger.Error(context.Background(), "database not initialized")
+        os.Exit(1)
     }
     return dbInstance.DB
 }
 
 // Health checks the health of the database connection by pinging the database.
 // It returns a map with keys indicating various health statistics.
-func (s *service) Health() map[string]string {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+func (s *service) Health(ctx context.Context) map[string]string {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
 	defer cancel()
 
 	stats := make(map[string]string)
@@ -81,7 +92,7 @@ func (s *service) Health() map[string]string {
 	if err != nil {
 		stats["status"] = "down"
 		stats["error"] = fmt.Sprintf("db down: %v", err)
-		log.Fatalf("db down: %v", err) // Log the error and terminate the program
+		logger.Error(ctx, "db down", map[string]any{"error": err})
 		return stats
 	}
 
@@ -112,12 +123,16 @@ func (s *service) Health() map[string]string {
 	return stats
 }
 
+func (s *service) Ping(ctx context.Context) error {
+	return s.DB.Ping(ctx)
+}
+
 // Close closes the database connection.
 // It logs a message indicating the disconnection from the specific database.
 // If the connection is succesBLUEPRINT_sfully closed, it returns nil.
 // If an error occurs while closing the connection, it returns the error.
 func (s *service) Close() {
-	log.Printf("Disconnected from database: %s", database)
+	logger.Info(context.Background(), "disconnected from database", map[string]any{"database": s.name})
 	s.DB.Close()
 }
 
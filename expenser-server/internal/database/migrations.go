@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed schema/*.sql
+var schemaFS embed.FS
+
+// ExpectedMigrationVersion returns the version of the newest goose migration shipped
+// with this build.
+func ExpectedMigrationVersion() (int64, error) {
+	files, err := fs.Glob(schemaFS, "schema/*.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest int64
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "schema/")
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			return 0, fmt.Errorf("migration %s has no version prefix", name)
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s has an invalid version: %w", name, err)
+		}
+		latest = max(latest, version)
+	}
+	return latest, nil
+}
+
+// MigrationVersion returns the newest migration goose has applied to the database.
+// A version counts as applied when its most recent goose_db_version row says so.
+func (s *service) MigrationVersion(ctx context.Context) (int64, error) {
+	var version int64
+	err := s.DB.QueryRow(ctx, `
+		SELECT COALESCE(MAX(version_id), 0)
+		FROM (
+			SELECT DISTINCT ON (version_id) version_id, is_applied
+			FROM goose_db_version
+			ORDER BY version_id, id DESC
+		) versions
+		WHERE is_applied`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migration version: %w", err)
+	}
+	return version, nil
+}
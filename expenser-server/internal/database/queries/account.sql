@@ -0,0 +1,29 @@
+-- name: DeleteUserGoals :exec
+DELETE FROM goals WHERE user_id = $1;
+
+-- name: DeleteUserTransactions :exec
+DELETE FROM transactions WHERE user_id = $1;
+
+-- name: DeleteUserIncomes :exec
+DELETE FROM incomes WHERE user_id = $1;
+
+-- name: DeleteUserInvestments :exec
+DELETE FROM investments WHERE user_id = $1;
+
+-- name: DetachUserCategories :exec
+UPDATE categories SET parent_id = NULL
+WHERE user_id = $1 AND parent_id IS NOT NULL;
+
+-- name: DeleteUserCategories :exec
+DELETE FROM categories WHERE user_id = $1;
+
+-- name: DeleteUser :execresult
+DELETE FROM users WHERE id = $1;
+
+-- name: RevokeUser :exec
+INSERT INTO revoked_users(user_id)
+VALUES ($1)
+ON CONFLICT (user_id) DO NOTHING;
+
+-- name: IsUserRevoked :one
+SELECT EXISTS(SELECT 1 FROM revoked_users WHERE user_id = $1);
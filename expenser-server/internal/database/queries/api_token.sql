@@ -0,0 +1,22 @@
+-- name: CreateAPIToken :one
+INSERT INTO api_tokens(id, user_id, name, scope, token_hash, token_prefix, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetAPITokens :many
+SELECT * FROM api_tokens
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: GetAPITokenByHash :one
+SELECT api_tokens.*, users.role FROM api_tokens
+JOIN users ON users.id = api_tokens.user_id
+WHERE api_tokens.token_hash = $1;
+
+-- name: TouchAPIToken :exec
+UPDATE api_tokens
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute');
+
+-- name: DeleteAPIToken :execresult
+DELETE FROM api_tokens WHERE id = $1 AND user_id = $2;
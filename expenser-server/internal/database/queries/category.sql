@@ -1,7 +1,7 @@
 -- name: CreateCategory :one
 WITH inserted AS (
-    INSERT INTO categories(id, name,type, description, user_id)
-    VALUES ($1, $2, $3, $4, $5)
+    INSERT INTO categories(id, name,type, description, user_id, parent_id, tax_section, tax_exempt)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
     RETURNING *
 )
 SELECT
@@ -9,6 +9,9 @@ SELECT
     inserted.name,
     inserted.description,
     inserted.type,
+    inserted.parent_id,
+    inserted.tax_section,
+    inserted.tax_exempt,
     users.name AS user,
     inserted.created_at,
     inserted.updated_at
@@ -21,6 +24,9 @@ SELECT
     categories.name,
     categories.description,
     categories.type,
+    categories.parent_id,
+    categories.tax_section,
+    categories.tax_exempt,
     users.name AS user,
     categories.created_at,
     categories.updated_at
@@ -38,6 +44,9 @@ SELECT
     categories.name,
     categories.description,
     categories.type,
+    categories.parent_id,
+    categories.tax_section,
+    categories.tax_exempt,
     users.name AS user,
     categories.created_at,
     categories.updated_at
@@ -51,7 +60,10 @@ WITH updated AS (
     UPDATE categories
     SET name = $2,
     description = $3,
-    type = $4
+    type = $4,
+    parent_id = $6,
+    tax_section = $7,
+    tax_exempt = $8
     WHERE categories.id = $1 And categories.user_id=$5
     RETURNING *
 )
@@ -60,8 +72,44 @@ SELECT
     updated.name,
     updated.description,
     updated.type,
+    updated.parent_id,
+    updated.tax_section,
+    updated.tax_exempt,
     users.name AS user,
     updated.created_at,
     updated.updated_at
 FROM updated
 INNER JOIN users ON updated.user_id = users.id;
+
+-- name: ReassignCategoryParent :execresult
+UPDATE categories
+SET parent_id = sqlc.arg(target_parent)
+WHERE parent_id = sqlc.arg(source_parent) AND user_id = sqlc.arg(user_id);
+
+-- name: GetCategoryTotals :many
+SELECT
+    categories.id,
+    categories.name,
+    categories.type,
+    categories.parent_id,
+    COALESCE(totals.total, 0)::numeric AS total
+FROM categories
+LEFT JOIN (
+    SELECT entries.category, SUM(entries.amount) AS total
+    FROM (
+        SELECT transactions.category, transactions.amount
+        FROM transactions
+        WHERE transactions.user_id = sqlc.arg(user_id) AND transactions."date" BETWEEN sqlc.arg(from_date) AND sqlc.arg(to_date)
+        UNION ALL
+        SELECT incomes.category, incomes.amount
+        FROM incomes
+        WHERE incomes.user_id = sqlc.arg(user_id) AND incomes."date" BETWEEN sqlc.arg(from_date) AND sqlc.arg(to_date)
+        UNION ALL
+        SELECT investments.category, investments.amount
+        FROM investments
+        WHERE investments.user_id = sqlc.arg(user_id) AND investments."date" BETWEEN sqlc.arg(from_date) AND sqlc.arg(to_date)
+    ) entries
+    GROUP BY entries.category
+) totals ON totals.category = categories.id
+WHERE categories.user_id = sqlc.arg(user_id)
+ORDER BY categories.name;
@@ -0,0 +1,52 @@
+-- name: CreateGoal :one
+INSERT INTO goals(id, name, target_amount, start_date, target_date, note, user_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetGoal :many
+SELECT * FROM goals
+WHERE user_id = $1
+ORDER BY target_date;
+
+-- name: GetGoalById :one
+SELECT * FROM goals
+WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateGoal :one
+UPDATE goals
+SET name = $2,
+    target_amount = $3,
+    start_date = $4,
+    target_date = $5,
+    note = $6
+WHERE id = $1 AND user_id = $7
+RETURNING *;
+
+-- name: DeleteGoal :execresult
+DELETE FROM goals WHERE id = $1 AND user_id = $2;
+
+-- name: AddGoalCategory :exec
+INSERT INTO goal_categories(goal_id, category_id)
+VALUES ($1, $2);
+
+-- name: DeleteGoalCategories :exec
+DELETE FROM goal_categories WHERE goal_id = $1;
+
+-- name: GetGoalCategories :many
+SELECT goal_categories.goal_id,
+    categories.id,
+    categories."name",
+    categories.type
+FROM goal_categories
+INNER JOIN categories ON goal_categories.category_id = categories.id
+WHERE categories.user_id = $1
+ORDER BY categories."name";
+
+-- name: ReassignGoalCategory :exec
+UPDATE goal_categories
+SET category_id = sqlc.arg(target_category)
+WHERE goal_categories.category_id = sqlc.arg(source_category)
+    AND NOT EXISTS (
+        SELECT 1 FROM goal_categories existing
+        WHERE existing.goal_id = goal_categories.goal_id AND existing.category_id = sqlc.arg(target_category)
+    );
@@ -60,3 +60,19 @@ INNER JOIN categories ON updated."category" = categories.id;
 -- name: DeleteIncome :execresult
 DELETE FROM incomes where id = $1 AND user_id=$2;
 
+-- name: ReassignIncomeCategory :execresult
+UPDATE incomes
+SET category = sqlc.arg(target_category)
+WHERE category = sqlc.arg(source_category) AND user_id = sqlc.arg(user_id);
+
+-- name: GetIncomesInRange :many
+SELECT incomes.id,
+    incomes."name",
+    incomes.amount,
+    incomes.category AS category_id,
+    categories."name" AS category,
+    incomes."date"
+FROM incomes
+INNER JOIN categories ON incomes.category = categories.id
+WHERE incomes.user_id = sqlc.arg(user_id) AND incomes."date" BETWEEN sqlc.arg(from_date) AND sqlc.arg(to_date)
+ORDER BY incomes."date";
@@ -60,3 +60,19 @@ INNER JOIN categories ON updated."category" = categories.id;
 -- name: DeleteInvestment :execresult
 DELETE FROM investments where id = $1 AND user_id=$2;
 
+-- name: ReassignInvestmentCategory :execresult
+UPDATE investments
+SET category = sqlc.arg(target_category)
+WHERE category = sqlc.arg(source_category) AND user_id = sqlc.arg(user_id);
+
+-- name: GetInvestmentsInRange :many
+SELECT investments.id,
+    investments."name",
+    investments.amount,
+    investments.category AS category_id,
+    categories."name" AS category,
+    investments."date"
+FROM investments
+INNER JOIN categories ON investments.category = categories.id
+WHERE investments.user_id = sqlc.arg(user_id) AND investments."date" BETWEEN sqlc.arg(from_date) AND sqlc.arg(to_date)
+ORDER BY investments."date";
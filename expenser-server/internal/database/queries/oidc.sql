@@ -0,0 +1,30 @@
+-- name: CreateOIDCLogin :exec
+INSERT INTO oidc_logins(state, nonce, code_verifier, expires_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: ConsumeOIDCLogin :one
+DELETE FROM oidc_logins
+WHERE state = $1
+RETURNING *;
+
+-- name: DeleteExpiredOIDCLogins :exec
+DELETE FROM oidc_logins WHERE expires_at < CURRENT_TIMESTAMP;
+
+-- name: GetUserByIdentity :one
+SELECT users.* FROM users
+JOIN user_identities ON user_identities.user_id = users.id
+WHERE user_identities.issuer = $1 AND user_identities.subject = $2;
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities(id, user_id, issuer, subject, authenticated_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET last_login_at = CURRENT_TIMESTAMP, authenticated_at = $3
+WHERE issuer = $1 AND subject = $2;
+
+-- name: GetLastIdentityAuthentication :one
+SELECT MAX(authenticated_at)::TIMESTAMPTZ AS authenticated_at
+FROM user_identities
+WHERE user_id = $1;
@@ -0,0 +1,36 @@
+-- name: SearchEntries :many
+SELECT
+    entries.kind::text AS kind,
+    entries.id,
+    entries.name,
+    entries.amount,
+    entries.category AS category_id,
+    categories.name AS category,
+    entries."date",
+    entries.note,
+    ts_rank(entries.search_vector, websearch_to_tsquery('english', sqlc.arg(query))) AS rank,
+    ts_headline('english', translate(entries.name, chr(2) || chr(3), ''), websearch_to_tsquery('english', sqlc.arg(query)), 'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', HighlightAll=true') AS name_highlight,
+    ts_headline('english', translate(COALESCE(entries.note, ''), chr(2) || chr(3), ''), websearch_to_tsquery('english', sqlc.arg(query)), 'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxFragments=2') AS note_highlight
+FROM (
+    SELECT 'transaction' AS kind, transactions.id, transactions.name, transactions.amount, transactions.category, transactions."date", transactions.note, transactions.search_vector
+    FROM transactions
+    WHERE transactions.user_id = sqlc.arg(user_id)
+    UNION ALL
+    SELECT 'income' AS kind, incomes.id, incomes.name, incomes.amount, incomes.category, incomes."date", incomes.note, incomes.search_vector
+    FROM incomes
+    WHERE incomes.user_id = sqlc.arg(user_id)
+    UNION ALL
+    SELECT 'investment' AS kind, investments.id, investments.name, investments.amount, investments.category, investments."date", investments.note, investments.search_vector
+    FROM investments
+    WHERE investments.user_id = sqlc.arg(user_id)
+) entries
+INNER JOIN categories ON entries.category = categories.id
+WHERE entries.search_vector @@ websearch_to_tsquery('english', sqlc.arg(query))
+    AND (sqlc.narg(kind)::text IS NULL OR entries.kind = sqlc.narg(kind)::text)
+    AND (sqlc.narg(category)::uuid IS NULL OR entries.category = sqlc.narg(category)::uuid)
+    AND (sqlc.narg(from_date)::date IS NULL OR entries."date" >= sqlc.narg(from_date)::date)
+    AND (sqlc.narg(to_date)::date IS NULL OR entries."date" <= sqlc.narg(to_date)::date)
+    AND (sqlc.narg(min_amount)::numeric IS NULL OR entries.amount >= sqlc.narg(min_amount)::numeric)
+    AND (sqlc.narg(max_amount)::numeric IS NULL OR entries.amount <= sqlc.narg(max_amount)::numeric)
+ORDER BY rank DESC, entries."date" DESC
+LIMIT sqlc.arg(result_limit);
@@ -0,0 +1,24 @@
+-- name: GetTaxTotals :many
+SELECT
+    categories.id,
+    categories.name,
+    categories.type,
+    categories.tax_section,
+    categories.tax_exempt,
+    COALESCE(totals.total, 0)::numeric AS total
+FROM categories
+LEFT JOIN (
+    SELECT entries.category, SUM(entries.amount) AS total
+    FROM (
+        SELECT incomes.category, incomes.amount
+        FROM incomes
+        WHERE incomes.user_id = sqlc.arg(user_id) AND incomes."date" BETWEEN sqlc.arg(from_date) AND sqlc.arg(to_date)
+        UNION ALL
+        SELECT investments.category, investments.amount
+        FROM investments
+        WHERE investments.user_id = sqlc.arg(user_id) AND investments."date" BETWEEN sqlc.arg(from_date) AND sqlc.arg(to_date)
+    ) entries
+    GROUP BY entries.category
+) totals ON totals.category = categories.id
+WHERE categories.user_id = sqlc.arg(user_id) AND categories.type IN ('Income', 'Investment')
+ORDER BY categories.name;
@@ -60,4 +60,19 @@ FROM updated
 INNER JOIN users ON updated.user_id = users.id
 INNER JOIN categories ON updated."category" = categories.id;
 
+-- name: ReassignTransactionCategory :execresult
+UPDATE transactions
+SET category = sqlc.arg(target_category)
+WHERE category = sqlc.arg(source_category) AND user_id = sqlc.arg(user_id);
 
+-- name: GetTransactionsInRange :many
+SELECT transactions.id,
+    transactions."name",
+    transactions.amount,
+    transactions.category AS category_id,
+    categories."name" AS category,
+    transactions."date"
+FROM transactions
+INNER JOIN categories ON transactions.category = categories.id
+WHERE transactions.user_id = sqlc.arg(user_id) AND transactions."date" BETWEEN sqlc.arg(from_date) AND sqlc.arg(to_date)
+ORDER BY transactions."date";
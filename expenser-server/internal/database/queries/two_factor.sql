@@ -0,0 +1,43 @@
+-- name: GetUserTotp :one
+SELECT * FROM user_totp
+WHERE user_id = $1;
+
+-- name: UpsertUserTotp :one
+INSERT INTO user_totp(user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret, enabled_at = NULL, last_used_step = 0, failed_attempts = 0, locked_until = NULL, created_at = CURRENT_TIMESTAMP
+WHERE user_totp.enabled_at IS NULL
+RETURNING *;
+
+-- name: EnableUserTotp :execresult
+UPDATE user_totp
+SET enabled_at = CURRENT_TIMESTAMP, last_used_step = $2, failed_attempts = 0, locked_until = NULL
+WHERE user_id = $1 AND enabled_at IS NULL;
+
+-- name: RecordTotpSuccess :exec
+UPDATE user_totp
+SET last_used_step = GREATEST(last_used_step, $2), failed_attempts = 0, locked_until = NULL
+WHERE user_id = $1;
+
+-- name: RecordTotpFailure :one
+UPDATE user_totp
+SET failed_attempts = failed_attempts + 1,
+    locked_until = CASE WHEN failed_attempts + 1 >= sqlc.arg(max_attempts)::int THEN sqlc.arg(locked_until)::timestamptz ELSE locked_until END
+WHERE user_id = sqlc.arg(user_id)
+RETURNING *;
+
+-- name: DeleteUserTotp :execresult
+DELETE FROM user_totp WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO recovery_codes(id, user_id, code_hash)
+VALUES ($1, $2, $3);
+
+-- name: UseRecoveryCode :execresult
+UPDATE recovery_codes
+SET used_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes WHERE user_id = $1;
@@ -1,6 +1,6 @@
 -- name: CreateUser :one
-INSERT INTO users(id, name,username, hashed_password)
-VALUES ($1, $2, $3, $4)
+INSERT INTO users(id, name,username, hashed_password, password_set)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: GetUser :many
@@ -18,3 +18,18 @@ SET name = $2,
     image = $3
 WHERE id = $1
 RETURNING *;
+
+-- name: UpdateUserPreferences :one
+UPDATE users
+SET locale = $2,
+    date_format = $3,
+    timezone = $4,
+    base_currency = $5,
+    first_day_of_week = $6,
+    fiscal_year_start_month = $7,
+    default_expense_category = $8,
+    default_income_category = $9,
+    default_investment_category = $10,
+    pay_cycle_start_day = $11
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE categories
+ADD parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+
+-- +goose Down
+DROP INDEX idx_categories_parent_id;
+
+ALTER TABLE categories
+DROP COLUMN parent_id;
@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE goals(
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    target_amount NUMERIC(12,4) NOT NULL,
+    start_date DATE NOT NULL,
+    target_date DATE NOT NULL,
+    note TEXT,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(name, user_id)
+);
+
+CREATE TRIGGER update_goals_updated_at
+    BEFORE UPDATE ON goals
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_timestamp();
+
+CREATE INDEX idx_goals_user_id ON goals(user_id);
+
+CREATE TABLE goal_categories(
+    goal_id UUID NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (goal_id, category_id)
+);
+
+CREATE INDEX idx_goal_categories_category_id ON goal_categories(category_id);
+
+-- +goose Down
+DROP TABLE goal_categories;
+DROP TABLE goals;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE users
+ADD locale TEXT NOT NULL DEFAULT 'en-IN',
+ADD date_format TEXT NOT NULL DEFAULT 'DD/MM/YYYY',
+ADD timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN timezone,
+DROP COLUMN date_format,
+DROP COLUMN locale;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE users
+ADD role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin'));
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN role;
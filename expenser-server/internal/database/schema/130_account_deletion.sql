@@ -0,0 +1,11 @@
+-- +goose Up
+-- Users who deleted their account. Access tokens are stateless, so the ones issued before
+-- the deletion stay valid until they expire unless checked against this table. There is
+-- no foreign key, the user row is gone.
+CREATE TABLE revoked_users(
+    user_id UUID PRIMARY KEY,
+    revoked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE revoked_users;
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE users
+ADD base_currency TEXT NOT NULL DEFAULT 'INR' CHECK (base_currency ~ '^[A-Z]{3}$'),
+ADD first_day_of_week TEXT NOT NULL DEFAULT 'Monday' CHECK (first_day_of_week IN ('Sunday', 'Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday')),
+ADD fiscal_year_start_month INTEGER NOT NULL DEFAULT 4 CHECK (fiscal_year_start_month BETWEEN 1 AND 12),
+ADD default_expense_category UUID REFERENCES categories(id) ON DELETE SET NULL,
+ADD default_income_category UUID REFERENCES categories(id) ON DELETE SET NULL,
+ADD default_investment_category UUID REFERENCES categories(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN default_investment_category,
+DROP COLUMN default_income_category,
+DROP COLUMN default_expense_category,
+DROP COLUMN fiscal_year_start_month,
+DROP COLUMN first_day_of_week,
+DROP COLUMN base_currency;
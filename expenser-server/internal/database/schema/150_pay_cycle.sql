@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE users
+ADD pay_cycle_start_day INTEGER NOT NULL DEFAULT 1 CHECK (pay_cycle_start_day BETWEEN 1 AND 31);
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN pay_cycle_start_day;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE categories
+ADD tax_section TEXT CHECK (tax_section IS NULL OR type = 'Investment'),
+ADD tax_exempt BOOLEAN NOT NULL DEFAULT false CHECK (NOT tax_exempt OR type = 'Income');
+
+-- +goose Down
+ALTER TABLE categories
+DROP COLUMN tax_exempt,
+DROP COLUMN tax_section;
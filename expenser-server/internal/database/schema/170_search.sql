@@ -0,0 +1,84 @@
+-- +goose Up
+-- The search vector of an entry weighs its name over its category name over its note.
+-- +goose StatementBegin
+CREATE FUNCTION entry_search_vector(entry_name TEXT, entry_note TEXT, entry_category UUID)
+RETURNS tsvector
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT setweight(to_tsvector('english', entry_name), 'A')
+        || setweight(to_tsvector('english', COALESCE((SELECT categories.name FROM categories WHERE categories.id = entry_category), '')), 'B')
+        || setweight(to_tsvector('english', COALESCE(entry_note, '')), 'C');
+$$;
+-- +goose StatementEnd
+
+CREATE FUNCTION trigger_set_search_vector()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$ BEGIN NEW.search_vector = entry_search_vector(NEW.name, NEW.note, NEW.category); RETURN NEW; END; $$;
+
+-- Renaming a category changes the search vectors of its entries.
+-- +goose StatementBegin
+CREATE FUNCTION trigger_refresh_category_search_vectors()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    UPDATE transactions SET search_vector = entry_search_vector(name, note, category) WHERE category = NEW.id;
+    UPDATE incomes SET search_vector = entry_search_vector(name, note, category) WHERE category = NEW.id;
+    UPDATE investments SET search_vector = entry_search_vector(name, note, category) WHERE category = NEW.id;
+    RETURN NULL;
+END;
+$$;
+-- +goose StatementEnd
+
+ALTER TABLE transactions ADD search_vector tsvector;
+ALTER TABLE incomes ADD search_vector tsvector;
+ALTER TABLE investments ADD search_vector tsvector;
+
+UPDATE transactions SET search_vector = entry_search_vector(name, note, category);
+UPDATE incomes SET search_vector = entry_search_vector(name, note, category);
+UPDATE investments SET search_vector = entry_search_vector(name, note, category);
+
+ALTER TABLE transactions ALTER search_vector SET NOT NULL;
+ALTER TABLE incomes ALTER search_vector SET NOT NULL;
+ALTER TABLE investments ALTER search_vector SET NOT NULL;
+
+CREATE TRIGGER set_transactions_search_vector
+    BEFORE INSERT OR UPDATE OF name, note, category ON transactions
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_search_vector();
+
+CREATE TRIGGER set_incomes_search_vector
+    BEFORE INSERT OR UPDATE OF name, note, category ON incomes
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_search_vector();
+
+CREATE TRIGGER set_investments_search_vector
+    BEFORE INSERT OR UPDATE OF name, note, category ON investments
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_search_vector();
+
+CREATE TRIGGER refresh_category_search_vectors
+    AFTER UPDATE OF name ON categories
+    FOR EACH ROW
+    WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION trigger_refresh_category_search_vectors();
+
+CREATE INDEX idx_transactions_search_vector ON transactions USING GIN (search_vector);
+CREATE INDEX idx_incomes_search_vector ON incomes USING GIN (search_vector);
+CREATE INDEX idx_investments_search_vector ON investments USING GIN (search_vector);
+
+-- +goose Down
+DROP TRIGGER refresh_category_search_vectors ON categories;
+DROP TRIGGER set_investments_search_vector ON investments;
+DROP TRIGGER set_incomes_search_vector ON incomes;
+DROP TRIGGER set_transactions_search_vector ON transactions;
+
+ALTER TABLE investments DROP COLUMN search_vector;
+ALTER TABLE incomes DROP COLUMN search_vector;
+ALTER TABLE transactions DROP COLUMN search_vector;
+
+DROP FUNCTION trigger_refresh_category_search_vectors();
+DROP FUNCTION trigger_set_search_vector();
+DROP FUNCTION entry_search_vector(TEXT, TEXT, UUID);
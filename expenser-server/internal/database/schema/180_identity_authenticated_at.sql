@@ -0,0 +1,8 @@
+-- +goose Up
+-- When the provider last authenticated the user, from the auth_time of the ID token. A
+-- recent one stands in for the password of accounts created through single sign-on.
+-- Identities linked before stay NULL until their next login.
+ALTER TABLE user_identities ADD COLUMN authenticated_at TIMESTAMP WITH TIME ZONE;
+
+-- +goose Down
+ALTER TABLE user_identities DROP COLUMN authenticated_at;
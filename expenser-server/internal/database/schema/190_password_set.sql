@@ -0,0 +1,14 @@
+-- +goose Up
+-- Users created on their first single sign-on get a random password nobody knows.
+ALTER TABLE users ADD COLUMN password_set BOOLEAN NOT NULL DEFAULT true;
+
+-- Those users and their identity were created in one transaction, so they share the
+-- CURRENT_TIMESTAMP, while accounts linked to an identity existed before it.
+UPDATE users SET password_set = false
+WHERE EXISTS (
+    SELECT 1 FROM user_identities
+    WHERE user_identities.user_id = users.id AND user_identities.created_at = users.created_at
+);
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN password_set;
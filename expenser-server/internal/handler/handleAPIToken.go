@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+)
+
+func HandleAPITokenGet(apiTokenService model.APITokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		tokens, err := apiTokenService.GetAPITokensFromDB(r.Context(), userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusOK, tokens)
+	}
+}
+
+// HandleAPITokenCreate creates a personal API token. The token is only ever returned in
+// this response, the server keeps its hash.
+func HandleAPITokenCreate(apiTokenService model.APITokenService) http.HandlerFunc {
+	type parameters struct {
+		Name          string `json:"name" validate:"required,max=50"`
+		Scope         string `json:"scope" validate:"required,oneof=read|write"`
+		ExpiresInDays int    `json:"expires_in_days"`
+	}
+
+	type response struct {
+		model.APIToken
+		Token string `json:"token"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if !requireLoginSession(w, r) {
+			return
+		}
+
+		params := parameters{}
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		if err := model.Validate(params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		if params.ExpiresInDays == 0 {
+			params.ExpiresInDays = model.DefaultAPITokenExpiryDays
+		}
+		if params.ExpiresInDays < 0 || params.ExpiresInDays > model.MaxAPITokenExpiryDays {
+			respondWithAppError(w, model.NewValidationError("expires_in_days", model.FieldInvalid,
+				fmt.Sprintf("expires_in_days must be between 1 and %d", model.MaxAPITokenExpiryDays)))
+			return
+		}
+
+		token, hash, prefix, err := auth.GenerateAPIToken()
+		if err != nil {
+			logger.Error(r.Context(), "failed to generate api token", map[string]interface{}{"error": err})
+			respondWithError(w, http.StatusInternalServerError, "Couldn't create api token")
+			return
+		}
+
+		created, err := apiTokenService.CreateAPITokenInDB(r.Context(), model.NewAPIToken{
+			UserID:    userID,
+			Name:      params.Name,
+			Scope:     params.Scope,
+			Hash:      hash,
+			Prefix:    prefix,
+			ExpiresAt: time.Now().UTC().AddDate(0, 0, params.ExpiresInDays),
+		})
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusCreated, response{APIToken: created, Token: token})
+	}
+}
+
+func HandleAPITokenDelete(apiTokenService model.APITokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.PathValue("id")
+
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			logger.Error(r.Context(), "Error while parsing uuid", map[string]interface{}{
+				"error": err,
+				"uuid":  idStr,
+			})
+			respondWithError(w, http.StatusBadRequest, "Invalid id")
+			return
+		}
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if !requireLoginSession(w, r) {
+			return
+		}
+
+		err = apiTokenService.DeleteAPITokenFromDB(r.Context(), id, userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// requireLoginSession rejects requests made with a personal API token, so a leaked
+// token can't be used to mint new ones, revoke the others or change two-factor settings.
+func requireLoginSession(w http.ResponseWriter, r *http.Request) bool {
+	if _, ok := auth.ScopeFromContext(r.Context()); ok {
+		respondWithAppError(w, model.NewForbiddenError("this requires logging in, api tokens aren't accepted"))
+		return false
+	}
+	return true
+}
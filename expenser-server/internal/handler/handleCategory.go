@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -19,30 +18,43 @@ func HandleCategoryGet(categoryService model.CategoryService) http.HandlerFunc {
 		}
 		categories, err := categoryService.GetCategoriesFromDB(r.Context(), userID)
 		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve categories")
+			respondWithAppError(w, err)
 			return
 		}
 		respondWithJson(w, http.StatusOK, categories)
 	}
 }
 
+func HandleCategoryTreeGet(categoryService model.CategoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		tree, err := categoryService.GetCategoryTreeFromDB(r.Context(), userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+		respondWithJson(w, http.StatusOK, tree)
+	}
+}
+
 func HandleCategoryCreate(categoryService model.CategoryService) http.HandlerFunc {
 	type parameters struct {
-		Name        string `json:"name"`
-		Type        string `json:"type"`
-		Description string `json:"description"`
+		Name        string     `json:"name"`
+		Type        string     `json:"type"`
+		Description string     `json:"description"`
+		ParentID    *uuid.UUID `json:"parent_id"`
+		TaxSection  string     `json:"tax_section"`
+		TaxExempt   bool       `json:"tax_exempt"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		decoder := json.NewDecoder(r.Body)
 		params := parameters{}
-		err := decoder.Decode(&params)
-		if err != nil {
-			logger.Error("Error while decoding parameters:%s", map[string]interface{}{
-				"error":  err,
-				"params": params,
-			})
-			respondWithError(w, http.StatusBadRequest, "Couldn't decode parameters")
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
 			return
 		}
 
@@ -56,11 +68,14 @@ func HandleCategoryCreate(categoryService model.CategoryService) http.HandlerFun
 			Name:        params.Name,
 			Type:        params.Type,
 			Description: params.Description,
+			ParentID:    params.ParentID,
+			TaxSection:  params.TaxSection,
+			TaxExempt:   params.TaxExempt,
 			UserID:      userID,
 		})
 
 		if err != nil {
-			respondWithError(w, http.StatusBadRequest, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 
@@ -70,9 +85,12 @@ func HandleCategoryCreate(categoryService model.CategoryService) http.HandlerFun
 
 func HandleCategoryUpdate(categoryService model.CategoryService) http.HandlerFunc {
 	type parameters struct {
-		Name        string `json:"name"`
-		Type        string `json:"type"`
-		Description string `json:"description"`
+		Name        string     `json:"name"`
+		Type        string     `json:"type"`
+		Description string     `json:"description"`
+		ParentID    *uuid.UUID `json:"parent_id"`
+		TaxSection  string     `json:"tax_section"`
+		TaxExempt   bool       `json:"tax_exempt"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -80,7 +98,7 @@ func HandleCategoryUpdate(categoryService model.CategoryService) http.HandlerFun
 
 		id, err := uuid.Parse(idStr)
 		if err != nil {
-			logger.Error("Error while parsing uuid", map[string]interface{}{
+			logger.Error(r.Context(), "Error while parsing uuid", map[string]interface{}{
 				"error": err,
 				"uuid":  idStr,
 			})
@@ -88,16 +106,9 @@ func HandleCategoryUpdate(categoryService model.CategoryService) http.HandlerFun
 			return
 		}
 
-		decoder := json.NewDecoder(r.Body)
 		params := parameters{}
-		err = decoder.Decode(&params)
-		if err != nil {
-			logger.Error("Error while decoding parameters:%s", map[string]interface{}{
-				"error":     err,
-				"params":    params,
-				"errorType": "decode_error",
-			})
-			respondWithError(w, http.StatusBadRequest, "Couldn't decode parameters")
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
 			return
 		}
 
@@ -111,11 +122,14 @@ func HandleCategoryUpdate(categoryService model.CategoryService) http.HandlerFun
 			Name:        params.Name,
 			Type:        params.Type,
 			Description: params.Description,
+			ParentID:    params.ParentID,
+			TaxSection:  params.TaxSection,
+			TaxExempt:   params.TaxExempt,
 			UserID:      userID,
 		})
 
 		if err != nil {
-			respondWithError(w, http.StatusBadRequest, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 		respondWithJson(w, http.StatusOK, category)
@@ -137,12 +151,109 @@ func HandleCategoryDelete(categoryService model.CategoryService) http.HandlerFun
 			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
 			return
 		}
+
+		if reassignTo := r.URL.Query().Get("reassign_to"); reassignTo != "" {
+			targetID, err := uuid.Parse(reassignTo)
+			if err != nil {
+				respondWithError(w, http.StatusBadRequest, "Invalid reassign_to id")
+				return
+			}
+			if _, err := categoryService.MergeCategoryInDB(r.Context(), id, targetID, userID); err != nil {
+				respondWithAppError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
 		err = categoryService.DeleteCategoryFromDB(r.Context(), id, userID)
 		if err != nil {
-			respondWithError(w, http.StatusBadRequest, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+func HandleCategoryMerge(categoryService model.CategoryService) http.HandlerFunc {
+	type parameters struct {
+		Target uuid.UUID `json:"target"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.PathValue("id")
+
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			logger.Error(r.Context(), "Error while parsing uuid", map[string]interface{}{
+				"error": err,
+				"uuid":  idStr,
+			})
+			respondWithError(w, http.StatusBadRequest, "Invalid id")
+			return
+		}
+
+		params := parameters{}
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		category, err := categoryService.MergeCategoryInDB(r.Context(), id, params.Target, userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+		respondWithJson(w, http.StatusOK, category)
+	}
+}
+
+func HandleCategoryTemplatesGet(categoryService model.CategoryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		templates, err := categoryService.GetCategoryTemplatesFromDB(r.Context(), userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+		respondWithJson(w, http.StatusOK, templates)
+	}
+}
+
+func HandleCategoryTemplatesApply(categoryService model.CategoryService) http.HandlerFunc {
+	type parameters struct {
+		Names []string `json:"names"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := parameters{}
+		if err := decodeOptionalJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		applied, err := categoryService.ApplyCategoryTemplatesToDB(r.Context(), userID, params.Names)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+		respondWithJson(w, http.StatusCreated, applied)
+	}
+}
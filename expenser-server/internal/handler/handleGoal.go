@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/shopspring/decimal"
+)
+
+func HandleGoalGet(goalService model.GoalService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		goals, err := goalService.GetGoalsFromDB(r.Context(), userID)
+		if err != nil {
+			logger.Error(r.Context(), "Error while fetching goals", map[string]interface{}{
+				"error": err,
+			})
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusOK, goals)
+	}
+}
+
+func HandleGoalGetById(goalService model.GoalService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.PathValue("id")
+
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			logger.Error(r.Context(), "Error while parsing goal ID", map[string]interface{}{
+				"error": err,
+				"uuid":  idStr,
+			})
+			respondWithError(w, http.StatusBadRequest, "Invalid id")
+			return
+		}
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		goal, err := goalService.GetGoalFromDB(r.Context(), id, userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusOK, goal)
+	}
+}
+
+func HandleGoalCreate(goalService model.GoalService) http.HandlerFunc {
+	type parameters struct {
+		Name         string          `json:"name"`
+		TargetAmount decimal.Decimal `json:"target_amount"`
+		StartDate    string          `json:"start_date"`
+		TargetDate   string          `json:"target_date"`
+		Note         string          `json:"note"`
+		Categories   []uuid.UUID     `json:"categories"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := parameters{}
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		goal := model.InputGoal{
+			ID:           uuid.New(),
+			Name:         params.Name,
+			TargetAmount: params.TargetAmount,
+			StartDate:    params.StartDate,
+			TargetDate:   params.TargetDate,
+			Note:         params.Note,
+			Categories:   params.Categories,
+			UserID:       userID,
+		}
+
+		dbGoal, err := goalService.AddGoalToDB(r.Context(), goal)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+		respondWithJson(w, http.StatusCreated, dbGoal)
+	}
+}
+
+func HandleGoalUpdate(goalService model.GoalService) http.HandlerFunc {
+	type parameters struct {
+		Name         string          `json:"name"`
+		TargetAmount decimal.Decimal `json:"target_amount"`
+		StartDate    string          `json:"start_date"`
+		TargetDate   string          `json:"target_date"`
+		Note         string          `json:"note"`
+		Categories   []uuid.UUID     `json:"categories"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.PathValue("id")
+
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			logger.Error(r.Context(), "Error while parsing goal ID", map[string]interface{}{
+				"error": err,
+				"uuid":  idStr,
+			})
+			respondWithError(w, http.StatusBadRequest, "Invalid id")
+			return
+		}
+
+		params := parameters{}
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		goal := model.InputGoal{
+			ID:           id,
+			Name:         params.Name,
+			TargetAmount: params.TargetAmount,
+			StartDate:    params.StartDate,
+			TargetDate:   params.TargetDate,
+			Note:         params.Note,
+			Categories:   params.Categories,
+			UserID:       userID,
+		}
+
+		dbGoal, err := goalService.UpdateGoalInDB(r.Context(), goal)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+		respondWithJson(w, http.StatusOK, dbGoal)
+	}
+}
+
+func HandleGoalDelete(goalService model.GoalService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idStr := r.PathValue("id")
+
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			logger.Error(r.Context(), "Error while parsing uuid", map[string]interface{}{
+				"error": err,
+				"uuid":  idStr,
+			})
+			respondWithError(w, http.StatusBadRequest, "Invalid id")
+			return
+		}
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		err = goalService.DeleteGoalFromDB(r.Context(), id, userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -7,18 +7,35 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/handler/util"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
 	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/keertirajmalik/expenser/expenser-server/metrics"
 )
 
-func HandleTransactionImport() http.HandlerFunc {
+func HandleTransactionImport(userService model.UserService) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
+		result := "failure"
+		defer func() { metrics.ImportsTotal.Inc(result) }()
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		preferences, err := userService.GetPreferencesFromDB(r.Context(), userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
 
 		file, handler, err := r.FormFile("file")
 
 		if err != nil {
-			logger.Error("Error while receiving file", map[string]any{
+			logger.Error(r.Context(), "Error while receiving file", map[string]any{
 				"error": err,
 			})
 			respondWithError(w, http.StatusBadRequest, err.Error())
@@ -27,19 +44,19 @@ func HandleTransactionImport() http.HandlerFunc {
 
 		defer func() {
 			if cerr := file.Close(); cerr != nil {
-				logger.Error("failed to close temp file", map[string]any{"error": cerr, "name": handler.Filename})
+				logger.Error(r.Context(), "failed to close temp file", map[string]any{"error": cerr, "name": handler.Filename})
 			}
 		}()
 
 		ext := strings.ToLower(filepath.Ext(handler.Filename))
 		if ext == ".xls" {
-			logger.Error("invalid file is uploaded", map[string]any{
+			logger.Error(r.Context(), "invalid file is uploaded", map[string]any{
 				"filename": handler.Filename,
 			})
 			respondWithError(w, http.StatusBadRequest, "Only XLSX file is allowed")
 			return
 		} else if ext != ".xlsx" {
-			logger.Error("invalid file is uploaded", map[string]any{
+			logger.Error(r.Context(), "invalid file is uploaded", map[string]any{
 				"filename": handler.Filename,
 			})
 			respondWithError(w, http.StatusBadRequest, "Only XLSX file is allowed")
@@ -49,13 +66,13 @@ func HandleTransactionImport() http.HandlerFunc {
 		currentDir, _ := filepath.Abs("./")
 		tempDir := currentDir + "/temp-files"
 		if err := os.MkdirAll(tempDir, 0o700); err != nil {
-			logger.Error("failed to ensure temp dir", map[string]any{"error": err, "dir": tempDir})
+			logger.Error(r.Context(), "failed to ensure temp dir", map[string]any{"error": err, "dir": tempDir})
 			respondWithError(w, http.StatusInternalServerError, "Server error")
 			return
 		}
 		tempFile, err := os.CreateTemp(tempDir, "*-"+handler.Filename)
 		if err != nil {
-			logger.Error("Error while creating temp file", map[string]any{
+			logger.Error(r.Context(), "Error while creating temp file", map[string]any{
 				"error":    err,
 				"filename": handler.Filename,
 			})
@@ -64,16 +81,16 @@ func HandleTransactionImport() http.HandlerFunc {
 		}
 		defer func() {
 			if cerr := tempFile.Close(); cerr != nil {
-				logger.Error("failed to close temp file", map[string]any{"error": cerr, "name": tempFile.Name()})
+				logger.Error(r.Context(), "failed to close temp file", map[string]any{"error": cerr, "name": tempFile.Name()})
 			}
 			if rerr := os.Remove(tempFile.Name()); rerr != nil {
-				logger.Error("failed to remove temp file", map[string]any{"error": rerr, "name": tempFile.Name()})
+				logger.Error(r.Context(), "failed to remove temp file", map[string]any{"error": rerr, "name": tempFile.Name()})
 			}
 		}()
 
 		// read all of the contents of our uploaded file into a byte array
 		if _, err := io.Copy(tempFile, file); err != nil {
-			logger.Error("Error while persisting uploaded file", map[string]any{
+			logger.Error(r.Context(), "Error while persisting uploaded file", map[string]any{
 				"error":    err,
 				"filename": handler.Filename,
 			})
@@ -82,9 +99,9 @@ func HandleTransactionImport() http.HandlerFunc {
 		}
 
 		// parse the excel file uploaded and display the data
-		transactions, err := util.ReadExcelFile(tempFile.Name())
+		transactions, err := util.ReadExcelFile(r.Context(), tempFile.Name(), preferences)
 		if err != nil {
-			logger.Error("Error while parsing the file content", map[string]any{
+			logger.Error(r.Context(), "Error while parsing the file content", map[string]any{
 				"error":    err,
 				"filename": handler.Filename,
 			})
@@ -92,6 +109,8 @@ func HandleTransactionImport() http.HandlerFunc {
 			return
 		}
 
+		result = "success"
+		metrics.ImportedRowsTotal.Add(float64(len(transactions)))
 		respondWithJson(w, http.StatusCreated, transactions)
 	}
 }
@@ -1,12 +1,9 @@
 package handler
 
 import (
-	"encoding/json"
-	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 	"github.com/keertirajmalik/expenser/expenser-server/auth"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
 	"github.com/keertirajmalik/expenser/expenser-server/logger"
@@ -22,10 +19,10 @@ func HandleInvestmentGet(investmentService model.InvestmentService) http.Handler
 		}
 		investments, err := investmentService.GetInvestmentsFromDB(r.Context(), userID)
 		if err != nil {
-			logger.Error("Error while fetching investments", map[string]interface{}{
+			logger.Error(r.Context(), "Error while fetching investments", map[string]interface{}{
 				"error": err,
 			})
-			respondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 
@@ -43,15 +40,9 @@ func HandleInvestmentCreate(investmentService model.InvestmentService) http.Hand
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		decoder := json.NewDecoder(r.Body)
 		params := parameters{}
-		err := decoder.Decode(&params)
-		if err != nil {
-			logger.Error("Error while decoding parameters", map[string]interface{}{
-				"params": params,
-				"error":  err,
-			})
-			respondWithError(w, http.StatusBadRequest, err.Error())
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
 			return
 		}
 
@@ -73,7 +64,7 @@ func HandleInvestmentCreate(investmentService model.InvestmentService) http.Hand
 
 		dbInvestment, err := investmentService.AddInvestmentToDB(r.Context(), investment)
 		if err != nil {
-			respondWithError(w, http.StatusBadRequest, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 		respondWithJson(w, http.StatusCreated, dbInvestment)
@@ -94,7 +85,7 @@ func HandleInvestmentUpdate(investmentService model.InvestmentService) http.Hand
 
 		id, err := uuid.Parse(idStr)
 		if err != nil {
-			logger.Error("Error while parsing investment ID", map[string]interface{}{
+			logger.Error(r.Context(), "Error while parsing investment ID", map[string]interface{}{
 				"error": err,
 				"uuid":  idStr,
 			})
@@ -102,15 +93,9 @@ func HandleInvestmentUpdate(investmentService model.InvestmentService) http.Hand
 			return
 		}
 
-		decoder := json.NewDecoder(r.Body)
 		params := parameters{}
-		err = decoder.Decode(&params)
-		if err != nil {
-			logger.Error("Error while decoding parameters", map[string]interface{}{
-				"error":  err,
-				"params": params,
-			})
-			respondWithError(w, http.StatusBadRequest, err.Error())
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
 			return
 		}
 
@@ -132,7 +117,7 @@ func HandleInvestmentUpdate(investmentService model.InvestmentService) http.Hand
 
 		dbInvestment, err := investmentService.UpdateInvestmentInDB(r.Context(), investment)
 		if err != nil {
-			respondWithError(w, http.StatusBadRequest, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 		respondWithJson(w, http.StatusOK, dbInvestment)
@@ -145,7 +130,7 @@ func HandleInvestmentDelete(investmentService model.InvestmentService) http.Hand
 
 		id, err := uuid.Parse(idStr)
 		if err != nil {
-			logger.Error("Error while parsing uuid", map[string]interface{}{
+			logger.Error(r.Context(), "Error while parsing uuid", map[string]interface{}{
 				"error": err,
 				"uuid":  idStr,
 			})
@@ -160,19 +145,42 @@ func HandleInvestmentDelete(investmentService model.InvestmentService) http.Hand
 		}
 		err = investmentService.DeleteInvestmentFromDB(r.Context(), id, userID)
 		if err != nil {
-			if err == pgx.ErrNoRows {
-				respondWithError(w, http.StatusNotFound, "Investment not found")
-				return
-			}
-			logger.Error("Error while deleting investment", map[string]interface{}{
+			logger.Error(r.Context(), "Error while deleting investment", map[string]interface{}{
 				"investmentId": id,
 				"userId":       userID,
 				"error":        err,
 			})
-			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Failed to delete investment: %v", err))
+			respondWithAppError(w, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+func HandleInvestmentBatch(investmentService model.InvestmentService) http.HandlerFunc {
+	type parameters struct {
+		Operations []model.BatchOperation `json:"operations"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := parameters{}
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		results, err := investmentService.ApplyInvestmentBatch(r.Context(), userID, params.Operations)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+		respondWithJson(w, http.StatusOK, results)
+	}
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+)
+
+// HandleJWKSGet publishes the public keys verifying the access tokens, so other services
+// can check them without sharing a secret.
+func HandleJWKSGet(jwtKeys *auth.Keys) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jwks := auth.JWKS{Keys: []auth.JWK{}}
+		if jwtKeys != nil {
+			jwks = jwtKeys.JWKS()
+		}
+
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		respondWithJson(w, http.StatusOK, jwks)
+	}
+}
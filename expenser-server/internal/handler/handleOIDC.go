@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/keertirajmalik/expenser/expenser-server/metrics"
+)
+
+// oidcStateCookie holds the hash of the state of the login the browser started, so a
+// callback can't finish a login started in another browser.
+const oidcStateCookie = "expenser_oidc_state"
+
+// HandleOIDCLogin sends the browser to the identity provider. With reauth=true the
+// provider signs the user in again, as confirming an account deletion without a password
+// needs.
+func HandleOIDCLogin(oidcService model.OIDCService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !oidcService.Enabled() {
+			respondWithError(w, http.StatusNotFound, "OIDC login isn't configured")
+			return
+		}
+
+		authURL, state, err := oidcService.StartLogin(r.Context(), r.URL.Query().Get("reauth") == "true")
+		if err != nil {
+			respondWithError(w, http.StatusBadGateway, "Couldn't reach the identity provider")
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     oidcStateCookie,
+			Value:    hashOIDCState(state),
+			Path:     "/cxf/",
+			MaxAge:   int(model.OIDCLoginTTL.Seconds()),
+			HttpOnly: true,
+			Secure:   strings.HasPrefix(oidcService.Provider.RedirectURL(), "https://"),
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		http.Redirect(w, r, authURL, http.StatusFound)
+	}
+}
+
+// HandleOIDCCallback finishes the login the identity provider redirects back to and
+// issues an access token, or the challenge token of HandleUserLogin to users with
+// two-factor authentication. With a post login URL the browser is sent there with the
+// token, or the error, in the fragment, otherwise it is answered like HandleUserLogin.
+func HandleOIDCCallback(oidcService model.OIDCService, twoFactorService model.TwoFactorService, jwtKeys *auth.Keys, tokenTTL time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !oidcService.Enabled() {
+			respondWithError(w, http.StatusNotFound, "OIDC login isn't configured")
+			return
+		}
+
+		fail := func(err error) {
+			metrics.LoginFailuresTotal.Inc("oidc")
+			if oidcService.PostLoginURL == "" {
+				respondWithAppError(w, err)
+				return
+			}
+			message := "Something went wrong, please try again later"
+			var unauthorizedErr *model.UnauthorizedError
+			var conflictErr *model.ConflictError
+			if errors.As(err, &unauthorizedErr) || errors.As(err, &conflictErr) {
+				message = err.Error()
+			}
+			redirectWithFragment(w, r, oidcService.PostLoginURL, url.Values{"error": {message}})
+		}
+
+		query := r.URL.Query()
+		if providerErr := query.Get("error"); providerErr != "" {
+			logger.Warn(r.Context(), "identity provider refused the login", map[string]interface{}{
+				"error":       providerErr,
+				"description": query.Get("error_description"),
+			})
+			fail(model.NewUnauthorizedError("the identity provider refused the login: " + providerErr))
+			return
+		}
+		if query.Get("state") == "" || query.Get("code") == "" {
+			fail(model.NewUnauthorizedError("state and code are required"))
+			return
+		}
+
+		cookie, err := r.Cookie(oidcStateCookie)
+		if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(hashOIDCState(query.Get("state")))) != 1 {
+			logger.Warn(r.Context(), "oidc callback state doesn't match the browser")
+			fail(model.NewUnauthorizedError("login wasn't started in this browser, please try again"))
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, Path: "/cxf/", MaxAge: -1, HttpOnly: true})
+
+		user, err := oidcService.FinishLogin(r.Context(), query.Get("state"), query.Get("code"))
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		enabled, err := twoFactorService.IsTwoFactorEnabled(r.Context(), user.ID)
+		if err != nil {
+			fail(err)
+			return
+		}
+		if enabled {
+			challengeToken, err := jwtKeys.MakeChallengeJWT(user.ID, model.TwoFactorChallengeTTL)
+			if err != nil {
+				fail(err)
+				return
+			}
+			if oidcService.PostLoginURL == "" {
+				respondWithJson(w, http.StatusOK, challengeResponse{
+					TwoFactorRequired: true,
+					ChallengeToken:    challengeToken,
+				})
+				return
+			}
+			redirectWithFragment(w, r, oidcService.PostLoginURL, url.Values{
+				"two_factor_required": {"true"},
+				"challenge_token":     {challengeToken},
+			})
+			return
+		}
+
+		if oidcService.PostLoginURL == "" {
+			respondWithLogin(w, user, jwtKeys, tokenTTL)
+			return
+		}
+
+		accessToken, err := jwtKeys.MakeJWT(user.ID, user.Role, tokenTTL)
+		if err != nil {
+			fail(err)
+			return
+		}
+		metrics.LoginsTotal.Inc()
+		redirectWithFragment(w, r, oidcService.PostLoginURL, url.Values{"token": {accessToken}})
+	}
+}
+
+// hashOIDCState returns the value of the state cookie for state. Only the hash is kept in
+// the browser so the cookie alone can't be replayed as a state.
+func hashOIDCState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// redirectWithFragment redirects to target with values in the fragment, which browsers
+// don't send to servers or put in Referer headers.
+func redirectWithFragment(w http.ResponseWriter, r *http.Request, target string, values url.Values) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	http.Redirect(w, r, target+"#"+values.Encode(), http.StatusFound)
+}
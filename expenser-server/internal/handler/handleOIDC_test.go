@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
+	"github.com/keertirajmalik/expenser/expenser-server/oidc"
+)
+
+func TestOIDCCallbackRequiresStateCookie(t *testing.T) {
+	const state = "state-of-this-browser"
+	tests := []struct {
+		name   string
+		cookie *http.Cookie
+	}{
+		{"no cookie", nil},
+		{"cookie of another login", &http.Cookie{Name: oidcStateCookie, Value: hashOIDCState("state-of-another-browser")}},
+		{"state as cookie", &http.Cookie{Name: oidcStateCookie, Value: state}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The provider is never reached, the callback is refused before the code exchange.
+			service := model.OIDCService{Provider: oidc.New(oidc.Config{Issuer: "http://idp.invalid"})}
+			callback := HandleOIDCCallback(service, model.TwoFactorService{}, nil, 0)
+
+			request := httptest.NewRequest(http.MethodGet, "/cxf/v1/oidc/callback?state="+state+"&code=code", nil)
+			if tt.cookie != nil {
+				request.AddCookie(tt.cookie)
+			}
+			recorder := httptest.NewRecorder()
+			callback(recorder, request)
+
+			if recorder.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, http.StatusUnauthorized, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestOIDCCallbackStateErrorRedirects(t *testing.T) {
+	service := model.OIDCService{
+		Provider:     oidc.New(oidc.Config{Issuer: "http://idp.invalid"}),
+		PostLoginURL: "http://ui.example/auth/oidc",
+	}
+	callback := HandleOIDCCallback(service, model.TwoFactorService{}, nil, 0)
+
+	recorder := httptest.NewRecorder()
+	callback(recorder, httptest.NewRequest(http.MethodGet, "/cxf/v1/oidc/callback?state=s&code=c", nil))
+
+	location := recorder.Header().Get("Location")
+	target, fragment, _ := strings.Cut(location, "#")
+	if recorder.Code != http.StatusFound || target != service.PostLoginURL {
+		t.Fatalf("got %d to %q, want a redirect to %s", recorder.Code, location, service.PostLoginURL)
+	}
+	values, err := url.ParseQuery(fragment)
+	if err != nil || values.Get("error") == "" || values.Get("token") != "" {
+		t.Errorf("fragment = %q, want an error and no token", fragment)
+	}
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+)
+
+// HandleOpenAPIGet serves the OpenAPI document of the API as is.
+func HandleOpenAPIGet(spec []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(spec); err != nil {
+			logger.Error(r.Context(), "failed to write openapi document", map[string]any{"error": err})
+		}
+	}
+}
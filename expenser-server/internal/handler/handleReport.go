@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+)
+
+func HandleSummaryGet(reportService model.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		now := reportService.Now(r.Context(), userID)
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 1, -1)
+
+		periodName := r.URL.Query().Get("period")
+		if periodName != "" {
+			if r.URL.Query().Get("from") != "" || r.URL.Query().Get("to") != "" {
+				respondWithError(w, http.StatusBadRequest, "period can't be combined with from and to")
+				return
+			}
+			period, err := reportService.ResolvePeriod(r.Context(), userID, periodName)
+			if err != nil {
+				respondWithAppError(w, err)
+				return
+			}
+			from, to = period.From, period.To
+		}
+
+		if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+			parsed, err := time.Parse(model.DateLayout, fromStr)
+			if err != nil {
+				respondWithError(w, http.StatusBadRequest, "invalid from date format: "+fromStr)
+				return
+			}
+			from = parsed
+		}
+		if toStr := r.URL.Query().Get("to"); toStr != "" {
+			parsed, err := time.Parse(model.DateLayout, toStr)
+			if err != nil {
+				respondWithError(w, http.StatusBadRequest, "invalid to date format: "+toStr)
+				return
+			}
+			to = parsed
+		}
+
+		summary, err := reportService.GetSummaryFromDB(r.Context(), userID, from, to)
+		if err != nil {
+			logger.Error(r.Context(), "Error while building summary", map[string]any{
+				"error": err,
+			})
+			respondWithAppError(w, err)
+			return
+		}
+		summary.Period = periodName
+
+		respondWithJson(w, http.StatusOK, summary)
+	}
+}
+
+func HandleInsightsGet(reportService model.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		months := model.DefaultInsightTrailingMonths
+		if monthsStr := r.URL.Query().Get("months"); monthsStr != "" {
+			parsed, err := strconv.Atoi(monthsStr)
+			if err != nil || parsed < 1 || parsed > model.MaxInsightTrailingMonths {
+				respondWithError(w, http.StatusBadRequest, fmt.Sprintf("months must be between 1 and %d", model.MaxInsightTrailingMonths))
+				return
+			}
+			months = parsed
+		}
+
+		insights, err := reportService.GetInsightsFromDB(r.Context(), userID, reportService.Now(r.Context(), userID), months)
+		if err != nil {
+			logger.Error(r.Context(), "Error while building insights", map[string]any{
+				"error": err,
+			})
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusOK, insights)
+	}
+}
+
+func HandleForecastGet(reportService model.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		months := model.DefaultForecastMonths
+		if monthsStr := r.URL.Query().Get("months"); monthsStr != "" {
+			parsed, err := strconv.Atoi(monthsStr)
+			if err != nil || parsed < 1 || parsed > model.MaxForecastMonths {
+				respondWithError(w, http.StatusBadRequest, fmt.Sprintf("months must be between 1 and %d", model.MaxForecastMonths))
+				return
+			}
+			months = parsed
+		}
+
+		forecast, err := reportService.GetForecastFromDB(r.Context(), userID, reportService.Now(r.Context(), userID), months)
+		if err != nil {
+			logger.Error(r.Context(), "Error while building forecast", map[string]any{
+				"error": err,
+			})
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusOK, forecast)
+	}
+}
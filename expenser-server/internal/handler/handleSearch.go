@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// HandleSearch searches the transactions, incomes and investments of the user. Results
+// can be narrowed down to a kind, a category, a period or dates from and to, and an
+// amount range.
+func HandleSearch(searchService model.SearchService, reportService model.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		query := r.URL.Query()
+		search := model.Search{
+			Query: query.Get("q"),
+			Kind:  query.Get("kind"),
+		}
+
+		if categoryStr := query.Get("category"); categoryStr != "" {
+			category, err := uuid.Parse(categoryStr)
+			if err != nil {
+				respondWithError(w, http.StatusBadRequest, "invalid category: "+categoryStr)
+				return
+			}
+			search.Category = &category
+		}
+
+		periodName := query.Get("period")
+		if periodName != "" {
+			if query.Get("from") != "" || query.Get("to") != "" {
+				respondWithError(w, http.StatusBadRequest, "period can't be combined with from and to")
+				return
+			}
+			period, err := reportService.ResolvePeriod(r.Context(), userID, periodName)
+			if err != nil {
+				respondWithAppError(w, err)
+				return
+			}
+			search.From, search.To = &period.From, &period.To
+		}
+		if fromStr := query.Get("from"); fromStr != "" {
+			parsed, err := time.Parse(model.DateLayout, fromStr)
+			if err != nil {
+				respondWithError(w, http.StatusBadRequest, "invalid from date format: "+fromStr)
+				return
+			}
+			search.From = &parsed
+		}
+		if toStr := query.Get("to"); toStr != "" {
+			parsed, err := time.Parse(model.DateLayout, toStr)
+			if err != nil {
+				respondWithError(w, http.StatusBadRequest, "invalid to date format: "+toStr)
+				return
+			}
+			search.To = &parsed
+		}
+
+		if minStr := query.Get("min_amount"); minStr != "" {
+			amount, err := decimal.NewFromString(minStr)
+			if err != nil {
+				respondWithError(w, http.StatusBadRequest, "invalid min_amount: "+minStr)
+				return
+			}
+			search.MinAmount = &amount
+		}
+		if maxStr := query.Get("max_amount"); maxStr != "" {
+			amount, err := decimal.NewFromString(maxStr)
+			if err != nil {
+				respondWithError(w, http.StatusBadRequest, "invalid max_amount: "+maxStr)
+				return
+			}
+			search.MaxAmount = &amount
+		}
+
+		if limitStr := query.Get("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil || limit < 1 {
+				respondWithError(w, http.StatusBadRequest, "invalid limit: "+limitStr)
+				return
+			}
+			search.Limit = limit
+		}
+
+		results, err := searchService.SearchEntriesInDB(r.Context(), userID, search)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusOK, results)
+	}
+}
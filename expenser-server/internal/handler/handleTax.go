@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/handler/util"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+)
+
+// defaultTaxPeriod is the period tax summaries cover when none is given.
+const defaultTaxPeriod = "fy"
+
+func HandleTaxSummaryGet(reportService model.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		summary, err := taxSummary(r, reportService, userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusOK, summary)
+	}
+}
+
+// HandleTaxSummaryExport returns the tax summary as an XLSX workbook.
+func HandleTaxSummaryExport(reportService model.ReportService, userService model.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		summary, err := taxSummary(r, reportService, userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+		preferences, err := userService.GetPreferencesFromDB(r.Context(), userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := util.WriteTaxSummary(&buf, summary, preferences); err != nil {
+			logger.Error(r.Context(), "Error while writing tax summary workbook", map[string]any{
+				"error": err,
+			})
+			respondWithError(w, http.StatusInternalServerError, "failed to export tax summary")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tax-summary-%s-%s.xlsx"`, summary.From, summary.To))
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			logger.Error(r.Context(), "failed to write tax summary workbook", map[string]any{
+				"error": err,
+			})
+		}
+	}
+}
+
+// taxSummary builds the tax summary of the period query parameter, the current fiscal
+// year by default.
+func taxSummary(r *http.Request, reportService model.ReportService, userID uuid.UUID) (model.TaxSummary, error) {
+	name := r.URL.Query().Get("period")
+	if name == "" {
+		name = defaultTaxPeriod
+	}
+	period, err := reportService.ResolvePeriod(r.Context(), userID, name)
+	if err != nil {
+		return model.TaxSummary{}, err
+	}
+
+	summary, err := reportService.GetTaxSummaryFromDB(r.Context(), userID, period)
+	if err != nil {
+		logger.Error(r.Context(), "Error while building tax summary", map[string]any{
+			"error": err,
+		})
+		return model.TaxSummary{}, err
+	}
+	return summary, nil
+}
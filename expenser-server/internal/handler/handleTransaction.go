@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -21,10 +20,10 @@ func HandleTransactionGet(transactionService model.TransactionService) http.Hand
 
 		transactions, err := transactionService.GetTransactionsFromDB(r.Context(), userID)
 		if err != nil {
-			logger.Error("Error while fetching transactions", map[string]any{
+			logger.Error(r.Context(), "Error while fetching transactions", map[string]any{
 				"error": err,
 			})
-			respondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 
@@ -42,15 +41,9 @@ func HandleTransactionCreate(transactionService model.TransactionService) http.H
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		decoder := json.NewDecoder(r.Body)
 		params := parameters{}
-		err := decoder.Decode(&params)
-		if err != nil {
-			logger.Error("Error while decoding parameters", map[string]any{
-				"params": params,
-				"error":  err,
-			})
-			respondWithError(w, http.StatusBadRequest, err.Error())
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
 			return
 		}
 
@@ -72,7 +65,7 @@ func HandleTransactionCreate(transactionService model.TransactionService) http.H
 
 		dbTransaction, err := transactionService.AddTransactionToDB(r.Context(), transaction)
 		if err != nil {
-			respondWithError(w, http.StatusBadRequest, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 		respondWithJson(w, http.StatusCreated, dbTransaction)
@@ -93,7 +86,7 @@ func HandleTransactionUpdate(transactionService model.TransactionService) http.H
 
 		id, err := uuid.Parse(idStr)
 		if err != nil {
-			logger.Error("Error while parsing transaction ID", map[string]any{
+			logger.Error(r.Context(), "Error while parsing transaction ID", map[string]any{
 				"error": err,
 				"uuid":  idStr,
 			})
@@ -101,15 +94,9 @@ func HandleTransactionUpdate(transactionService model.TransactionService) http.H
 			return
 		}
 
-		decoder := json.NewDecoder(r.Body)
 		params := parameters{}
-		err = decoder.Decode(&params)
-		if err != nil {
-			logger.Error("Error while decoding parameters", map[string]any{
-				"error":  err,
-				"params": params,
-			})
-			respondWithError(w, http.StatusBadRequest, err.Error())
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
 			return
 		}
 
@@ -131,7 +118,7 @@ func HandleTransactionUpdate(transactionService model.TransactionService) http.H
 
 		dbTransaction, err := transactionService.UpdateTransactionInDB(r.Context(), transaction)
 		if err != nil {
-			respondWithError(w, http.StatusBadRequest, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 		respondWithJson(w, http.StatusOK, dbTransaction)
@@ -144,7 +131,7 @@ func HandleTransactionDelete(transactionService model.TransactionService) http.H
 
 		id, err := uuid.Parse(idStr)
 		if err != nil {
-			logger.Error("Error while parsing uuid", map[string]any{
+			logger.Error(r.Context(), "Error while parsing uuid", map[string]any{
 				"error": err,
 				"uuid":  idStr,
 			})
@@ -159,15 +146,42 @@ func HandleTransactionDelete(transactionService model.TransactionService) http.H
 		}
 		err = transactionService.DeleteTransactionFromDB(r.Context(), id, userID)
 		if err != nil {
-			logger.Error("Error while deleting transaction", map[string]any{
+			logger.Error(r.Context(), "Error while deleting transaction", map[string]any{
 				"transaction_id": id,
 				"user_id":        userID,
 				"error":          err,
 			})
-			respondWithError(w, http.StatusBadRequest, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+func HandleTransactionBatch(transactionService model.TransactionService) http.HandlerFunc {
+	type parameters struct {
+		Operations []model.BatchOperation `json:"operations"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := parameters{}
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		results, err := transactionService.ApplyTransactionBatch(r.Context(), userID, params.Operations)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+		respondWithJson(w, http.StatusOK, results)
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
+)
+
+// HandleTwoFactorEnroll starts a TOTP enrollment and returns the secret and its otpauth
+// URI. Two-factor authentication stays off until HandleTwoFactorConfirm.
+func HandleTwoFactorEnroll(userService model.UserService, twoFactorService model.TwoFactorService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if !requireLoginSession(w, r) {
+			return
+		}
+
+		user, err := userService.GetUserByUserIdFromDB(r.Context(), userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		enrollment, err := twoFactorService.EnrollTOTP(r.Context(), userID, user.Username)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusCreated, enrollment)
+	}
+}
+
+// HandleTwoFactorConfirm enables two-factor authentication with a code from the enrolled
+// secret and returns the recovery codes, which are never shown again.
+func HandleTwoFactorConfirm(twoFactorService model.TwoFactorService) http.HandlerFunc {
+	type parameters struct {
+		Code string `json:"code" validate:"required"`
+	}
+
+	type response struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if !requireLoginSession(w, r) {
+			return
+		}
+
+		params := parameters{}
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		if err := model.Validate(params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		codes, err := twoFactorService.ConfirmTOTP(r.Context(), userID, params.Code)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusOK, response{RecoveryCodes: codes})
+	}
+}
+
+// HandleTwoFactorDisable turns two-factor authentication off. It takes a current TOTP
+// or recovery code so a stolen session alone can't remove the second factor.
+func HandleTwoFactorDisable(twoFactorService model.TwoFactorService) http.HandlerFunc {
+	type parameters struct {
+		Code string `json:"code" validate:"required"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if !requireLoginSession(w, r) {
+			return
+		}
+
+		params := parameters{}
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		if err := model.Validate(params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		if err := twoFactorService.DisableTOTP(r.Context(), userID, params.Code); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -1,7 +1,7 @@
 package handler
 
 import (
-	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -11,9 +11,13 @@ import (
 
 func HandleUserGet(userService model.UserService) http.HandlerFunc {
 	type response struct {
-		Name     string `json:"name"`
-		Username string `json:"username"`
-		Image    string `json:"image"`
+		Name       string `json:"name"`
+		Username   string `json:"username"`
+		Image      string `json:"image"`
+		Locale     string `json:"locale"`
+		DateFormat string `json:"date_format"`
+		Timezone   string `json:"timezone"`
+		Role       string `json:"role"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -25,36 +29,51 @@ func HandleUserGet(userService model.UserService) http.HandlerFunc {
 
 		user, err := userService.GetUserByUserIdFromDB(r.Context(), userID)
 		if err != nil {
-			respondWithError(w, http.StatusUnauthorized, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 
 		respondWithJson(w, http.StatusOK, response{
-			Name:     user.Name,
-			Username: user.Username,
-			Image:    user.Image,
+			Name:       user.Name,
+			Username:   user.Username,
+			Image:      user.Image,
+			Locale:     user.Locale,
+			DateFormat: user.DateFormat,
+			Timezone:   user.Timezone,
+			Role:       user.Role,
 		})
 	}
 
 }
+
+// HandleUsersGet lists every user. The route is restricted to admins.
+func HandleUsersGet(userService model.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		users, err := userService.GetUsersFromDB(r.Context())
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusOK, users)
+	}
+}
+
 func HandleUserCreate(userService model.UserService) http.HandlerFunc {
 	type parameters struct {
-		Name     string `json:"name"`
-		Username string `json:"username"`
-		Password string `json:"password"`
+		Name     string `json:"name" validate:"max=100"`
+		Username string `json:"username" validate:"required,max=50"`
+		Password string `json:"password" validate:"required,max=72"`
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
-		decoder := json.NewDecoder(r.Body)
-		decoder.DisallowUnknownFields()
 		params := parameters{}
-		err := decoder.Decode(&params)
-		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters")
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
 			return
 		}
 
-		if params.Username == "" || params.Password == "" {
-			respondWithError(w, http.StatusBadRequest, "Provide valid credentials")
+		if err := model.Validate(params); err != nil {
+			respondWithAppError(w, err)
 			return
 		}
 
@@ -67,13 +86,14 @@ func HandleUserCreate(userService model.UserService) http.HandlerFunc {
 		user, err := userService.AddUserToDB(r.Context(), model.User{ID: uuid.New(), Name: params.Name, Username: params.Username, HashedPassword: hashedPassword})
 
 		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 		respondWithJson(w, http.StatusCreated, model.User{
 			ID:       user.ID,
 			Username: user.Username,
 			Name:     user.Name,
+			Role:     user.Role,
 		})
 	}
 
@@ -81,8 +101,11 @@ func HandleUserCreate(userService model.UserService) http.HandlerFunc {
 
 func HandleUserUpdate(userService model.UserService) http.HandlerFunc {
 	type parameters struct {
-		Name  string `json:"name"`
-		Image string `json:"image"`
+		Name       string `json:"name" validate:"max=100"`
+		Image      string `json:"image"`
+		Locale     string `json:"locale" validate:"locale"`
+		DateFormat string `json:"date_format" validate:"dateformat"`
+		Timezone   string `json:"timezone" validate:"timezone"`
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID, ok := auth.UserIDFromContext(r.Context())
@@ -91,31 +114,215 @@ func HandleUserUpdate(userService model.UserService) http.HandlerFunc {
 			return
 		}
 
-		decoder := json.NewDecoder(r.Body)
-		decoder.DisallowUnknownFields()
 		params := parameters{}
-		err := decoder.Decode(&params)
-		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters")
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		if err := model.Validate(params); err != nil {
+			respondWithAppError(w, err)
 			return
 		}
 
 		user, err := userService.UpdateUserInDB(r.Context(), model.User{
-			ID:    userID,
-			Name:  params.Name,
-			Image: params.Image,
+			ID:         userID,
+			Name:       params.Name,
+			Image:      params.Image,
+			Locale:     params.Locale,
+			DateFormat: params.DateFormat,
+			Timezone:   params.Timezone,
 		})
 
 		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, err.Error())
+			respondWithAppError(w, err)
 			return
 		}
 		respondWithJson(w, http.StatusOK, model.User{
-			ID:       user.ID,
-			Username: user.Username,
-			Name:     user.Name,
-			Image:    user.Image,
+			ID:         user.ID,
+			Username:   user.Username,
+			Name:       user.Name,
+			Image:      user.Image,
+			Locale:     user.Locale,
+			DateFormat: user.DateFormat,
+			Timezone:   user.Timezone,
+			Role:       user.Role,
 		})
 	}
 
 }
+
+func HandlePreferencesGet(userService model.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		preferences, err := userService.GetPreferencesFromDB(r.Context(), userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusOK, preferences)
+	}
+}
+
+// HandlePreferencesUpdate changes the preferences present in the body and leaves the
+// others alone.
+func HandlePreferencesUpdate(userService model.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		params := model.PreferencesUpdate{}
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		preferences, err := userService.UpdatePreferencesInDB(r.Context(), userID, params)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithJson(w, http.StatusOK, preferences)
+	}
+}
+
+// HandleUserExport returns everything stored about the user, only the entries of a
+// period when one is asked for.
+func HandleUserExport(userService model.UserService, reportService model.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		var period *model.Period
+		if name := r.URL.Query().Get("period"); name != "" {
+			resolved, err := reportService.ResolvePeriod(r.Context(), userID, name)
+			if err != nil {
+				respondWithAppError(w, err)
+				return
+			}
+			period = &resolved
+		}
+
+		export, err := userService.ExportUserData(r.Context(), userID, period)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Disposition", `attachment; filename="expenser-export.json"`)
+		respondWithJson(w, http.StatusOK, export)
+	}
+}
+
+// HandleUserDelete deletes the account of the user and all their data after checking
+// the password, and the second factor when enabled. Users created through single sign-on
+// have no password to check and must have signed in again with the provider within
+// model.SSOReauthWindow instead. With export set the data is
+// returned as by HandleUserExport, read just before deleting it.
+func HandleUserDelete(userService model.UserService, twoFactorService model.TwoFactorService) http.HandlerFunc {
+	type parameters struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+		Export   bool   `json:"export"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if !requireLoginSession(w, r) {
+			return
+		}
+
+		params := parameters{}
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		if err := model.Validate(params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		user, err := userService.GetUserByUserIdFromDB(r.Context(), userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+		if user.PasswordSet {
+			if params.Password == "" {
+				respondWithAppError(w, model.NewValidationError("password", model.FieldRequired, "password is required"))
+				return
+			}
+			if err := auth.CheckPasswordHash(params.Password, user.HashedPassword); err != nil {
+				respondWithAppError(w, model.NewUnauthorizedError("invalid password"))
+				return
+			}
+		} else {
+			recent, err := userService.RecentlySignedInWithSSO(r.Context(), userID)
+			if err != nil {
+				respondWithAppError(w, err)
+				return
+			}
+			if !recent {
+				respondWithAppError(w, model.NewUnauthorizedError(fmt.Sprintf(
+					"sign in again with single sign-on (/cxf/v1/oidc/login?reauth=true) within %d minutes before deleting the account",
+					int(model.SSOReauthWindow.Minutes()))))
+				return
+			}
+		}
+
+		enabled, err := twoFactorService.IsTwoFactorEnabled(r.Context(), userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+		if enabled {
+			if params.Code == "" {
+				respondWithAppError(w, model.NewValidationError("code", model.FieldRequired, "code is required with two-factor authentication"))
+				return
+			}
+			if err := twoFactorService.VerifySecondFactor(r.Context(), userID, params.Code); err != nil {
+				respondWithAppError(w, err)
+				return
+			}
+		}
+
+		var export model.UserExport
+		if params.Export {
+			export, err = userService.ExportUserData(r.Context(), userID, nil)
+			if err != nil {
+				respondWithAppError(w, err)
+				return
+			}
+		}
+
+		if err := userService.DeleteUserFromDB(r.Context(), userID); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		if params.Export {
+			w.Header().Set("Content-Disposition", `attachment; filename="expenser-export.json"`)
+			respondWithJson(w, http.StatusOK, export)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -1,60 +1,146 @@
 package handler
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/keertirajmalik/expenser/expenser-server/auth"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
+	"github.com/keertirajmalik/expenser/expenser-server/metrics"
 )
 
-func HandleUserLogin(userService model.UserService, jwtSecret string) http.HandlerFunc {
+type loginResponse struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Token    string `json:"token"`
+	Image    string `json:"image"`
+}
+
+// challengeResponse answers a login of a user with two-factor authentication.
+type challengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+}
+
+// HandleUserLogin checks the password and issues an access token. Users with two-factor
+// authentication get a short-lived challenge token instead, to exchange on
+// /cxf/v1/login/2fa with a code.
+func HandleUserLogin(userService model.UserService, twoFactorService model.TwoFactorService, jwtKeys *auth.Keys, tokenTTL time.Duration) http.HandlerFunc {
 	type parameters struct {
 		Username string `json:"username"`
 		Password string `json:"password"`
 	}
 
-	type response struct {
-		Name     string `json:"name"`
-		Username string `json:"username"`
-		Token    string `json:"token"`
-		Image    string `json:"image"`
-	}
-
 	return func(w http.ResponseWriter, r *http.Request) {
-		decoder := json.NewDecoder(r.Body)
 		params := parameters{}
-		err := decoder.Decode(&params)
-		if err != nil {
-			respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
 			return
 		}
 
 		user, err := userService.GetUserByUsernameFromDB(r.Context(), params.Username)
-		if err != nil {
+		var notFoundErr *model.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			metrics.LoginFailuresTotal.Inc("unknown_user")
 			respondWithError(w, http.StatusUnauthorized, "Invalid credentials")
 			return
 		}
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
 
 		err = auth.CheckPasswordHash(params.Password, user.HashedPassword)
 		if err != nil {
+			metrics.LoginFailuresTotal.Inc("invalid_password")
 			respondWithError(w, http.StatusUnauthorized, "Invalid credentials")
 			return
 		}
 
-		accessToken, err := auth.MakeJWT(user.ID, []byte(jwtSecret), time.Hour)
+		enabled, err := twoFactorService.IsTwoFactorEnabled(r.Context(), user.ID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+		if enabled {
+			challengeToken, err := jwtKeys.MakeChallengeJWT(user.ID, model.TwoFactorChallengeTTL)
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, "Couldn't create JWT")
+				return
+			}
+
+			respondWithJson(w, http.StatusOK, challengeResponse{
+				TwoFactorRequired: true,
+				ChallengeToken:    challengeToken,
+			})
+			return
+		}
+
+		respondWithLogin(w, user, jwtKeys, tokenTTL)
+	}
+
+}
+
+// HandleTwoFactorLogin exchanges the challenge token of HandleUserLogin and a TOTP or
+// recovery code for an access token.
+func HandleTwoFactorLogin(userService model.UserService, twoFactorService model.TwoFactorService, jwtKeys *auth.Keys, tokenTTL time.Duration) http.HandlerFunc {
+	type parameters struct {
+		ChallengeToken string `json:"challenge_token" validate:"required"`
+		Code           string `json:"code" validate:"required"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := parameters{}
+		if err := decodeJSON(w, r, &params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		if err := model.Validate(params); err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		userID, err := jwtKeys.ValidateChallengeJWT(params.ChallengeToken)
 		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, "Couldn't create JWT")
+			metrics.LoginFailuresTotal.Inc("invalid_challenge")
+			respondWithError(w, http.StatusUnauthorized, "Invalid or expired challenge, log in again")
 			return
 		}
 
-		respondWithJson(w, http.StatusOK, response{
-			Name:     user.Name,
-			Username: user.Username,
-			Token:    accessToken,
-			Image:    user.Image,
-		})
+		err = twoFactorService.VerifySecondFactor(r.Context(), userID, params.Code)
+		var unauthorizedErr *model.UnauthorizedError
+		if errors.As(err, &unauthorizedErr) {
+			metrics.LoginFailuresTotal.Inc("invalid_code")
+		}
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		user, err := userService.GetUserByUserIdFromDB(r.Context(), userID)
+		if err != nil {
+			respondWithAppError(w, err)
+			return
+		}
+
+		respondWithLogin(w, user, jwtKeys, tokenTTL)
+	}
+}
+
+func respondWithLogin(w http.ResponseWriter, user model.User, jwtKeys *auth.Keys, tokenTTL time.Duration) {
+	accessToken, err := jwtKeys.MakeJWT(user.ID, user.Role, tokenTTL)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create JWT")
+		return
 	}
 
+	metrics.LoginsTotal.Inc()
+	respondWithJson(w, http.StatusOK, loginResponse{
+		Name:     user.Name,
+		Username: user.Username,
+		Token:    accessToken,
+		Image:    user.Image,
+	})
 }
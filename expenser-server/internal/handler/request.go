@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
+)
+
+// maxBodyBytes caps the size of JSON request bodies.
+const maxBodyBytes = 1 << 20
+
+// decodeJSON decodes the JSON body of r into v. Bodies larger than maxBodyBytes,
+// unknown fields and trailing data are rejected; decoding errors are returned as a
+// model.ValidationError naming the offending field.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	return decode(w, r, v, false)
+}
+
+// decodeOptionalJSON is decodeJSON for endpoints where the body may be left out,
+// in which case v is left untouched.
+func decodeOptionalJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	return decode(w, r, v, true)
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any, optional bool) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		if optional && errors.Is(err, io.EOF) {
+			return nil
+		}
+		return decodeError(err)
+	}
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return model.NewValidationError("body", model.FieldInvalid, "body must contain a single JSON object")
+	}
+	return nil
+}
+
+func decodeError(err error) error {
+	var (
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+		maxBytesErr *http.MaxBytesError
+	)
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return maxBytesErr
+	case errors.Is(err, io.EOF):
+		return model.NewValidationError("body", model.FieldRequired, "body is required")
+	case errors.As(err, &syntaxErr):
+		return model.NewValidationError("body", model.FieldInvalid, fmt.Sprintf("malformed JSON at position %d", syntaxErr.Offset))
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return model.NewValidationError("body", model.FieldInvalid, "malformed JSON")
+	case errors.As(err, &typeErr):
+		return model.NewValidationError(typeErr.Field, model.FieldInvalid, fmt.Sprintf("%s must be of type %s", typeErr.Field, typeErr.Type))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return model.NewValidationError(field, model.FieldUnknown, fmt.Sprintf("unknown field %s", field))
+	}
+	return model.NewValidationError("body", model.FieldInvalid, err.Error())
+}
@@ -1,35 +1,100 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
 	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/keertirajmalik/expenser/expenser-server/middleware"
+	"github.com/keertirajmalik/expenser/expenser-server/problem"
 )
 
-func respondWithError(w http.ResponseWriter, code int, msg string) {
-	if code > 499 {
-		logger.Error("Responding with 5XX error", map[string]interface{}{
-			"code":    code,
-			"message": msg,
+// responseContext rebuilds the logging context from the request ID the RequestID
+// middleware put on the response, as the responders don't get the request.
+func responseContext(w http.ResponseWriter) context.Context {
+	return logger.ContextWithRequestID(context.Background(), w.Header().Get(middleware.RequestIDHeader))
+}
+
+// respondWithError writes a problem+json response with the generic code of status.
+func respondWithError(w http.ResponseWriter, status int, msg string) {
+	respondWithProblem(w, problem.New(status, "", msg))
+}
+
+// respondWithAppError maps an error returned by a service to its problem+json
+// response. Errors that aren't typed domain errors are logged and hidden behind a
+// generic 500 so no internal detail reaches the client.
+func respondWithAppError(w http.ResponseWriter, err error) {
+	var (
+		validationErr   *model.ValidationError
+		notFoundErr     *model.NotFoundError
+		conflictErr     *model.ConflictError
+		forbiddenErr    *model.ForbiddenError
+		unauthorizedErr *model.UnauthorizedError
+		batchErr        *model.ErrBatchFailed
+		duplicateErr    *database.ErrDuplicateData
+		foreignKeyErr   *database.ErrForeignKeyViolation
+		maxBytesErr     *http.MaxBytesError
+	)
+
+	var p problem.Details
+	switch {
+	case errors.As(err, &validationErr):
+		p = problem.New(http.StatusBadRequest, validationErr.Code(), validationErr.Error())
+		p.Errors = validationErr.Fields
+	case errors.As(err, &batchErr):
+		p = problem.New(http.StatusBadRequest, batchErr.Code(), batchErr.Error())
+		p.Errors = batchErr.Errors
+	case errors.As(err, &notFoundErr):
+		p = problem.New(http.StatusNotFound, notFoundErr.Code(), notFoundErr.Error())
+	case errors.As(err, &conflictErr):
+		p = problem.New(http.StatusConflict, conflictErr.Code(), conflictErr.Error())
+	case errors.As(err, &forbiddenErr):
+		p = problem.New(http.StatusForbidden, forbiddenErr.Code(), forbiddenErr.Error())
+	case errors.As(err, &unauthorizedErr):
+		p = problem.New(http.StatusUnauthorized, unauthorizedErr.Code(), unauthorizedErr.Error())
+	case errors.As(err, &maxBytesErr):
+		p = problem.New(http.StatusRequestEntityTooLarge, "", fmt.Sprintf("body must not be larger than %d bytes", maxBytesErr.Limit))
+	case errors.As(err, &duplicateErr):
+		p = problem.New(http.StatusConflict, model.CodeDuplicate, duplicateErr.Error())
+	case errors.As(err, &foreignKeyErr):
+		p = problem.New(http.StatusConflict, model.CodeConflict, foreignKeyErr.Error())
+	default:
+		logger.Error(responseContext(w), "Unhandled error", map[string]any{
+			"error": err,
 		})
+		p = problem.New(http.StatusInternalServerError, "", "Something went wrong, please try again later")
 	}
 
-	type errorResponse struct {
-		Error string `json:"error"`
+	respondWithProblem(w, p)
+}
+
+func respondWithProblem(w http.ResponseWriter, p problem.Details) {
+	if p.Status > 499 {
+		logger.Error(responseContext(w), "Responding with 5XX error", map[string]interface{}{
+			"code":    p.Status,
+			"message": p.Detail,
+		})
 	}
 
-	respondWithJson(w, code, errorResponse{
-		Error: msg,
-	})
+	p.RequestID = w.Header().Get(middleware.RequestIDHeader)
+	err := problem.Write(w, p)
+	if err != nil {
+		logger.Error(responseContext(w), "Error writing response", map[string]interface{}{
+			"error": err,
+		})
+	}
 }
 
 func respondWithJson(w http.ResponseWriter, code int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	dat, err := json.Marshal(payload)
 	if err != nil {
-		logger.Error("Error marshalling JSON", map[string]interface{}{
+		logger.Error(responseContext(w), "Error marshalling JSON", map[string]interface{}{
 			"payload": payload,
 			"error":   err,
 		})
@@ -41,7 +106,7 @@ func respondWithJson(w http.ResponseWriter, code int, payload interface{}) {
 	w.WriteHeader(code)
 	_, err = w.Write(dat)
 	if err != nil {
-		logger.Error("Error writing response", map[string]interface{}{
+		logger.Error(responseContext(w), "Error writing response", map[string]interface{}{
 			"error": err,
 		})
 		w.WriteHeader(500)
@@ -1,8 +1,12 @@
 package util
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
 	"github.com/keertirajmalik/expenser/expenser-server/logger"
@@ -10,10 +14,20 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
-func ReadExcelFile(filename string) ([]model.BulkTransaction, error) {
+// statementHeader matches the header of the transaction table. The currency of the
+// amount column is not checked, statements are imported as they are whatever the base
+// currency preference of the user is.
+var statementHeader = regexp.MustCompile(`Serial Number Transaction Date Transaction Remark CR/DR Amount\([^()]*\)`)
+
+// ReadExcelFile reads the transactions of a bank statement. Transaction dates are parsed
+// with their date format preference and returned in model.DateLayout, and rows get the
+// default expense or income category of the user.
+func ReadExcelFile(ctx context.Context, filename string, preferences model.Preferences) ([]model.BulkTransaction, error) {
+	dateLayout := model.DateFormatLayout(preferences.DateFormat)
+
 	f, err := excelize.OpenFile(filename)
 	if err != nil {
-		logger.Error("Error while reading the file content", map[string]any{
+		logger.Error(ctx, "Error while reading the file content", map[string]any{
 			"error":    err.Error(),
 			"filename": filename,
 		})
@@ -21,7 +35,7 @@ func ReadExcelFile(filename string) ([]model.BulkTransaction, error) {
 	}
 	defer func() {
 		if cerr := f.Close(); cerr != nil {
-			logger.Error("failed to close excel file", map[string]any{
+			logger.Error(ctx, "failed to close excel file", map[string]any{
 				"error":    cerr.Error(),
 				"filename": filename,
 			})
@@ -35,24 +49,23 @@ func ReadExcelFile(filename string) ([]model.BulkTransaction, error) {
 	}
 	rows, err := f.GetRows(sheet)
 	if err != nil {
-		logger.Error("empty excel sheet uploaded", map[string]any{
+		logger.Error(ctx, "empty excel sheet uploaded", map[string]any{
 			"error":    err.Error(),
 			"filename": filename,
 		})
 		return nil, err
 	}
 	if len(rows) == 0 {
-		logger.Error("excel file contains no rows", map[string]any{
+		logger.Error(ctx, "excel file contains no rows", map[string]any{
 			"filename": filename,
 		})
 		return nil, errors.New("excel file contains no rows")
 	}
 
 	//check for the table titles
-	expectedHeader := "Serial Number Transaction Date Transaction Remark CR/DR Amount(INR)"
 	actualHeader := strings.Join(rows[0], " ")
-	if !strings.Contains(actualHeader, expectedHeader) {
-		logger.Error("remove the extra cell from sheet till the transaction table header", map[string]any{
+	if !statementHeader.MatchString(actualHeader) {
+		logger.Error(ctx, "remove the extra cell from sheet till the transaction table header", map[string]any{
 			"error":  "file has additional cells on top of transactions table keep only the transaction details table",
 			"header": rows[0],
 		})
@@ -67,7 +80,7 @@ func ReadExcelFile(filename string) ([]model.BulkTransaction, error) {
 			continue
 		}
 		if len(row) < 5 {
-			logger.Error("remove the extra cell in sheet from the bottom of transaction table", map[string]any{
+			logger.Error(ctx, "remove the extra cell in sheet from the bottom of transaction table", map[string]any{
 				"error": "file has additional cells on bottom of transaction table ",
 				"row":   row,
 			})
@@ -76,23 +89,49 @@ func ReadExcelFile(filename string) ([]model.BulkTransaction, error) {
 
 		amount, err := decimal.NewFromString(strings.SplitAfter(strings.ReplaceAll(row[4], ",", ""), " ")[1])
 		if err != nil {
-			logger.Error("unable to parse amount", map[string]any{
+			logger.Error(ctx, "unable to parse amount", map[string]any{
 				"error":    err.Error(),
 				"cell":     row[4],
 				"filename": filename,
 			})
-			return nil, errors.New("unable to parse amount; expected like: INR 5,000.00")
+			return nil, fmt.Errorf("unable to parse amount; expected like: %s 5,000.00", preferences.BaseCurrency)
+		}
+
+		date, err := parseStatementDate(row[1], dateLayout)
+		if err != nil {
+			logger.Error(ctx, "unable to parse date", map[string]any{
+				"error":    err.Error(),
+				"cell":     row[1],
+				"filename": filename,
+			})
+			return nil, fmt.Errorf("unable to parse date %q; expected like: %s", strings.TrimSpace(row[1]), time.Date(2025, time.September, 11, 0, 0, 0, 0, time.UTC).Format(dateLayout))
 		}
 
 		transactionType := strings.ToLower(strings.Trim(strings.TrimSpace(row[3]), "."))
+		expense := transactionType == "dr"
+		category := preferences.DefaultCategories[model.CategoryTypeIncome]
+		if expense {
+			category = preferences.DefaultCategories[model.CategoryTypeExpense]
+		}
 
 		transaction := model.BulkTransaction{
-			Name:    strings.TrimSpace(row[2]),
-			Date:    strings.TrimSpace(row[1]),
-			Expense: transactionType == "dr",
-			Amount:  amount,
+			Name:     strings.TrimSpace(row[2]),
+			Date:     date.Format(model.DateLayout),
+			Expense:  expense,
+			Amount:   amount,
+			Category: category,
 		}
 		transactions = append(transactions, transaction)
 	}
 	return transactions, nil
 }
+
+// parseStatementDate parses the date cell of a statement row. Some banks append the
+// time of the transaction, which is dropped.
+func parseStatementDate(cell, layout string) (time.Time, error) {
+	fields := strings.Fields(cell)
+	if len(fields) == 0 {
+		return time.Time{}, errors.New("empty date")
+	}
+	return time.Parse(layout, fields[0])
+}
@@ -0,0 +1,106 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+)
+
+// WriteTaxSummary writes summary to w as an XLSX workbook with a Deductions and an Income
+// sheet. Amounts are numbers in the base currency of the summary and the period dates
+// are written in the date format preference of the user.
+func WriteTaxSummary(w io.Writer, summary model.TaxSummary, preferences model.Preferences) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	dateLayout := model.DateFormatLayout(preferences.DateFormat)
+	from, err := time.Parse(model.DateLayout, summary.From)
+	if err != nil {
+		return err
+	}
+	to, err := time.Parse(model.DateLayout, summary.To)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("Tax summary %s to %s (%s)", from.Format(dateLayout), to.Format(dateLayout), summary.Currency)
+
+	if err := f.SetSheetName(f.GetSheetName(0), "Deductions"); err != nil {
+		return err
+	}
+	deductions := [][]any{
+		{title},
+		{},
+		{"Section", "Category", "Invested", "Cap", "Eligible", "Remaining"},
+	}
+	for _, deduction := range summary.Deductions {
+		deductions = append(deductions, []any{
+			deduction.Section, "", amount(deduction.Invested), optionalAmount(deduction.Cap),
+			amount(deduction.Eligible), optionalAmount(deduction.Remaining),
+		})
+		for _, category := range deduction.Categories {
+			deductions = append(deductions, []any{"", category.Name, amount(category.Total)})
+		}
+	}
+	deductions = append(deductions, []any{}, []any{"Total deductions", "", "", "", amount(summary.TotalDeductions)})
+	if err := writeRows(f, "Deductions", deductions); err != nil {
+		return err
+	}
+
+	if _, err := f.NewSheet("Income"); err != nil {
+		return err
+	}
+	incomes := [][]any{
+		{title},
+		{},
+		{"Category", "Tax exempt", "Total"},
+	}
+	for _, income := range summary.Incomes {
+		exempt := "No"
+		if income.TaxExempt {
+			exempt = "Yes"
+		}
+		incomes = append(incomes, []any{income.Name, exempt, amount(income.Total)})
+	}
+	incomes = append(incomes,
+		[]any{},
+		[]any{"Taxable income", "", amount(summary.TaxableIncome)},
+		[]any{"Exempt income", "", amount(summary.ExemptIncome)},
+	)
+	if err := writeRows(f, "Income", incomes); err != nil {
+		return err
+	}
+
+	return f.Write(w)
+}
+
+func writeRows(f *excelize.File, sheet string, rows [][]any) error {
+	for i, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func amount(value decimal.Decimal) float64 {
+	return value.InexactFloat64()
+}
+
+// optionalAmount leaves the cell empty for sections without a cap.
+func optionalAmount(value *decimal.Decimal) any {
+	if value == nil {
+		return ""
+	}
+	return value.InexactFloat64()
+}
@@ -0,0 +1,161 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+)
+
+// SSOReauthWindow is how recent a single sign-on must be to confirm the deletion of an
+// account created through single sign-on, whose password nobody knows.
+const SSOReauthWindow = 5 * time.Minute
+
+// UserExport is everything stored about a user, as returned by the export and, on
+// request, by the account deletion. Secrets like the password hash, the TOTP secret and
+// the API token hashes are left out. Exports of a period only hold the transactions,
+// incomes and investments dated in it.
+type UserExport struct {
+	ExportedAt   time.Time             `json:"exported_at"`
+	Period       string                `json:"period,omitempty"`
+	From         string                `json:"from,omitempty"`
+	To           string                `json:"to,omitempty"`
+	User         User                  `json:"user"`
+	Preferences  Preferences           `json:"preferences"`
+	Categories   []ResponseCategory    `json:"categories"`
+	Transactions []ResponseTransaction `json:"transactions"`
+	Incomes      []ResponseIncome      `json:"incomes"`
+	Investments  []ResponseInvestment  `json:"investments"`
+	Goals        []ResponseGoal        `json:"goals"`
+	APITokens    []APIToken            `json:"api_tokens"`
+}
+
+// ExportUserData collects the data of the user, the entries of period only when it
+// isn't nil.
+func (s UserService) ExportUserData(ctx context.Context, userID uuid.UUID, period *Period) (UserExport, error) {
+	export := UserExport{ExportedAt: time.Now().UTC()}
+
+	var err error
+	if export.User, err = s.GetUserByUserIdFromDB(ctx, userID); err != nil {
+		return UserExport{}, err
+	}
+	if export.Preferences, err = s.GetPreferencesFromDB(ctx, userID); err != nil {
+		return UserExport{}, err
+	}
+	if export.Categories, err = (CategoryService{Queries: s.Queries, DB: s.DB}).GetCategoriesFromDB(ctx, userID); err != nil {
+		return UserExport{}, err
+	}
+	if export.Transactions, err = (TransactionService{Queries: s.Queries, DB: s.DB}).GetTransactionsFromDB(ctx, userID); err != nil {
+		return UserExport{}, err
+	}
+	if export.Incomes, err = (IncomeService{Queries: s.Queries, DB: s.DB}).GetIncomesFromDB(ctx, userID); err != nil {
+		return UserExport{}, err
+	}
+	if export.Investments, err = (InvestmentService{Queries: s.Queries, DB: s.DB}).GetInvestmentsFromDB(ctx, userID); err != nil {
+		return UserExport{}, err
+	}
+	if export.Goals, err = (GoalService{Queries: s.Queries, DB: s.DB}).GetGoalsFromDB(ctx, userID); err != nil {
+		return UserExport{}, err
+	}
+	if export.APITokens, err = (APITokenService{Queries: s.Queries}).GetAPITokensFromDB(ctx, userID); err != nil {
+		return UserExport{}, err
+	}
+
+	if period != nil {
+		export.Period = period.Name
+		export.From = period.From.Format(DateLayout)
+		export.To = period.To.Format(DateLayout)
+		export.Transactions = inPeriod(export.Transactions, period, func(t ResponseTransaction) string { return t.Date })
+		export.Incomes = inPeriod(export.Incomes, period, func(i ResponseIncome) string { return i.Date })
+		export.Investments = inPeriod(export.Investments, period, func(i ResponseInvestment) string { return i.Date })
+	}
+
+	return export, nil
+}
+
+// inPeriod returns the entries dated in period.
+func inPeriod[T any](entries []T, period *Period, date func(T) string) []T {
+	kept := []T{}
+	for _, entry := range entries {
+		if period.Contains(date(entry)) {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// RecentlySignedInWithSSO reports whether the identity provider authenticated the user
+// within SSOReauthWindow, on a login with any of their linked identities. Logins whose
+// ID token had no auth_time never count.
+func (s UserService) RecentlySignedInWithSSO(ctx context.Context, userID uuid.UUID) (bool, error) {
+	authenticatedAt, err := s.Queries.GetLastIdentityAuthentication(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "failed to get last identity authentication", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return false, err
+	}
+	return authenticatedAt.Valid && time.Since(authenticatedAt.Time) <= SSOReauthWindow, nil
+}
+
+// DeleteUserFromDB erases the user and all their data in one transaction and revokes the
+// access tokens issued to them. The user's rows are deleted children first because the
+// foreign keys on users and categories restrict deletes; API tokens, two-factor settings
+// and linked identities go with the user row.
+func (s UserService) DeleteUserFromDB(ctx context.Context, userID uuid.UUID) error {
+	err := database.WithTx(ctx, s.DB, func(tx pgx.Tx) error {
+		queries := s.Queries.WithTx(tx)
+
+		steps := []func(context.Context, uuid.UUID) error{
+			queries.DeleteUserGoals,
+			queries.DeleteUserTransactions,
+			queries.DeleteUserIncomes,
+			queries.DeleteUserInvestments,
+			queries.DetachUserCategories,
+			queries.DeleteUserCategories,
+		}
+		for _, step := range steps {
+			if err := step(ctx, userID); err != nil {
+				return err
+			}
+		}
+
+		result, err := queries.DeleteUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return NewNotFoundError("user")
+		}
+
+		return queries.RevokeUser(ctx, userID)
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to delete user", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return err
+	}
+
+	logger.Info(ctx, "user deleted their account", map[string]interface{}{"user_id": userID})
+	return nil
+}
+
+// IsUserRevoked reports whether the access tokens of the user were revoked by deleting
+// the account.
+func (s UserService) IsUserRevoked(ctx context.Context, userID uuid.UUID) (bool, error) {
+	revoked, err := s.Queries.IsUserRevoked(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "failed to check token revocation", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return false, err
+	}
+	return revoked, nil
+}
@@ -0,0 +1,159 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+)
+
+const (
+	DefaultAPITokenExpiryDays = 90
+	MaxAPITokenExpiryDays     = 365
+)
+
+// APIToken is a personal API token as listed to its owner. Only its prefix is kept in
+// clear, the token itself is shown once when it is created.
+type APIToken struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Scope      string     `json:"scope"`
+	Prefix     string     `json:"prefix"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewAPIToken is a token to store. Hash and Prefix come from auth.GenerateAPIToken.
+type NewAPIToken struct {
+	UserID    uuid.UUID
+	Name      string
+	Scope     string
+	Hash      string
+	Prefix    string
+	ExpiresAt time.Time
+}
+
+type APITokenService struct {
+	Queries *repository.Queries
+}
+
+func (s APITokenService) CreateAPITokenInDB(ctx context.Context, token NewAPIToken) (APIToken, error) {
+	dbToken, err := s.Queries.CreateAPIToken(ctx, repository.CreateAPITokenParams{
+		ID:          uuid.New(),
+		UserID:      token.UserID,
+		Name:        token.Name,
+		Scope:       token.Scope,
+		TokenHash:   token.Hash,
+		TokenPrefix: token.Prefix,
+		ExpiresAt:   pgtype.Timestamptz{Time: token.ExpiresAt, Valid: true},
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to create api token", map[string]interface{}{
+			"user_id": token.UserID,
+			"error":   err,
+		})
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeUniqueViolation {
+			return APIToken{}, NewConflictError(CodeDuplicate, fmt.Sprintf("%s already exist", token.Name))
+		}
+		return APIToken{}, err
+	}
+
+	return convertDBAPITokenToAPIToken(dbToken), nil
+}
+
+func (s APITokenService) GetAPITokensFromDB(ctx context.Context, userID uuid.UUID) ([]APIToken, error) {
+	dbTokens, err := s.Queries.GetAPITokens(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "failed to get api tokens", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return []APIToken{}, err
+	}
+
+	tokens := []APIToken{}
+	for _, dbToken := range dbTokens {
+		tokens = append(tokens, convertDBAPITokenToAPIToken(dbToken))
+	}
+	return tokens, nil
+}
+
+func (s APITokenService) DeleteAPITokenFromDB(ctx context.Context, id, userID uuid.UUID) error {
+	result, err := s.Queries.DeleteAPIToken(ctx, repository.DeleteAPITokenParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to delete api token", map[string]interface{}{
+			"token_id": id,
+			"user_id":  userID,
+			"error":    err,
+		})
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		logger.Warn(ctx, fmt.Sprintf("api token %s not found for user %s", id, userID))
+		return NewNotFoundError("api_token")
+	}
+
+	return nil
+}
+
+// LookupAPIToken returns who the token with the given hash authenticates as and records
+// that it was used. ok is false for unknown and expired tokens.
+func (s APITokenService) LookupAPIToken(ctx context.Context, hash string) (identity auth.Identity, ok bool, err error) {
+	dbToken, err := s.Queries.GetAPITokenByHash(ctx, hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return auth.Identity{}, false, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "failed to get api token", map[string]interface{}{"error": err})
+		return auth.Identity{}, false, err
+	}
+
+	if !dbToken.ExpiresAt.Time.After(time.Now()) {
+		logger.Warn(ctx, "expired api token used", map[string]interface{}{"token_id": dbToken.ID})
+		return auth.Identity{}, false, nil
+	}
+
+	// A failed timestamp update must not fail the request it was made for.
+	if err := s.Queries.TouchAPIToken(ctx, dbToken.ID); err != nil {
+		logger.Warn(ctx, "failed to record api token use", map[string]interface{}{
+			"token_id": dbToken.ID,
+			"error":    err,
+		})
+	}
+
+	return auth.Identity{
+		UserID: dbToken.UserID,
+		Role:   dbToken.Role,
+		Scope:  dbToken.Scope,
+	}, true, nil
+}
+
+func convertDBAPITokenToAPIToken(dbToken repository.ApiToken) APIToken {
+	token := APIToken{
+		ID:        dbToken.ID,
+		Name:      dbToken.Name,
+		Scope:     dbToken.Scope,
+		Prefix:    dbToken.TokenPrefix,
+		ExpiresAt: dbToken.ExpiresAt.Time,
+		CreatedAt: dbToken.CreatedAt.Time,
+	}
+	if dbToken.LastUsedAt.Valid {
+		token.LastUsedAt = &dbToken.LastUsedAt.Time
+	}
+	return token
+}
@@ -0,0 +1,176 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+)
+
+// MaxBatchSize caps the number of operations accepted in a single batch request.
+const MaxBatchSize = 500
+
+type BatchOperation struct {
+	Op       string          `json:"op"`
+	ID       uuid.UUID       `json:"id"`
+	Name     string          `json:"name"`
+	Amount   decimal.Decimal `json:"amount"`
+	Category uuid.UUID       `json:"category"`
+	Date     string          `json:"date"`
+	Note     string          `json:"note"`
+}
+
+type BatchResult struct {
+	Index int       `json:"index"`
+	Op    string    `json:"op"`
+	ID    uuid.UUID `json:"id"`
+	Data  any       `json:"data,omitempty"`
+}
+
+type BatchItemError struct {
+	Index  int          `json:"index"`
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// batchPayload holds the fields create and update operations are validated against.
+type batchPayload struct {
+	Name     string          `json:"name" validate:"required,max=100"`
+	Amount   decimal.Decimal `json:"amount" validate:"positive"`
+	Category uuid.UUID       `json:"category" validate:"required"`
+	Date     string          `json:"date" validate:"required,date,notfuture"`
+	Note     string          `json:"note" validate:"max=500"`
+}
+
+// ErrBatchFailed is returned when at least one operation of a batch is invalid
+// or fails to apply. No operation of the batch is persisted in that case.
+type ErrBatchFailed struct {
+	Errors []BatchItemError
+	// Applying is set when the batch was valid but an operation failed to apply.
+	// Applying stops at the first failure, so Errors holds only that operation and the
+	// ones after it were not tried.
+	Applying bool
+}
+
+func (e *ErrBatchFailed) Error() string {
+	if e.Applying && len(e.Errors) == 1 {
+		return fmt.Sprintf("batch rolled back: operation %d failed to apply, the operations after it were not tried", e.Errors[0].Index)
+	}
+	return fmt.Sprintf("batch rejected: %d operation(s) failed", len(e.Errors))
+}
+
+func (e *ErrBatchFailed) Code() string {
+	return CodeBatchFailed
+}
+
+// batchApplyFunc applies a single operation using queries bound to the batch transaction.
+type batchApplyFunc func(queries *repository.Queries, op BatchOperation) (uuid.UUID, any, error)
+
+// validateBatch checks every operation up front and reports all failures at once.
+// validateCategory is called at most once per distinct category, dates are checked
+// against now.
+func validateBatch(operations []BatchOperation, now time.Time, validateCategory func(categoryID uuid.UUID) error) error {
+	if len(operations) == 0 {
+		return NewValidationError("operations", FieldRequired, "batch must contain at least one operation")
+	}
+	if len(operations) > MaxBatchSize {
+		return NewValidationError("operations", FieldTooLong, fmt.Sprintf("batch can't contain more than %d operations", MaxBatchSize))
+	}
+
+	checkedCategories := map[uuid.UUID]error{}
+	var itemErrors []BatchItemError
+
+	for i, op := range operations {
+		var err error
+		switch op.Op {
+		case BatchOpCreate, BatchOpUpdate:
+			if op.Op == BatchOpUpdate && op.ID == uuid.Nil {
+				err = NewValidationError("id", FieldRequired, fmt.Sprintf("id is required for %s", op.Op))
+				break
+			}
+			err = ValidateAt(batchPayload{
+				Name:     op.Name,
+				Amount:   op.Amount,
+				Category: op.Category,
+				Date:     op.Date,
+				Note:     op.Note,
+			}, now)
+			if err != nil {
+				break
+			}
+			categoryErr, ok := checkedCategories[op.Category]
+			if !ok {
+				categoryErr = validateCategory(op.Category)
+				checkedCategories[op.Category] = categoryErr
+			}
+			err = categoryErr
+		case BatchOpDelete:
+			if op.ID == uuid.Nil {
+				err = NewValidationError("id", FieldRequired, fmt.Sprintf("id is required for %s", op.Op))
+			}
+		default:
+			err = NewValidationError("op", FieldInvalid, fmt.Sprintf("invalid operation: %q (must be %s, %s or %s)", op.Op, BatchOpCreate, BatchOpUpdate, BatchOpDelete))
+		}
+
+		if err != nil {
+			itemError := BatchItemError{Index: i, Error: err.Error()}
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				itemError.Fields = validationErr.Fields
+			}
+			itemErrors = append(itemErrors, itemError)
+		}
+	}
+
+	if len(itemErrors) > 0 {
+		return &ErrBatchFailed{Errors: itemErrors}
+	}
+	return nil
+}
+
+// runBatch applies all operations inside one database transaction.
+// The first failing operation rolls back the whole batch and is the only one reported.
+func runBatch(ctx context.Context, db *pgxpool.Pool, baseQueries *repository.Queries, operations []BatchOperation, apply batchApplyFunc) ([]BatchResult, error) {
+	results := make([]BatchResult, 0, len(operations))
+
+	err := database.WithTx(ctx, db, func(tx pgx.Tx) error {
+		queries := baseQueries.WithTx(tx)
+		for i, op := range operations {
+			id, data, err := apply(queries, op)
+			if err != nil {
+				logger.Error(ctx, "batch operation failed, rolling back", map[string]interface{}{
+					"index": i,
+					"op":    op.Op,
+					"error": err,
+				})
+				// Only errors the client can act on are reported per operation.
+				var clientErr interface{ Code() string }
+				if !errors.As(err, &clientErr) {
+					return err
+				}
+				return &ErrBatchFailed{Errors: []BatchItemError{{Index: i, Error: err.Error()}}, Applying: true}
+			}
+			results = append(results, BatchResult{Index: i, Op: op.Op, ID: id, Data: data})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
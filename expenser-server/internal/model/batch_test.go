@@ -0,0 +1,59 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestValidateBatchListsEveryInvalidOperation(t *testing.T) {
+	now := mustDate(t, "2025-03-15")
+	valid := BatchOperation{Op: BatchOpCreate, Name: "Coffee", Amount: decimal.NewFromInt(10), Category: groceries, Date: "2025-03-14"}
+	missingName := valid
+	missingName.Name = ""
+	operations := []BatchOperation{
+		valid,
+		missingName,
+		{Op: BatchOpDelete},
+		{Op: "upsert"},
+		{Op: BatchOpDelete, ID: uuid.New()},
+	}
+
+	err := validateBatch(operations, now, func(uuid.UUID) error { return nil })
+
+	var batchErr *ErrBatchFailed
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("validateBatch = %v, want a batch error", err)
+	}
+	var indexes []int
+	for _, itemErr := range batchErr.Errors {
+		indexes = append(indexes, itemErr.Index)
+	}
+	if len(indexes) != 3 || indexes[0] != 1 || indexes[1] != 2 || indexes[2] != 3 {
+		t.Errorf("invalid operations = %v, want [1 2 3]", indexes)
+	}
+	if batchErr.Applying {
+		t.Error("validation failure reported as an apply failure")
+	}
+}
+
+func TestErrBatchFailedMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  ErrBatchFailed
+		want string
+	}{
+		{"validation", ErrBatchFailed{Errors: []BatchItemError{{Index: 1}, {Index: 3}}}, "2 operation(s) failed"},
+		{"apply", ErrBatchFailed{Errors: []BatchItemError{{Index: 4}}, Applying: true}, "operation 4 failed to apply, the operations after it were not tried"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); !strings.Contains(got, tt.want) {
+				t.Errorf("Error() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
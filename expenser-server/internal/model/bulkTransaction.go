@@ -1,10 +1,15 @@
 package model
 
-import "github.com/shopspring/decimal"
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
 
 type BulkTransaction struct {
 	Name    string          `json:"name"`
 	Date    string          `json:"date"`
 	Expense bool            `json:"expense"`
 	Amount  decimal.Decimal `json:"amount"`
+	// Category is the default category of the user for the kind of row, nil when unset.
+	Category *uuid.UUID `json:"category"`
 }
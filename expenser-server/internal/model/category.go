@@ -4,34 +4,46 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"maps"
-	"slices"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
 	"github.com/keertirajmalik/expenser/expenser-server/logger"
 )
 
 type Category struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Type        string    `json:"type"`
-	Description string    `json:"description"`
-	UserID      uuid.UUID `json:"user_id"`
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name" validate:"required,max=50"`
+	Type        string     `json:"type" validate:"required,oneof=Expense|Income|Investment"`
+	Description string     `json:"description" validate:"max=500"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	// TaxSection tags Investment categories with the section their entries are
+	// deductible under, like 80C. TaxExempt marks Income categories as tax free.
+	TaxSection string    `json:"tax_section" validate:"max=20"`
+	TaxExempt  bool      `json:"tax_exempt"`
+	UserID     uuid.UUID `json:"user_id"`
 }
 
 type ResponseCategory struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Type        string    `json:"type"`
-	Description string    `json:"description"`
-	User        string    `json:"user"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	Description string     `json:"description"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	TaxSection  *string    `json:"tax_section"`
+	TaxExempt   bool       `json:"tax_exempt"`
+	User        string     `json:"user"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CategoryNode is a category together with its sub categories.
+type CategoryNode struct {
+	ResponseCategory
+	Children []CategoryNode `json:"children"`
 }
 
 const (
@@ -48,33 +60,20 @@ var ValidCategoryTypes = map[string]bool{
 
 type CategoryService struct {
 	Queries *repository.Queries
+	DB      *pgxpool.Pool
 }
 
 func (c Category) Validate() error {
-	if len(strings.TrimSpace(c.Name)) == 0 {
-		return fmt.Errorf("category name cannot be empty")
-	}
-
-	if len(c.Name) > 50 {
-		return fmt.Errorf("category name too long")
-	}
-
-	if len(strings.TrimSpace(c.Type)) == 0 {
-		return fmt.Errorf("category type cannot be empty")
-	}
-
-	if !ValidCategoryTypes[c.Type] {
-		validTypes := strings.Join(slices.Collect(maps.Keys(ValidCategoryTypes)), " or ")
-		return fmt.Errorf("invalid category type: %q (must be %s)", c.Type, validTypes)
+	if err := Validate(c); err != nil {
+		return err
 	}
-
-	return nil
+	return validateCategoryTax(c)
 }
 
 func (c CategoryService) GetCategoriesFromDB(ctx context.Context, userId uuid.UUID) ([]ResponseCategory, error) {
 	dbCategories, err := c.Queries.GetCategory(ctx, userId)
 	if err != nil {
-		logger.Error("Couldn't get category from DB", map[string]interface{}{
+		logger.Error(ctx, "Couldn't get category from DB", map[string]interface{}{
 			"user_id": userId,
 			"error":   err,
 		})
@@ -93,6 +92,9 @@ func (c CategoryService) GetCategoriesFromDB(ctx context.Context, userId uuid.UU
 			Name:        category.Name,
 			Type:        category.Type,
 			Description: descriptionValue,
+			ParentID:    category.ParentID,
+			TaxSection:  category.TaxSection,
+			TaxExempt:   category.TaxExempt,
 			User:        category.User,
 			CreatedAt:   category.CreatedAt.Time,
 		})
@@ -104,11 +106,14 @@ func (c CategoryService) GetCategoriesFromDB(ctx context.Context, userId uuid.UU
 func (c CategoryService) GetCategoryByIdFromDB(ctx context.Context, id, userId uuid.UUID) (ResponseCategory, error) {
 	dbCategory, err := c.Queries.GetCategoryById(ctx, repository.GetCategoryByIdParams{ID: id, UserID: userId})
 	if err != nil {
-		logger.Error("Couldn't get category from DB", map[string]interface{}{
+		logger.Error(ctx, "Couldn't get category from DB", map[string]interface{}{
 			"category_id": id,
 			"user_id":     userId,
 			"error":       err,
 		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ResponseCategory{}, NewNotFoundError("category")
+		}
 		return ResponseCategory{}, err
 	}
 
@@ -121,6 +126,9 @@ func (c CategoryService) GetCategoryByIdFromDB(ctx context.Context, id, userId u
 		Name:        dbCategory.Name,
 		Type:        dbCategory.Type,
 		Description: descriptionValue,
+		ParentID:    dbCategory.ParentID,
+		TaxSection:  dbCategory.TaxSection,
+		TaxExempt:   dbCategory.TaxExempt,
 		User:        dbCategory.User,
 		CreatedAt:   dbCategory.CreatedAt.Time,
 	}
@@ -129,29 +137,36 @@ func (c CategoryService) GetCategoryByIdFromDB(ctx context.Context, id, userId u
 
 func (c CategoryService) AddCategoryToDB(ctx context.Context, category Category) (ResponseCategory, error) {
 	if err := category.Validate(); err != nil {
-		logger.Error("Provided category is not valid", map[string]interface{}{
+		logger.Error(ctx, "Provided category is not valid", map[string]interface{}{
 			"category": category,
 		})
 		return ResponseCategory{}, err
 	}
 
+	if err := c.validateCategoryParent(ctx, category); err != nil {
+		return ResponseCategory{}, err
+	}
+
 	dbCategory, err := c.Queries.CreateCategory(ctx, repository.CreateCategoryParams{
 		ID:          uuid.New(),
 		Name:        category.Name,
 		Type:        category.Type,
 		Description: &category.Description,
 		UserID:      category.UserID,
+		ParentID:    category.ParentID,
+		TaxSection:  taxSectionParam(category.TaxSection),
+		TaxExempt:   category.TaxExempt,
 	})
 
 	if err != nil {
-		logger.Error("Failed to create category", map[string]interface{}{
+		logger.Error(ctx, "Failed to create category", map[string]interface{}{
 			"category_id": category.ID,
 			"user_id":     category.UserID,
 			"error":       err,
 		})
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeUniqueViolation {
-			return ResponseCategory{}, &database.ErrDuplicateData{Column: category.Name}
+			return ResponseCategory{}, NewConflictError(CodeDuplicate, fmt.Sprintf("%s already exist", category.Name))
 		}
 		return ResponseCategory{}, err
 	}
@@ -166,6 +181,9 @@ func (c CategoryService) AddCategoryToDB(ctx context.Context, category Category)
 		Name:        dbCategory.Name,
 		Type:        dbCategory.Type,
 		Description: descriptionValue,
+		ParentID:    dbCategory.ParentID,
+		TaxSection:  dbCategory.TaxSection,
+		TaxExempt:   dbCategory.TaxExempt,
 		User:        dbCategory.User,
 		CreatedAt:   dbCategory.CreatedAt.Time,
 	}
@@ -178,24 +196,27 @@ func (c CategoryService) DeleteCategoryFromDB(ctx context.Context, id, userID uu
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeForeignKeyViolation {
 			data, _ := c.GetCategoryByIdFromDB(ctx, id, userID)
-			logger.Error("Couldn't delete category", map[string]interface{}{
+			logger.Error(ctx, "Couldn't delete category", map[string]interface{}{
 				"category_id": id,
 				"user_id":     userID,
 				"error":       err,
 			})
-			return &database.ErrForeignKeyViolation{Message: fmt.Sprintf("%s category has been used in transaction", data.Name)}
+			if pgErr.ConstraintName == "categories_parent_id_fkey" {
+				return NewConflictError(CodeInUse, fmt.Sprintf("%s category has sub categories, move or delete them first", data.Name))
+			}
+			return NewConflictError(CodeInUse, fmt.Sprintf("%s category has been used in transaction, merge it into another category or delete it with reassign_to", data.Name))
 		}
 
 		if errors.Is(err, pgx.ErrNoRows) {
-			logger.Error("Failed to delete category", map[string]interface{}{
+			logger.Error(ctx, "Failed to delete category", map[string]interface{}{
 				"category_id": id,
 				"user_id":     userID,
 				"error":       err,
 				"error_type":  "not_found_violation",
 			})
-			return errors.New("Category not found")
+			return NewNotFoundError("category")
 		}
-		logger.Error("Couldn't delete category", map[string]interface{}{
+		logger.Error(ctx, "Couldn't delete category", map[string]interface{}{
 			"category_id": id,
 			"user_id":     userID,
 			"error":       err,
@@ -205,63 +226,169 @@ func (c CategoryService) DeleteCategoryFromDB(ctx context.Context, id, userID uu
 
 	rowAffected := result.RowsAffected()
 	if rowAffected == 0 {
-		logger.Error("Failed to delete category", map[string]interface{}{
+		logger.Error(ctx, "Failed to delete category", map[string]interface{}{
 			"category_id": id,
 			"user_id":     userID,
 			"error":       "no category found",
 		})
-		return fmt.Errorf("category %s not found for user %s", id, userID)
+		return NewNotFoundError("category")
 	}
 
 	return nil
 }
 
+// MergeCategoryInDB moves every transaction, income, investment and sub category of
+// the source category into the target category and deletes the source, in a single DB transaction.
+func (c CategoryService) MergeCategoryInDB(ctx context.Context, sourceID, targetID, userID uuid.UUID) (ResponseCategory, error) {
+	if sourceID == targetID {
+		return ResponseCategory{}, NewValidationError("target", FieldInvalid, "category can't be merged into itself")
+	}
+
+	source, err := c.GetCategoryByIdFromDB(ctx, sourceID, userID)
+	if err != nil {
+		return ResponseCategory{}, err
+	}
+	target, err := c.GetCategoryByIdFromDB(ctx, targetID, userID)
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		return ResponseCategory{}, NewValidationError("target", FieldNotFound, "target category not found")
+	}
+	if err != nil {
+		return ResponseCategory{}, err
+	}
+
+	if source.Type != target.Type {
+		logger.Error(ctx, "Category types don't match for merge", map[string]interface{}{
+			"source_id":   sourceID,
+			"source_type": source.Type,
+			"target_id":   targetID,
+			"target_type": target.Type,
+		})
+		return ResponseCategory{}, NewValidationError("target", FieldInvalid, fmt.Sprintf("can't merge %s category %q into %s category %q", source.Type, source.Name, target.Type, target.Name))
+	}
+
+	categories, err := c.GetCategoriesFromDB(ctx, userID)
+	if err != nil {
+		return ResponseCategory{}, err
+	}
+	if isCategoryAncestor(categories, sourceID, targetID) {
+		return ResponseCategory{}, NewValidationError("target", FieldInvalid, fmt.Sprintf("can't merge category %q into its own sub category %q", source.Name, target.Name))
+	}
+
+	err = database.WithTx(ctx, c.DB, func(tx pgx.Tx) error {
+		queries := c.Queries.WithTx(tx)
+
+		if _, err := queries.ReassignCategoryParent(ctx, repository.ReassignCategoryParentParams{
+			TargetParent: &targetID,
+			SourceParent: &sourceID,
+			UserID:       userID,
+		}); err != nil {
+			return err
+		}
+
+		if _, err := queries.ReassignTransactionCategory(ctx, repository.ReassignTransactionCategoryParams{
+			TargetCategory: targetID,
+			SourceCategory: sourceID,
+			UserID:         userID,
+		}); err != nil {
+			return err
+		}
+		if _, err := queries.ReassignIncomeCategory(ctx, repository.ReassignIncomeCategoryParams{
+			TargetCategory: targetID,
+			SourceCategory: sourceID,
+			UserID:         userID,
+		}); err != nil {
+			return err
+		}
+		if _, err := queries.ReassignInvestmentCategory(ctx, repository.ReassignInvestmentCategoryParams{
+			TargetCategory: targetID,
+			SourceCategory: sourceID,
+			UserID:         userID,
+		}); err != nil {
+			return err
+		}
+		if err := queries.ReassignGoalCategory(ctx, repository.ReassignGoalCategoryParams{
+			TargetCategory: targetID,
+			SourceCategory: sourceID,
+		}); err != nil {
+			return err
+		}
+
+		result, err := queries.DeleteCategory(ctx, repository.DeleteCategoryParams{ID: sourceID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return NewNotFoundError("category")
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, "Failed to merge category", map[string]interface{}{
+			"source_id": sourceID,
+			"target_id": targetID,
+			"user_id":   userID,
+			"error":     err,
+		})
+		return ResponseCategory{}, err
+	}
+
+	return target, nil
+}
+
 func (c CategoryService) UpdateCategoryInDB(ctx context.Context, category Category) (ResponseCategory, error) {
 	if err := category.Validate(); err != nil {
-		logger.Error("Provided category is not valid", map[string]interface{}{
+		logger.Error(ctx, "Provided category is not valid", map[string]interface{}{
 			"category": category,
 		})
 		return ResponseCategory{}, err
 	}
 
+	if err := c.validateCategoryParent(ctx, category); err != nil {
+		return ResponseCategory{}, err
+	}
+
 	dbCategory, err := c.Queries.UpdateCategory(ctx, repository.UpdateCategoryParams{
 		ID:          category.ID,
 		Name:        category.Name,
 		Type:        category.Type,
 		Description: &category.Description,
 		UserID:      category.UserID,
+		ParentID:    category.ParentID,
+		TaxSection:  taxSectionParam(category.TaxSection),
+		TaxExempt:   category.TaxExempt,
 	})
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			logger.Error("Category not found during update", map[string]interface{}{
+			logger.Error(ctx, "Category not found during update", map[string]interface{}{
 				"category_id": category.ID,
 				"user_id":     category.UserID,
 			})
-			return ResponseCategory{}, errors.New("Category not found")
+			return ResponseCategory{}, NewNotFoundError("category")
 		}
 
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeForeignKeyViolation {
 			data, _ := c.GetCategoryByIdFromDB(ctx, category.ID, category.UserID)
-			logger.Error("Couldn't update category", map[string]interface{}{
+			logger.Error(ctx, "Couldn't update category", map[string]interface{}{
 				"category_id": category.ID,
 				"user_id":     category.UserID,
 				"error":       err,
 			})
-			return ResponseCategory{}, &database.ErrForeignKeyViolation{Message: fmt.Sprintf("%s category has been used in transaction", data.Name)}
+			return ResponseCategory{}, NewConflictError(CodeInUse, fmt.Sprintf("%s category has been used in transaction", data.Name))
 		}
 
 		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeUniqueViolation {
-			logger.Error("Failed to update category", map[string]interface{}{
+			logger.Error(ctx, "Failed to update category", map[string]interface{}{
 				"category_id": category.ID,
 				"user_id":     category.UserID,
 				"error":       err,
 			})
-			return ResponseCategory{}, &database.ErrDuplicateData{Column: category.Name}
+			return ResponseCategory{}, NewConflictError(CodeDuplicate, fmt.Sprintf("%s already exist", category.Name))
 		}
 
-		logger.Error("Failed to update category", map[string]interface{}{
+		logger.Error(ctx, "Failed to update category", map[string]interface{}{
 			"category_id": category.ID,
 			"user_id":     category.UserID,
 			"error":       err,
@@ -279,8 +406,114 @@ func (c CategoryService) UpdateCategoryInDB(ctx context.Context, category Catego
 		Name:        dbCategory.Name,
 		Type:        dbCategory.Type,
 		Description: descriptionValue,
+		ParentID:    dbCategory.ParentID,
+		TaxSection:  dbCategory.TaxSection,
+		TaxExempt:   dbCategory.TaxExempt,
 		User:        dbCategory.User,
 		CreatedAt:   dbCategory.CreatedAt.Time,
 	}
 	return categoryResponse, nil
 }
+
+// GetCategoryTreeFromDB returns the categories of a user as a forest of root
+// categories with their sub categories nested under them.
+func (c CategoryService) GetCategoryTreeFromDB(ctx context.Context, userID uuid.UUID) ([]CategoryNode, error) {
+	categories, err := c.GetCategoriesFromDB(ctx, userID)
+	if err != nil {
+		return []CategoryNode{}, err
+	}
+
+	children := map[uuid.UUID][]ResponseCategory{}
+	roots := []ResponseCategory{}
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	var build func(category ResponseCategory) CategoryNode
+	build = func(category ResponseCategory) CategoryNode {
+		node := CategoryNode{ResponseCategory: category, Children: []CategoryNode{}}
+		for _, child := range children[category.ID] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+
+	tree := []CategoryNode{}
+	for _, root := range roots {
+		tree = append(tree, build(root))
+	}
+	return tree, nil
+}
+
+// validateCategoryParent checks that the parent exists, has the same type as the
+// category and that setting it doesn't introduce a cycle.
+func (c CategoryService) validateCategoryParent(ctx context.Context, category Category) error {
+	categories, err := c.GetCategoriesFromDB(ctx, category.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range categories {
+		if existing.ParentID != nil && *existing.ParentID == category.ID && existing.Type != category.Type {
+			return NewValidationError("type", FieldInvalid, fmt.Sprintf("category type can't change while sub category %q is of type %s", existing.Name, existing.Type))
+		}
+	}
+
+	if category.ParentID == nil {
+		return nil
+	}
+
+	if *category.ParentID == category.ID {
+		return NewValidationError("parent_id", FieldInvalid, "category can't be its own parent")
+	}
+
+	var parent *ResponseCategory
+	for i := range categories {
+		if categories[i].ID == *category.ParentID {
+			parent = &categories[i]
+			break
+		}
+	}
+	if parent == nil {
+		logger.Error(ctx, "Parent category not found", map[string]interface{}{
+			"category_id": category.ID,
+			"parent_id":   category.ParentID,
+			"user_id":     category.UserID,
+		})
+		return NewValidationError("parent_id", FieldNotFound, "parent category not found")
+	}
+
+	if parent.Type != category.Type {
+		return NewValidationError("parent_id", FieldInvalid, fmt.Sprintf("parent category %q is of type %s, expected %s", parent.Name, parent.Type, category.Type))
+	}
+
+	if isCategoryAncestor(categories, category.ID, parent.ID) {
+		return NewValidationError("parent_id", FieldInvalid, fmt.Sprintf("category %q can't be moved under its own sub category %q", category.Name, parent.Name))
+	}
+
+	return nil
+}
+
+// isCategoryAncestor reports whether ancestorID appears on the parent chain of categoryID.
+func isCategoryAncestor(categories []ResponseCategory, ancestorID, categoryID uuid.UUID) bool {
+	parents := make(map[uuid.UUID]*uuid.UUID, len(categories))
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+	}
+
+	visited := map[uuid.UUID]bool{}
+	for current := parents[categoryID]; current != nil; current = parents[*current] {
+		if *current == ancestorID {
+			return true
+		}
+		if visited[*current] {
+			return false
+		}
+		visited[*current] = true
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package model
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+)
+
+type CategoryTemplate struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	// Parent is the name of the parent template, empty for root categories.
+	Parent string `json:"parent,omitempty"`
+}
+
+type CategoryTemplatePreview struct {
+	CategoryTemplate
+	Exists bool `json:"exists"`
+}
+
+type AppliedCategoryTemplates struct {
+	Created []ResponseCategory `json:"created"`
+	Skipped []string           `json:"skipped"`
+}
+
+// DefaultCategoryTemplates are seeded for every new user. Parents are listed
+// before their sub categories.
+var DefaultCategoryTemplates = []CategoryTemplate{
+	{Name: "Food", Type: CategoryTypeExpense, Description: "Meals and groceries"},
+	{Name: "Groceries", Type: CategoryTypeExpense, Description: "Supermarket and daily essentials", Parent: "Food"},
+	{Name: "Restaurants", Type: CategoryTypeExpense, Description: "Eating out and food delivery", Parent: "Food"},
+	{Name: "Housing", Type: CategoryTypeExpense, Description: "Rent, maintenance and repairs"},
+	{Name: "Utilities", Type: CategoryTypeExpense, Description: "Electricity, water, gas and internet"},
+	{Name: "Transportation", Type: CategoryTypeExpense, Description: "Fuel, public transport and cabs"},
+	{Name: "Shopping", Type: CategoryTypeExpense, Description: "Clothes, electronics and other purchases"},
+	{Name: "Health", Type: CategoryTypeExpense, Description: "Medicines, doctor visits and insurance premiums"},
+	{Name: "Entertainment", Type: CategoryTypeExpense, Description: "Movies, subscriptions and hobbies"},
+	{Name: "Travel", Type: CategoryTypeExpense, Description: "Trips and holidays"},
+	{Name: "Salary", Type: CategoryTypeIncome, Description: "Monthly salary"},
+	{Name: "Bonus", Type: CategoryTypeIncome, Description: "Bonuses and incentives"},
+	{Name: "Interest", Type: CategoryTypeIncome, Description: "Interest from savings and deposits"},
+	{Name: "Other Income", Type: CategoryTypeIncome, Description: "Gifts, refunds and other income"},
+	{Name: "Mutual Funds", Type: CategoryTypeInvestment, Description: "SIPs and lump sum mutual fund investments"},
+	{Name: "Stocks", Type: CategoryTypeInvestment, Description: "Direct equity investments"},
+	{Name: "Fixed Deposits", Type: CategoryTypeInvestment, Description: "Bank fixed and recurring deposits"},
+	{Name: "Retirement", Type: CategoryTypeInvestment, Description: "PPF, EPF and NPS contributions"},
+}
+
+func (c CategoryService) GetCategoryTemplatesFromDB(ctx context.Context, userID uuid.UUID) ([]CategoryTemplatePreview, error) {
+	categories, err := c.GetCategoriesFromDB(ctx, userID)
+	if err != nil {
+		return []CategoryTemplatePreview{}, err
+	}
+
+	existing := map[string]bool{}
+	for _, category := range categories {
+		existing[category.Name] = true
+	}
+
+	previews := []CategoryTemplatePreview{}
+	for _, template := range DefaultCategoryTemplates {
+		previews = append(previews, CategoryTemplatePreview{
+			CategoryTemplate: template,
+			Exists:           existing[template.Name],
+		})
+	}
+	return previews, nil
+}
+
+// ApplyCategoryTemplatesToDB creates the named templates for the user, or all of them
+// when names is empty. Templates whose name already exists are skipped and parents of
+// selected templates are created along with them.
+func (c CategoryService) ApplyCategoryTemplatesToDB(ctx context.Context, userID uuid.UUID, names []string) (AppliedCategoryTemplates, error) {
+	templates, err := selectCategoryTemplates(names)
+	if err != nil {
+		return AppliedCategoryTemplates{}, err
+	}
+
+	var applied AppliedCategoryTemplates
+	err = database.WithTx(ctx, c.DB, func(tx pgx.Tx) error {
+		var err error
+		applied, err = applyCategoryTemplates(ctx, c.Queries.WithTx(tx), userID, templates)
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, "Failed to apply category templates", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return AppliedCategoryTemplates{}, err
+	}
+
+	return applied, nil
+}
+
+func selectCategoryTemplates(names []string) ([]CategoryTemplate, error) {
+	if len(names) == 0 {
+		return DefaultCategoryTemplates, nil
+	}
+
+	byName := map[string]CategoryTemplate{}
+	for _, template := range DefaultCategoryTemplates {
+		byName[template.Name] = template
+	}
+
+	selected := map[string]bool{}
+	for _, name := range names {
+		template, ok := byName[name]
+		if !ok {
+			return nil, NewValidationError("names", FieldInvalid, fmt.Sprintf("unknown category template: %q", name))
+		}
+		selected[name] = true
+		if template.Parent != "" {
+			selected[template.Parent] = true
+		}
+	}
+
+	templates := []CategoryTemplate{}
+	for _, template := range DefaultCategoryTemplates {
+		if selected[template.Name] {
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+func applyCategoryTemplates(ctx context.Context, queries *repository.Queries, userID uuid.UUID, templates []CategoryTemplate) (AppliedCategoryTemplates, error) {
+	dbCategories, err := queries.GetCategory(ctx, userID)
+	if err != nil {
+		return AppliedCategoryTemplates{}, err
+	}
+
+	type existingCategory struct {
+		id           uuid.UUID
+		categoryType string
+	}
+	existing := map[string]existingCategory{}
+	for _, category := range dbCategories {
+		existing[category.Name] = existingCategory{id: category.ID, categoryType: category.Type}
+	}
+
+	applied := AppliedCategoryTemplates{Created: []ResponseCategory{}, Skipped: []string{}}
+	for _, template := range templates {
+		if _, ok := existing[template.Name]; ok {
+			applied.Skipped = append(applied.Skipped, template.Name)
+			continue
+		}
+
+		var parentID *uuid.UUID
+		if parent, ok := existing[template.Parent]; ok && template.Parent != "" && parent.categoryType == template.Type {
+			parentID = &parent.id
+		}
+
+		description := template.Description
+		dbCategory, err := queries.CreateCategory(ctx, repository.CreateCategoryParams{
+			ID:          uuid.New(),
+			Name:        template.Name,
+			Type:        template.Type,
+			Description: &description,
+			UserID:      userID,
+			ParentID:    parentID,
+		})
+		if err != nil {
+			return AppliedCategoryTemplates{}, err
+		}
+		existing[dbCategory.Name] = existingCategory{id: dbCategory.ID, categoryType: dbCategory.Type}
+
+		applied.Created = append(applied.Created, ResponseCategory{
+			ID:          dbCategory.ID,
+			Name:        dbCategory.Name,
+			Type:        dbCategory.Type,
+			Description: description,
+			ParentID:    dbCategory.ParentID,
+			TaxSection:  dbCategory.TaxSection,
+			TaxExempt:   dbCategory.TaxExempt,
+			User:        dbCategory.User,
+			CreatedAt:   dbCategory.CreatedAt.Time,
+		})
+	}
+
+	return applied, nil
+}
@@ -1,10 +1,23 @@
 package model
 
+import (
+	"time"
+
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+)
+
 type Config struct {
-	JWTSecret          string
+	JWTKeys            *auth.Keys
+	TokenTTL           time.Duration
 	UserService        UserService
 	CategoryService    CategoryService
 	TransactionService TransactionService
 	InvestmentService  InvestmentService
 	IncomeService      IncomeService
+	ReportService      ReportService
+	GoalService        GoalService
+	APITokenService    APITokenService
+	TwoFactorService   TwoFactorService
+	OIDCService        OIDCService
+	SearchService      SearchService
 }
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stable error codes returned to clients. The UI switches on these, so existing
+// values must never change meaning.
+const (
+	CodeValidationFailed = "validation_failed"
+	CodeConflict         = "conflict"
+	CodeDuplicate        = "duplicate"
+	CodeInUse            = "in_use"
+	CodeForbidden        = "forbidden"
+	CodeUnauthorized     = "unauthorized"
+	CodeBatchFailed      = "batch_failed"
+)
+
+// Field error codes describing why a single field is invalid.
+const (
+	FieldRequired = "required"
+	FieldInvalid  = "invalid"
+	FieldTooLong  = "too_long"
+	FieldNotFound = "not_found"
+	FieldUnknown  = "unknown"
+)
+
+// NotFoundError is returned when a resource doesn't exist or isn't owned by the user.
+type NotFoundError struct {
+	Resource string
+}
+
+func NewNotFoundError(resource string) *NotFoundError {
+	return &NotFoundError{Resource: resource}
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found", e.Resource)
+}
+
+// Code is <resource>_not_found, e.g. transaction_not_found.
+func (e *NotFoundError) Code() string {
+	return strings.ReplaceAll(e.Resource, " ", "_") + "_not_found"
+}
+
+// ConflictError is returned when the request clashes with the current state, like a
+// duplicate name or a category that is still in use.
+type ConflictError struct {
+	ErrorCode string
+	Message   string
+}
+
+func NewConflictError(code, message string) *ConflictError {
+	return &ConflictError{ErrorCode: code, Message: message}
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func (e *ConflictError) Code() string {
+	return e.ErrorCode
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field of a request.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// NewValidationError returns a ValidationError for a single invalid field.
+func NewValidationError(field, code, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Code: code, Message: message}}}
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Code() string {
+	return CodeValidationFailed
+}
+
+// ForbiddenError is returned when the user is known but not allowed to do something.
+type ForbiddenError struct {
+	Message string
+}
+
+func NewForbiddenError(message string) *ForbiddenError {
+	return &ForbiddenError{Message: message}
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+func (e *ForbiddenError) Code() string {
+	return CodeForbidden
+}
+
+// UnauthorizedError is returned when the credentials of the request are missing or wrong.
+type UnauthorizedError struct {
+	Message string
+}
+
+func NewUnauthorizedError(message string) *UnauthorizedError {
+	return &UnauthorizedError{Message: message}
+}
+
+func (e *UnauthorizedError) Error() string {
+	return e.Message
+}
+
+func (e *UnauthorizedError) Code() string {
+	return CodeUnauthorized
+}
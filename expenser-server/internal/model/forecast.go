@@ -0,0 +1,444 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DefaultForecastMonths = 6
+	MaxForecastMonths     = 24
+
+	ForecastKindIncome  = "income"
+	ForecastKindExpense = "expense"
+
+	forecastHistoryMonths   = 12
+	minRecurringOccurrences = 3
+	// forecastZ is the z value of a two-sided 80% confidence range.
+	forecastZ = 1.2816
+	// Patterns with a mean interval in this range are monthly and are projected on the
+	// same day of every month rather than every interval days. Entries exactly four
+	// weeks apart every time are four-weekly instead, as only February is 28 days.
+	minMonthlyInterval = 28
+	maxMonthlyInterval = 31
+	// similarAmountRatio is the relative difference under which two amounts are
+	// grouped together when looking for recurring entries without a common name.
+	similarAmountRatio = 1.05
+)
+
+type ForecastRange struct {
+	Expected decimal.Decimal `json:"expected"`
+	Low      decimal.Decimal `json:"low"`
+	High     decimal.Decimal `json:"high"`
+}
+
+type ForecastMonth struct {
+	Month         string          `json:"month"`
+	Income        ForecastRange   `json:"income"`
+	Expense       ForecastRange   `json:"expense"`
+	Net           ForecastRange   `json:"net"`
+	CumulativeNet decimal.Decimal `json:"cumulative_net"`
+}
+
+type RecurringPattern struct {
+	Kind         string          `json:"kind"`
+	Name         string          `json:"name"`
+	Category     string          `json:"category"`
+	Amount       decimal.Decimal `json:"amount"`
+	IntervalDays float64         `json:"interval_days"`
+	Occurrences  int             `json:"occurrences"`
+	LastDate     string          `json:"last_date"`
+	NextDate     string          `json:"next_date"`
+
+	amountStdDev float64
+	interval     int
+	last         time.Time
+	// monthDay is the day of the month monthly patterns occur on, 0 for other patterns.
+	monthDay int
+}
+
+// occurrence returns the date of the nth occurrence after the last one. Monthly
+// patterns land on monthDay, or on the last day of months shorter than that.
+func (p RecurringPattern) occurrence(n int) time.Time {
+	if p.monthDay == 0 {
+		return p.last.AddDate(0, 0, n*p.interval)
+	}
+	first := time.Date(p.last.Year(), p.last.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(p.monthDay, lastDay)-1)
+}
+
+type CategoryForecast struct {
+	Kind           string          `json:"kind"`
+	CategoryID     uuid.UUID       `json:"category_id"`
+	Category       string          `json:"category"`
+	MonthlyAverage decimal.Decimal `json:"monthly_average"`
+	MonthlyStdDev  decimal.Decimal `json:"monthly_std_dev"`
+
+	mean, stdDev float64
+}
+
+type Forecast struct {
+	HistoryFrom string             `json:"history_from"`
+	HistoryTo   string             `json:"history_to"`
+	Currency    string             `json:"currency"`
+	Months      []ForecastMonth    `json:"months"`
+	Recurring   []RecurringPattern `json:"recurring"`
+	Categories  []CategoryForecast `json:"categories"`
+}
+
+// GetForecastFromDB projects income, expenses and net balance for the months after
+// the current one from the last year of incomes and transactions.
+func (s ReportService) GetForecastFromDB(ctx context.Context, userID uuid.UUID, now time.Time, months int) (Forecast, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, 1-today.Day()).AddDate(0, -forecastHistoryMonths, 0)
+
+	transactionRows, err := s.Queries.GetTransactionsInRange(ctx, repository.GetTransactionsInRangeParams{
+		UserID:   userID,
+		FromDate: pgtype.Date{Time: from, Valid: true},
+		ToDate:   pgtype.Date{Time: today, Valid: true},
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to get transactions for forecast", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return Forecast{}, err
+	}
+
+	incomeRows, err := s.Queries.GetIncomesInRange(ctx, repository.GetIncomesInRangeParams{
+		UserID:   userID,
+		FromDate: pgtype.Date{Time: from, Valid: true},
+		ToDate:   pgtype.Date{Time: today, Valid: true},
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to get incomes for forecast", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return Forecast{}, err
+	}
+
+	forecast := BuildForecast(incomeRowsToEntries(ctx, incomeRows), transactionRowsToEntries(ctx, transactionRows), today, months)
+	forecast.Currency = userPreferences(ctx, s.Queries, userID).BaseCurrency
+	return forecast, nil
+}
+
+// BuildForecast projects the given number of months following the month of today.
+// Recurring entries are projected on their own schedule, everything else uses the
+// per-category monthly average of the full months before today.
+func BuildForecast(incomes, expenses []LedgerEntry, today time.Time, months int) Forecast {
+	monthStart := today.AddDate(0, 0, 1-today.Day())
+	historyFrom := monthStart.AddDate(0, -forecastHistoryMonths, 0)
+	historyTo := monthStart.AddDate(0, 0, -1)
+
+	incomePatterns, incomeResidual := detectRecurring(ForecastKindIncome, incomes, today)
+	expensePatterns, expenseResidual := detectRecurring(ForecastKindExpense, expenses, today)
+	incomeCategories := categoryAverages(ForecastKindIncome, incomeResidual, historyFrom, historyTo)
+	expenseCategories := categoryAverages(ForecastKindExpense, expenseResidual, historyFrom, historyTo)
+
+	forecast := Forecast{
+		HistoryFrom: historyFrom.Format(DateLayout),
+		HistoryTo:   historyTo.Format(DateLayout),
+		Months:      []ForecastMonth{},
+		Recurring:   append(incomePatterns, expensePatterns...),
+		Categories:  append(incomeCategories, expenseCategories...),
+	}
+
+	cumulative := decimal.Zero
+	for i := 1; i <= months; i++ {
+		start := monthStart.AddDate(0, i, 0)
+		end := start.AddDate(0, 1, -1)
+
+		incomeMean, incomeVariance := projectMonth(incomePatterns, incomeCategories, start, end)
+		expenseMean, expenseVariance := projectMonth(expensePatterns, expenseCategories, start, end)
+		netMean := incomeMean - expenseMean
+		netVariance := incomeVariance + expenseVariance
+
+		net := newForecastRange(netMean, netVariance, false)
+		cumulative = cumulative.Add(net.Expected)
+		forecast.Months = append(forecast.Months, ForecastMonth{
+			Month:         start.Format("2006-01"),
+			Income:        newForecastRange(incomeMean, incomeVariance, true),
+			Expense:       newForecastRange(expenseMean, expenseVariance, true),
+			Net:           net,
+			CumulativeNet: cumulative,
+		})
+	}
+
+	return forecast
+}
+
+// detectRecurring finds entries repeating at a regular interval, first by name and
+// then by category and similar amount. It returns the active patterns and the
+// entries not explained by any of them.
+func detectRecurring(kind string, entries []LedgerEntry, today time.Time) ([]RecurringPattern, []LedgerEntry) {
+	patterns := []RecurringPattern{}
+	used := make([]bool, len(entries))
+
+	groupBy := func(key func(entry LedgerEntry) string) {
+		groups := map[string][]int{}
+		for i, entry := range entries {
+			if used[i] {
+				continue
+			}
+			if k := key(entry); k != "" {
+				groups[k] = append(groups[k], i)
+			}
+		}
+
+		keys := make([]string, 0, len(groups))
+		for k := range groups {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			group := make([]LedgerEntry, 0, len(groups[k]))
+			for _, i := range groups[k] {
+				group = append(group, entries[i])
+			}
+			pattern, ok := recurringPattern(kind, group, today)
+			if !ok {
+				continue
+			}
+			for _, i := range groups[k] {
+				used[i] = true
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	groupBy(func(entry LedgerEntry) string {
+		return normalizeEntryName(entry.Name)
+	})
+	groupBy(func(entry LedgerEntry) string {
+		amount := entry.Amount.InexactFloat64()
+		if amount <= 0 {
+			return ""
+		}
+		bucket := int(math.Round(math.Log(amount) / math.Log(similarAmountRatio)))
+		return fmt.Sprintf("%s/%d", entry.CategoryID, bucket)
+	})
+
+	residual := []LedgerEntry{}
+	for i, entry := range entries {
+		if !used[i] {
+			residual = append(residual, entry)
+		}
+	}
+	return patterns, residual
+}
+
+// recurringPattern reports whether the entries occur at a regular interval and are
+// still active, i.e. the last one is not older than two intervals.
+func recurringPattern(kind string, entries []LedgerEntry, today time.Time) (RecurringPattern, bool) {
+	if len(entries) < minRecurringOccurrences {
+		return RecurringPattern{}, false
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+
+	intervals := make([]float64, 0, len(entries)-1)
+	for i := 1; i < len(entries); i++ {
+		days := entries[i].Date.Sub(entries[i-1].Date).Hours() / 24
+		if days < 1 {
+			return RecurringPattern{}, false
+		}
+		intervals = append(intervals, days)
+	}
+
+	meanInterval, _ := meanAndStdDev(intervals)
+	if meanInterval < 6 {
+		return RecurringPattern{}, false
+	}
+	tolerance := math.Max(3, meanInterval*0.2)
+	for _, interval := range intervals {
+		if math.Abs(interval-meanInterval) > tolerance {
+			return RecurringPattern{}, false
+		}
+	}
+
+	last := entries[len(entries)-1]
+	interval := int(math.Round(meanInterval))
+	if today.Sub(last.Date).Hours()/24 > 2*meanInterval {
+		return RecurringPattern{}, false
+	}
+
+	amounts := make([]float64, 0, len(entries))
+	for _, entry := range entries {
+		amounts = append(amounts, entry.Amount.InexactFloat64())
+	}
+	meanAmount, stdDevAmount := meanAndStdDev(amounts)
+
+	pattern := RecurringPattern{
+		Kind:         kind,
+		Name:         last.Name,
+		Category:     last.Category,
+		Amount:       decimal.NewFromFloat(meanAmount).Round(2),
+		IntervalDays: roundTo(meanInterval, 1),
+		Occurrences:  len(entries),
+		LastDate:     last.Date.Format(DateLayout),
+		amountStdDev: stdDevAmount,
+		interval:     interval,
+		last:         last.Date,
+	}
+	if interval >= minMonthlyInterval && interval <= maxMonthlyInterval && !fourWeekly(intervals) {
+		pattern.monthDay = monthDay(entries)
+	}
+	pattern.NextDate = pattern.occurrence(1).Format(DateLayout)
+	return pattern, true
+}
+
+func fourWeekly(intervals []float64) bool {
+	for _, interval := range intervals {
+		if interval != 28 {
+			return false
+		}
+	}
+	return true
+}
+
+// monthDay returns the day of the month of the last entry. When that is the last day
+// of a short month, like the 28th of February, it's the latest day of all the entries
+// instead, so a bill due on the 31st stays on the 31st.
+func monthDay(entries []LedgerEntry) int {
+	last := entries[len(entries)-1].Date
+	if last.AddDate(0, 0, 1).Day() != 1 {
+		return last.Day()
+	}
+	day := last.Day()
+	for _, entry := range entries {
+		day = max(day, entry.Date.Day())
+	}
+	return day
+}
+
+// categoryAverages returns the monthly average and spread of each category over the
+// full months between from and to, starting at the month of the first entry.
+func categoryAverages(kind string, entries []LedgerEntry, from, to time.Time) []CategoryForecast {
+	var first time.Time
+	for _, entry := range entries {
+		if entry.Date.Before(from) || entry.Date.After(to) {
+			continue
+		}
+		if first.IsZero() || entry.Date.Before(first) {
+			first = entry.Date
+		}
+	}
+	if first.IsZero() {
+		return []CategoryForecast{}
+	}
+
+	historyMonths := monthsBetween(first, to) + 1
+	totals := map[uuid.UUID][]float64{}
+	names := map[uuid.UUID]string{}
+	for _, entry := range entries {
+		if entry.Date.Before(from) || entry.Date.After(to) {
+			continue
+		}
+		if _, ok := totals[entry.CategoryID]; !ok {
+			totals[entry.CategoryID] = make([]float64, historyMonths)
+			names[entry.CategoryID] = entry.Category
+		}
+		offset := monthsBetween(entry.Date, to)
+		if offset < historyMonths {
+			totals[entry.CategoryID][offset] += entry.Amount.InexactFloat64()
+		}
+	}
+
+	categories := []CategoryForecast{}
+	for id, monthly := range totals {
+		mean, stdDev := meanAndStdDev(monthly)
+		categories = append(categories, CategoryForecast{
+			Kind:           kind,
+			CategoryID:     id,
+			Category:       names[id],
+			MonthlyAverage: decimal.NewFromFloat(mean).Round(2),
+			MonthlyStdDev:  decimal.NewFromFloat(stdDev).Round(2),
+			mean:           mean,
+			stdDev:         stdDev,
+		})
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Category != categories[j].Category {
+			return categories[i].Category < categories[j].Category
+		}
+		return categories[i].CategoryID.String() < categories[j].CategoryID.String()
+	})
+	return categories
+}
+
+// projectMonth returns the expected total and its variance between start and end.
+func projectMonth(patterns []RecurringPattern, categories []CategoryForecast, start, end time.Time) (float64, float64) {
+	var mean, variance float64
+
+	for _, pattern := range patterns {
+		for n, next := 1, pattern.occurrence(1); !next.After(end); n, next = n+1, pattern.occurrence(n+1) {
+			if next.Before(start) {
+				continue
+			}
+			mean += pattern.Amount.InexactFloat64()
+			variance += pattern.amountStdDev * pattern.amountStdDev
+		}
+	}
+
+	for _, category := range categories {
+		mean += category.mean
+		variance += category.stdDev * category.stdDev
+	}
+
+	return mean, variance
+}
+
+func newForecastRange(mean, variance float64, nonNegative bool) ForecastRange {
+	spread := forecastZ * math.Sqrt(variance)
+	low := mean - spread
+	if nonNegative && low < 0 {
+		low = 0
+	}
+	return ForecastRange{
+		Expected: decimal.NewFromFloat(mean).Round(2),
+		Low:      decimal.NewFromFloat(low).Round(2),
+		High:     decimal.NewFromFloat(mean + spread).Round(2),
+	}
+}
+
+var entryNameNoise = regexp.MustCompile(`[^a-z]+`)
+
+var monthNames = map[string]bool{}
+
+func init() {
+	for month := time.January; month <= time.December; month++ {
+		name := strings.ToLower(month.String())
+		monthNames[name] = true
+		monthNames[name[:3]] = true
+	}
+	monthNames["sept"] = true
+}
+
+// normalizeEntryName strips digits, punctuation and case so that names like
+// "Salary Oct 2024" and "SALARY NOV 2024" only differ by their month.
+func normalizeEntryName(name string) string {
+	words := strings.Fields(entryNameNoise.ReplaceAllString(strings.ToLower(name), " "))
+	kept := words[:0]
+	for _, word := range words {
+		if !monthNames[word] {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
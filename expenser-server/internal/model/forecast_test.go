@@ -0,0 +1,96 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBuildForecastRecurring(t *testing.T) {
+	tests := []struct {
+		name         string
+		dates        []string
+		today        string
+		wantNextDate string
+		// wantMonths holds the expected number of occurrences in every forecast month.
+		wantMonths map[string]int
+	}{
+		{
+			name:         "monthly on the 1st through February",
+			dates:        []string{"2024-09-01", "2024-10-01", "2024-11-01", "2024-12-01"},
+			today:        "2024-12-15",
+			wantNextDate: "2025-01-01",
+			wantMonths:   map[string]int{"2025-01": 1, "2025-02": 1, "2025-03": 1, "2025-04": 1, "2025-05": 1, "2025-06": 1},
+		},
+		{
+			name:         "monthly on the 1st across 31 day months",
+			dates:        []string{"2025-01-01", "2025-02-01", "2025-03-01", "2025-04-01"},
+			today:        "2025-04-15",
+			wantNextDate: "2025-05-01",
+			wantMonths:   map[string]int{"2025-05": 1, "2025-06": 1, "2025-07": 1, "2025-08": 1, "2025-09": 1, "2025-10": 1},
+		},
+		{
+			name:         "monthly on the 31st",
+			dates:        []string{"2024-10-31", "2024-11-30", "2024-12-31", "2025-01-31"},
+			today:        "2025-02-10",
+			wantNextDate: "2025-02-28",
+			wantMonths:   map[string]int{"2025-03": 1, "2025-04": 1, "2025-05": 1, "2025-06": 1, "2025-07": 1, "2025-08": 1},
+		},
+		{
+			name:         "monthly on the 31st after February",
+			dates:        []string{"2024-11-30", "2024-12-31", "2025-01-31", "2025-02-28"},
+			today:        "2025-03-05",
+			wantNextDate: "2025-03-31",
+			wantMonths:   map[string]int{"2025-04": 1, "2025-05": 1, "2025-06": 1, "2025-07": 1, "2025-08": 1, "2025-09": 1},
+		},
+		{
+			name:         "monthly on the 30th through February",
+			dates:        []string{"2024-10-30", "2024-11-30", "2024-12-30"},
+			today:        "2025-01-02",
+			wantNextDate: "2025-01-30",
+			wantMonths:   map[string]int{"2025-02": 1, "2025-03": 1, "2025-04": 1, "2025-05": 1, "2025-06": 1, "2025-07": 1},
+		},
+		{
+			name:         "every four weeks",
+			dates:        []string{"2025-01-03", "2025-01-31", "2025-02-28", "2025-03-28"},
+			today:        "2025-04-01",
+			wantNextDate: "2025-04-25",
+			wantMonths:   map[string]int{"2025-05": 1, "2025-06": 1, "2025-07": 1, "2025-08": 1, "2025-09": 1, "2025-10": 1},
+		},
+		{
+			name:         "weekly",
+			dates:        []string{"2025-03-07", "2025-03-14", "2025-03-21", "2025-03-28"},
+			today:        "2025-04-01",
+			wantNextDate: "2025-04-04",
+			wantMonths:   map[string]int{"2025-05": 5, "2025-06": 4, "2025-07": 4, "2025-08": 5, "2025-09": 4, "2025-10": 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var expenses []LedgerEntry
+			for _, day := range tt.dates {
+				entry := ledgerEntry(t, rent, day, 1000)
+				entry.Name = "Rent"
+				expenses = append(expenses, entry)
+			}
+
+			forecast := BuildForecast(nil, expenses, mustDate(t, tt.today), DefaultForecastMonths)
+
+			if len(forecast.Recurring) != 1 {
+				t.Fatalf("got %d recurring patterns, want 1: %+v", len(forecast.Recurring), forecast.Recurring)
+			}
+			if got := forecast.Recurring[0].NextDate; got != tt.wantNextDate {
+				t.Errorf("next date = %s, want %s", got, tt.wantNextDate)
+			}
+			if len(forecast.Months) != len(tt.wantMonths) {
+				t.Fatalf("got %d months, want %d", len(forecast.Months), len(tt.wantMonths))
+			}
+			for _, month := range forecast.Months {
+				want := decimal.NewFromInt(int64(1000 * tt.wantMonths[month.Month]))
+				if !month.Expense.Expected.Equal(want) {
+					t.Errorf("%s expense = %s, want %s", month.Month, month.Expense.Expected, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,482 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	GoalStatusAchieved = "achieved"
+	GoalStatusOverdue  = "overdue"
+	GoalStatusOnTrack  = "on_track"
+	GoalStatusBehind   = "behind"
+)
+
+type InputGoal struct {
+	ID           uuid.UUID       `json:"id"`
+	Name         string          `json:"name" validate:"required,max=100"`
+	TargetAmount decimal.Decimal `json:"target_amount" validate:"positive"`
+	StartDate    string          `json:"start_date" validate:"date"`
+	TargetDate   string          `json:"target_date" validate:"required,date"`
+	Note         string          `json:"note" validate:"max=500"`
+	// Categories are the linked Investment and Income categories. Without links every
+	// investment counts towards the goal.
+	Categories []uuid.UUID `json:"categories"`
+	UserID     uuid.UUID   `json:"user_id"`
+}
+
+type GoalCategory struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Type string    `json:"type"`
+}
+
+type GoalProgress struct {
+	Contributed decimal.Decimal `json:"contributed"`
+	Remaining   decimal.Decimal `json:"remaining"`
+	Percent     float64         `json:"percent"`
+	// ExpectedByNow is what should have been contributed by today when saving evenly
+	// from the start date to the target date.
+	ExpectedByNow   decimal.Decimal `json:"expected_by_now"`
+	MonthsRemaining int             `json:"months_remaining"`
+	RequiredMonthly decimal.Decimal `json:"required_monthly"`
+	Status          string          `json:"status"`
+}
+
+type ResponseGoal struct {
+	ID           uuid.UUID       `json:"id"`
+	Name         string          `json:"name"`
+	TargetAmount decimal.Decimal `json:"target_amount"`
+	StartDate    string          `json:"start_date"`
+	TargetDate   string          `json:"target_date"`
+	Note         string          `json:"note"`
+	Categories   []GoalCategory  `json:"categories"`
+	Progress     GoalProgress    `json:"progress"`
+}
+
+type GoalService struct {
+	Queries *repository.Queries
+	DB      *pgxpool.Pool
+}
+
+func (g GoalService) GetGoalsFromDB(ctx context.Context, userID uuid.UUID) ([]ResponseGoal, error) {
+	dbGoals, err := g.Queries.GetGoal(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "failed to get goals", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return []ResponseGoal{}, err
+	}
+
+	return g.goalResponses(ctx, userID, dbGoals, userNow(ctx, g.Queries, userID))
+}
+
+func (g GoalService) GetGoalFromDB(ctx context.Context, id, userID uuid.UUID) (ResponseGoal, error) {
+	dbGoal, err := g.Queries.GetGoalById(ctx, repository.GetGoalByIdParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn(ctx, fmt.Sprintf("goal %s not found for user %s", id, userID))
+			return ResponseGoal{}, NewNotFoundError("goal")
+		}
+		logger.Error(ctx, "failed to get goal", map[string]interface{}{
+			"goal_id": id,
+			"user_id": userID,
+			"error":   err,
+		})
+		return ResponseGoal{}, err
+	}
+
+	goals, err := g.goalResponses(ctx, userID, []repository.Goal{dbGoal}, userNow(ctx, g.Queries, userID))
+	if err != nil {
+		return ResponseGoal{}, err
+	}
+	return goals[0], nil
+}
+
+func (g GoalService) AddGoalToDB(ctx context.Context, goal InputGoal) (ResponseGoal, error) {
+	params, err := g.validateGoal(ctx, goal, userNow(ctx, g.Queries, goal.UserID))
+	if err != nil {
+		return ResponseGoal{}, err
+	}
+
+	err = database.WithTx(ctx, g.DB, func(tx pgx.Tx) error {
+		queries := g.Queries.WithTx(tx)
+		if _, err := queries.CreateGoal(ctx, repository.CreateGoalParams(params)); err != nil {
+			return err
+		}
+		return linkGoalCategories(ctx, queries, goal.ID, goal.Categories)
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to create goal", map[string]interface{}{
+			"user_id": goal.UserID,
+			"error":   err,
+		})
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeUniqueViolation {
+			return ResponseGoal{}, NewConflictError(CodeDuplicate, fmt.Sprintf("%s already exist", goal.Name))
+		}
+		return ResponseGoal{}, fmt.Errorf("failed to create goal: %w", err)
+	}
+
+	return g.GetGoalFromDB(ctx, goal.ID, goal.UserID)
+}
+
+// UpdateGoalInDB replaces the goal, keeping its start date when none is given.
+func (g GoalService) UpdateGoalInDB(ctx context.Context, goal InputGoal) (ResponseGoal, error) {
+	dbGoal, err := g.Queries.GetGoalById(ctx, repository.GetGoalByIdParams{ID: goal.ID, UserID: goal.UserID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn(ctx, fmt.Sprintf("goal %s not found for user %s", goal.ID, goal.UserID))
+			return ResponseGoal{}, NewNotFoundError("goal")
+		}
+		logger.Error(ctx, "failed to get goal", map[string]interface{}{
+			"goal_id": goal.ID,
+			"user_id": goal.UserID,
+			"error":   err,
+		})
+		return ResponseGoal{}, err
+	}
+
+	params, err := g.validateGoal(ctx, goal, dbGoal.StartDate.Time)
+	if err != nil {
+		return ResponseGoal{}, err
+	}
+
+	err = database.WithTx(ctx, g.DB, func(tx pgx.Tx) error {
+		queries := g.Queries.WithTx(tx)
+		if _, err := queries.UpdateGoal(ctx, repository.UpdateGoalParams(params)); err != nil {
+			return err
+		}
+		if err := queries.DeleteGoalCategories(ctx, goal.ID); err != nil {
+			return err
+		}
+		return linkGoalCategories(ctx, queries, goal.ID, goal.Categories)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn(ctx, fmt.Sprintf("goal %s not found for user %s", goal.ID, goal.UserID))
+			return ResponseGoal{}, NewNotFoundError("goal")
+		}
+		logger.Error(ctx, "failed to update goal", map[string]interface{}{
+			"goal_id": goal.ID,
+			"user_id": goal.UserID,
+			"error":   err,
+		})
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeUniqueViolation {
+			return ResponseGoal{}, NewConflictError(CodeDuplicate, fmt.Sprintf("%s already exist", goal.Name))
+		}
+		return ResponseGoal{}, err
+	}
+
+	return g.GetGoalFromDB(ctx, goal.ID, goal.UserID)
+}
+
+func (g GoalService) DeleteGoalFromDB(ctx context.Context, id, userID uuid.UUID) error {
+	result, err := g.Queries.DeleteGoal(ctx, repository.DeleteGoalParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to delete goal", map[string]interface{}{
+			"goal_id": id,
+			"user_id": userID,
+			"error":   err,
+		})
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		logger.Warn(ctx, fmt.Sprintf("goal %s not found for user %s", id, userID))
+		return NewNotFoundError("goal")
+	}
+
+	return nil
+}
+
+// validateGoal checks the input and converts it into the parameters shared by the
+// create and update queries. A goal without a start date starts on defaultStart.
+func (g GoalService) validateGoal(ctx context.Context, goal InputGoal, defaultStart time.Time) (repository.CreateGoalParams, error) {
+	if err := Validate(goal); err != nil {
+		return repository.CreateGoalParams{}, err
+	}
+
+	startDate := defaultStart
+	if goal.StartDate != "" {
+		var err error
+		startDate, err = time.Parse(DateLayout, goal.StartDate)
+		if err != nil {
+			return repository.CreateGoalParams{}, NewValidationError("start_date", FieldInvalid, fmt.Sprintf("invalid date format: %s", goal.StartDate))
+		}
+	}
+	targetDate, err := time.Parse(DateLayout, goal.TargetDate)
+	if err != nil {
+		return repository.CreateGoalParams{}, NewValidationError("target_date", FieldInvalid, fmt.Sprintf("invalid date format: %s", goal.TargetDate))
+	}
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
+	if !targetDate.After(startDate) {
+		return repository.CreateGoalParams{}, NewValidationError("target_date", FieldInvalid, "target date must be after start date")
+	}
+
+	for _, categoryID := range goal.Categories {
+		dbCategory, err := g.Queries.GetCategoryById(ctx, repository.GetCategoryByIdParams{
+			ID:     categoryID,
+			UserID: goal.UserID,
+		})
+		if err != nil {
+			logger.Error(ctx, "Category not found", map[string]interface{}{
+				"category": categoryID,
+				"user_id":  goal.UserID,
+				"error":    err,
+			})
+			return repository.CreateGoalParams{}, NewValidationError("categories", FieldNotFound, fmt.Sprintf("category %s not found", categoryID))
+		}
+		if dbCategory.Type != CategoryTypeInvestment && dbCategory.Type != CategoryTypeIncome {
+			return repository.CreateGoalParams{}, NewValidationError("categories", FieldInvalid, fmt.Sprintf("category %s must be of type investment or income", dbCategory.Name))
+		}
+	}
+
+	money := &pgtype.Numeric{}
+	if err := money.Scan(goal.TargetAmount.String()); err != nil {
+		return repository.CreateGoalParams{}, NewValidationError("target_amount", FieldInvalid, fmt.Sprintf("invalid amount: %v", err))
+	}
+
+	return repository.CreateGoalParams{
+		ID:           goal.ID,
+		Name:         goal.Name,
+		TargetAmount: *money,
+		StartDate:    pgtype.Date{Time: startDate, Valid: true},
+		TargetDate:   pgtype.Date{Time: targetDate, Valid: true},
+		Note:         &goal.Note,
+		UserID:       goal.UserID,
+	}, nil
+}
+
+func linkGoalCategories(ctx context.Context, queries *repository.Queries, goalID uuid.UUID, categories []uuid.UUID) error {
+	linked := map[uuid.UUID]bool{}
+	for _, categoryID := range categories {
+		if linked[categoryID] {
+			continue
+		}
+		linked[categoryID] = true
+		if err := queries.AddGoalCategory(ctx, repository.AddGoalCategoryParams{
+			GoalID:     goalID,
+			CategoryID: categoryID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// goalResponses loads the linked categories and contributions of the goals and
+// computes their progress as of now.
+func (g GoalService) goalResponses(ctx context.Context, userID uuid.UUID, dbGoals []repository.Goal, now time.Time) ([]ResponseGoal, error) {
+	goals := []ResponseGoal{}
+	if len(dbGoals) == 0 {
+		return goals, nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := today
+	for _, dbGoal := range dbGoals {
+		if dbGoal.StartDate.Time.Before(from) {
+			from = dbGoal.StartDate.Time
+		}
+	}
+
+	dbCategories, err := g.Queries.GetGoalCategories(ctx, userID)
+	if err != nil {
+		logger.Error(ctx, "failed to get goal categories", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return []ResponseGoal{}, err
+	}
+	categories := map[uuid.UUID][]GoalCategory{}
+	for _, category := range dbCategories {
+		categories[category.GoalID] = append(categories[category.GoalID], GoalCategory{
+			ID:   category.ID,
+			Name: category.Name,
+			Type: category.Type,
+		})
+	}
+
+	fromDate := pgtype.Date{Time: from, Valid: true}
+	toDate := pgtype.Date{Time: today, Valid: true}
+
+	investmentRows, err := g.Queries.GetInvestmentsInRange(ctx, repository.GetInvestmentsInRangeParams{
+		UserID:   userID,
+		FromDate: fromDate,
+		ToDate:   toDate,
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to get investments for goals", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return []ResponseGoal{}, err
+	}
+	incomeRows, err := g.Queries.GetIncomesInRange(ctx, repository.GetIncomesInRangeParams{
+		UserID:   userID,
+		FromDate: fromDate,
+		ToDate:   toDate,
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to get incomes for goals", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return []ResponseGoal{}, err
+	}
+	investments := investmentRowsToEntries(ctx, investmentRows)
+	incomes := incomeRowsToEntries(ctx, incomeRows)
+
+	for _, dbGoal := range dbGoals {
+		target, err := numericToDecimal(dbGoal.TargetAmount)
+		if err != nil {
+			logger.Error(ctx, "failed to convert amount to decimal", map[string]interface{}{
+				"goal_id": dbGoal.ID,
+				"error":   err,
+			})
+		}
+		note := ""
+		if dbGoal.Note != nil {
+			note = *dbGoal.Note
+		}
+		goalCategories := categories[dbGoal.ID]
+		if goalCategories == nil {
+			goalCategories = []GoalCategory{}
+		}
+
+		contributions := goalContributions(goalCategories, investments, incomes)
+		goals = append(goals, ResponseGoal{
+			ID:           dbGoal.ID,
+			Name:         dbGoal.Name,
+			TargetAmount: target,
+			StartDate:    dbGoal.StartDate.Time.Format(DateLayout),
+			TargetDate:   dbGoal.TargetDate.Time.Format(DateLayout),
+			Note:         note,
+			Categories:   goalCategories,
+			Progress:     BuildGoalProgress(target, dbGoal.StartDate.Time, dbGoal.TargetDate.Time, contributions, today),
+		})
+	}
+
+	return goals, nil
+}
+
+// goalContributions picks the entries of the linked categories, or every investment
+// when the goal has no linked category.
+func goalContributions(categories []GoalCategory, investments, incomes []LedgerEntry) []LedgerEntry {
+	if len(categories) == 0 {
+		return investments
+	}
+
+	linked := map[uuid.UUID]bool{}
+	for _, category := range categories {
+		linked[category.ID] = true
+	}
+
+	contributions := []LedgerEntry{}
+	for _, entries := range [][]LedgerEntry{investments, incomes} {
+		for _, entry := range entries {
+			if linked[entry.CategoryID] {
+				contributions = append(contributions, entry)
+			}
+		}
+	}
+	return contributions
+}
+
+// BuildGoalProgress sums the contributions made between start and today and compares
+// them with an even saving plan from start to target.
+func BuildGoalProgress(target decimal.Decimal, start, targetDate time.Time, contributions []LedgerEntry, today time.Time) GoalProgress {
+	var contributed decimal.Decimal
+	for _, entry := range contributions {
+		if entry.Date.Before(start) || entry.Date.After(today) {
+			continue
+		}
+		contributed = contributed.Add(entry.Amount)
+	}
+
+	progress := GoalProgress{
+		Contributed:     contributed,
+		Remaining:       decimal.Max(target.Sub(contributed), decimal.Zero),
+		ExpectedByNow:   target,
+		RequiredMonthly: decimal.Zero,
+	}
+	if target.IsPositive() {
+		progress.Percent = roundTo(contributed.Div(target).InexactFloat64()*100, 2)
+	}
+
+	totalDays := targetDate.Sub(start).Hours() / 24
+	elapsedDays := today.Sub(start).Hours() / 24
+	if elapsedDays < totalDays && totalDays > 0 {
+		share := decimal.NewFromFloat(max(elapsedDays, 0) / totalDays)
+		progress.ExpectedByNow = target.Mul(share).Round(2)
+	}
+
+	if today.Before(targetDate) {
+		progress.MonthsRemaining = monthsUntil(today, targetDate)
+		progress.RequiredMonthly = progress.Remaining.Div(decimal.NewFromInt(int64(progress.MonthsRemaining))).Round(2)
+	}
+
+	switch {
+	case !progress.Remaining.IsPositive():
+		progress.Status = GoalStatusAchieved
+	case today.After(targetDate):
+		progress.Status = GoalStatusOverdue
+	case contributed.GreaterThanOrEqual(progress.ExpectedByNow):
+		progress.Status = GoalStatusOnTrack
+	default:
+		progress.Status = GoalStatusBehind
+	}
+
+	return progress
+}
+
+// monthsUntil returns the number of monthly contributions left before to, counting a
+// started month as a whole one.
+func monthsUntil(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if from.AddDate(0, months, 0).Before(to) {
+		months++
+	}
+	return max(months, 1)
+}
+
+func investmentRowsToEntries(ctx context.Context, rows []repository.GetInvestmentsInRangeRow) []LedgerEntry {
+	entries := make([]LedgerEntry, 0, len(rows))
+	for _, row := range rows {
+		amount, err := numericToDecimal(row.Amount)
+		if err != nil {
+			logger.Error(ctx, "failed to convert amount to decimal", map[string]interface{}{
+				"investment_id": row.ID,
+				"error":         err,
+			})
+			continue
+		}
+		entries = append(entries, LedgerEntry{
+			ID:         row.ID,
+			Name:       row.Name,
+			CategoryID: row.CategoryID,
+			Category:   row.Category,
+			Date:       row.Date.Time,
+			Amount:     amount,
+		})
+	}
+	return entries
+}
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestBuildGoalProgress(t *testing.T) {
+	contributions := func(amounts ...int64) []LedgerEntry {
+		entries := []LedgerEntry{
+			// Outside of start..today, never counted.
+			ledgerEntry(t, groceries, "2024-12-31", 5000),
+			ledgerEntry(t, groceries, "2025-07-16", 5000),
+		}
+		for _, amount := range amounts {
+			entries = append(entries, ledgerEntry(t, groceries, "2025-02-01", amount))
+		}
+		return entries
+	}
+
+	tests := []struct {
+		name          string
+		targetDate    string
+		today         string
+		contributions []LedgerEntry
+		want          GoalProgress
+	}{
+		{
+			name:          "target date passed",
+			targetDate:    "2025-06-30",
+			today:         "2025-07-15",
+			contributions: contributions(600),
+			want: GoalProgress{Contributed: decimal.NewFromInt(600), Remaining: decimal.NewFromInt(600), Percent: 50,
+				ExpectedByNow: decimal.NewFromInt(1200), RequiredMonthly: decimal.Zero, Status: GoalStatusOverdue},
+		},
+		{
+			name:          "target date today",
+			targetDate:    "2025-07-15",
+			today:         "2025-07-15",
+			contributions: contributions(1000),
+			want: GoalProgress{Contributed: decimal.NewFromInt(1000), Remaining: decimal.NewFromInt(200), Percent: 83.33,
+				ExpectedByNow: decimal.NewFromInt(1200), RequiredMonthly: decimal.Zero, Status: GoalStatusBehind},
+		},
+		{
+			name:          "target in the current month",
+			targetDate:    "2025-07-31",
+			today:         "2025-07-15",
+			contributions: contributions(700, 300),
+			want: GoalProgress{Contributed: decimal.NewFromInt(1000), Remaining: decimal.NewFromInt(200), Percent: 83.33,
+				ExpectedByNow: decimal.RequireFromString("1109"), MonthsRemaining: 1, RequiredMonthly: decimal.NewFromInt(200), Status: GoalStatusBehind},
+		},
+		{
+			name:          "on track",
+			targetDate:    "2025-12-31",
+			today:         "2025-07-01",
+			contributions: contributions(600),
+			want: GoalProgress{Contributed: decimal.NewFromInt(600), Remaining: decimal.NewFromInt(600), Percent: 50,
+				ExpectedByNow: decimal.RequireFromString("596.7"), MonthsRemaining: 6, RequiredMonthly: decimal.NewFromInt(100), Status: GoalStatusOnTrack},
+		},
+		{
+			name:          "already met",
+			targetDate:    "2025-12-31",
+			today:         "2025-07-15",
+			contributions: contributions(1300),
+			want: GoalProgress{Contributed: decimal.NewFromInt(1300), Remaining: decimal.Zero, Percent: 108.33,
+				ExpectedByNow: decimal.RequireFromString("642.86"), MonthsRemaining: 6, RequiredMonthly: decimal.Zero, Status: GoalStatusAchieved},
+		},
+		{
+			name:       "nothing contributed",
+			targetDate: "2025-12-31",
+			today:      "2025-01-01",
+			want: GoalProgress{Contributed: decimal.Zero, Remaining: decimal.NewFromInt(1200),
+				ExpectedByNow: decimal.Zero, MonthsRemaining: 12, RequiredMonthly: decimal.NewFromInt(100), Status: GoalStatusOnTrack},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildGoalProgress(decimal.NewFromInt(1200), mustDate(t, "2025-01-01"), mustDate(t, tt.targetDate), tt.contributions, mustDate(t, tt.today))
+
+			if !got.Contributed.Equal(tt.want.Contributed) || !got.Remaining.Equal(tt.want.Remaining) ||
+				!got.ExpectedByNow.Equal(tt.want.ExpectedByNow) || !got.RequiredMonthly.Equal(tt.want.RequiredMonthly) ||
+				got.Percent != tt.want.Percent || got.MonthsRemaining != tt.want.MonthsRemaining || got.Status != tt.want.Status {
+				t.Errorf("BuildGoalProgress =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonthsUntil(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     int
+	}{
+		{"2025-01-15", "2025-01-20", 1},
+		{"2025-01-15", "2025-02-15", 1},
+		{"2025-01-15", "2025-02-16", 2},
+		{"2025-01-15", "2025-03-15", 2},
+		{"2025-01-31", "2025-02-28", 1},
+		{"2025-12-10", "2026-01-05", 1},
+		{"2025-01-01", "2025-12-31", 12},
+		{"2025-07-15", "2025-07-15", 1},
+		{"2025-07-15", "2025-06-30", 1},
+	}
+	for _, tt := range tests {
+		if got := monthsUntil(mustDate(t, tt.from), mustDate(t, tt.to)); got != tt.want {
+			t.Errorf("monthsUntil(%s, %s) = %d, want %d", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestGoalContributions(t *testing.T) {
+	ppf := uuid.MustParse("00000000-0000-0000-0000-000000000003")
+	salary := uuid.MustParse("00000000-0000-0000-0000-000000000004")
+	entry := func(category uuid.UUID, amount int64) LedgerEntry {
+		return LedgerEntry{ID: uuid.New(), CategoryID: category, Amount: decimal.NewFromInt(amount)}
+	}
+	investments := []LedgerEntry{entry(ppf, 100), entry(rent, 200)}
+	incomes := []LedgerEntry{entry(salary, 1000), entry(groceries, 2000)}
+
+	tests := []struct {
+		name       string
+		categories []GoalCategory
+		want       int64
+	}{
+		{"no linked categories", nil, 300},
+		{"linked investment", []GoalCategory{{ID: ppf, Type: CategoryTypeInvestment}}, 100},
+		{"linked investment and income", []GoalCategory{{ID: ppf, Type: CategoryTypeInvestment}, {ID: salary, Type: CategoryTypeIncome}}, 1100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total := decimal.Zero
+			for _, contribution := range goalContributions(tt.categories, investments, incomes) {
+				total = total.Add(contribution.Amount)
+			}
+			if !total.Equal(decimal.NewFromInt(tt.want)) {
+				t.Errorf("contributions total %s, want %d", total, tt.want)
+			}
+		})
+	}
+}
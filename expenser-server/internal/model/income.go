@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
 	"github.com/keertirajmalik/expenser/expenser-server/logger"
@@ -18,11 +19,11 @@ import (
 
 type InputIncome struct {
 	ID       uuid.UUID       `json:"id"`
-	Name     string          `json:"name"`
-	Amount   decimal.Decimal `json:"amount"`
-	Category uuid.UUID       `json:"category"`
-	Date     string          `json:"date"`
-	Note     string          `json:"note"`
+	Name     string          `json:"name" validate:"required,max=100"`
+	Amount   decimal.Decimal `json:"amount" validate:"positive"`
+	Category uuid.UUID       `json:"category" validate:"required"`
+	Date     string          `json:"date" validate:"required,date,notfuture"`
+	Note     string          `json:"note" validate:"max=500"`
 	UserID   uuid.UUID       `json:"user_id"`
 }
 
@@ -38,12 +39,13 @@ type ResponseIncome struct {
 
 type IncomeService struct {
 	Queries *repository.Queries
+	DB      *pgxpool.Pool
 }
 
 func (i IncomeService) GetIncomesFromDB(ctx context.Context, userID uuid.UUID) ([]ResponseIncome, error) {
 	dbIncomes, err := i.Queries.GetIncome(ctx, userID)
 	if err != nil {
-		logger.Error("failed to get incomes: %v", map[string]interface{}{
+		logger.Error(ctx, "failed to get incomes: %v", map[string]interface{}{
 			"user_id": userID,
 			"error":   err,
 		})
@@ -58,7 +60,7 @@ func (i IncomeService) GetIncomesFromDB(ctx context.Context, userID uuid.UUID) (
 		}
 		date := ""
 		if income.Date.Valid {
-			date = income.Date.Time.Format("02/01/2006")
+			date = income.Date.Time.Format(DateLayout)
 		}
 
 		var money decimal.Decimal
@@ -72,7 +74,7 @@ func (i IncomeService) GetIncomesFromDB(ctx context.Context, userID uuid.UUID) (
 			var err error
 			money, err = decimal.NewFromString(numStr)
 			if err != nil {
-				logger.Error("failed to convert amount to decimal: %v", map[string]interface{}{
+				logger.Error(ctx, "failed to convert amount to decimal: %v", map[string]interface{}{
 					"amount": numStr,
 					"error":  err,
 				})
@@ -94,24 +96,32 @@ func (i IncomeService) GetIncomesFromDB(ctx context.Context, userID uuid.UUID) (
 }
 
 func (i IncomeService) AddIncomeToDB(ctx context.Context, income InputIncome) (ResponseIncome, error) {
-	parsedDate, err := time.Parse("02/01/2006", income.Date)
+	// Quick entries may leave the category out to use the default of the user.
+	if income.Category == uuid.Nil {
+		income.Category = defaultCategory(ctx, i.Queries, income.UserID, CategoryTypeIncome)
+	}
+	if err := ValidateAt(income, userNow(ctx, i.Queries, income.UserID)); err != nil {
+		return ResponseIncome{}, err
+	}
+
+	parsedDate, err := time.Parse(DateLayout, income.Date)
 	if err != nil {
-		logger.Error("failed to parse date", map[string]interface{}{
+		logger.Error(ctx, "failed to parse date", map[string]interface{}{
 			"date":  income.Date,
 			"error": err,
 		})
-		return ResponseIncome{}, fmt.Errorf("invalid date format: %s", income.Date)
+		return ResponseIncome{}, NewValidationError("date", FieldInvalid, fmt.Sprintf("invalid date format: %s", income.Date))
 	}
 
 	money := &pgtype.Numeric{}
 	err = money.Scan(income.Amount.String())
 	if err != nil {
-		logger.Error("failed to convert amount to numeric: %v", map[string]interface{}{
+		logger.Error(ctx, "failed to convert amount to numeric: %v", map[string]interface{}{
 			"amount": income.Amount,
 			"error":  err,
 		})
 
-		return ResponseIncome{}, fmt.Errorf("failed to convert amount type: %w", err)
+		return ResponseIncome{}, NewValidationError("amount", FieldInvalid, fmt.Sprintf("invalid amount: %v", err))
 	}
 
 	err = i.validateIncomeCategory(ctx, income.Category, income.UserID)
@@ -133,15 +143,15 @@ func (i IncomeService) AddIncomeToDB(ctx context.Context, income InputIncome) (R
 	})
 
 	if err != nil {
-		logger.Error("failed to create income for user : %v", map[string]interface{}{
+		logger.Error(ctx, "failed to create income for user : %v", map[string]interface{}{
 			"user_id": income.UserID,
 			"error":   err,
 		})
 
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeForeignKeyViolation {
-			logger.Warn(fmt.Sprintf("foreign key violation while creating income %s: non-existent category", income.ID))
-			return ResponseIncome{}, &database.ErrForeignKeyViolation{Message: "provide valid category"}
+			logger.Warn(ctx, fmt.Sprintf("foreign key violation while creating income %s: non-existent category", income.ID))
+			return ResponseIncome{}, NewValidationError("category", FieldNotFound, "provide valid category")
 		}
 		return ResponseIncome{}, fmt.Errorf("failed to create income: %w", err)
 	}
@@ -152,7 +162,7 @@ func (i IncomeService) AddIncomeToDB(ctx context.Context, income InputIncome) (R
 	}
 	date := ""
 	if dbIncome.Date.Valid {
-		date = dbIncome.Date.Time.Format("02/01/2006")
+		date = dbIncome.Date.Time.Format(DateLayout)
 	}
 
 	var dbMoney decimal.Decimal
@@ -166,7 +176,7 @@ func (i IncomeService) AddIncomeToDB(ctx context.Context, income InputIncome) (R
 		var err error
 		dbMoney, err = decimal.NewFromString(numStr)
 		if err != nil {
-			logger.Error("failed to convert amount to decimal: %v", map[string]interface{}{
+			logger.Error(ctx, "failed to convert amount to decimal: %v", map[string]interface{}{
 				"amount": numStr,
 				"error":  err,
 			})
@@ -185,25 +195,29 @@ func (i IncomeService) AddIncomeToDB(ctx context.Context, income InputIncome) (R
 }
 
 func (i IncomeService) UpdateIncomeInDB(ctx context.Context, income InputIncome) (ResponseIncome, error) {
-	parsedDate, err := time.Parse("02/01/2006", income.Date)
+	if err := ValidateAt(income, userNow(ctx, i.Queries, income.UserID)); err != nil {
+		return ResponseIncome{}, err
+	}
+
+	parsedDate, err := time.Parse(DateLayout, income.Date)
 	if err != nil {
-		logger.Error("failed to parse date", map[string]interface{}{
+		logger.Error(ctx, "failed to parse date", map[string]interface{}{
 			"income_id": income.ID,
-			"date":          income.Date,
-			"error":         err,
+			"date":      income.Date,
+			"error":     err,
 		})
-		return ResponseIncome{}, fmt.Errorf("invalid date format: %s", income.Date)
+		return ResponseIncome{}, NewValidationError("date", FieldInvalid, fmt.Sprintf("invalid date format: %s", income.Date))
 	}
 
 	money := &pgtype.Numeric{}
 	err = money.Scan(income.Amount.String())
 	if err != nil {
-		logger.Error("failed to convert amount to numeric: %v", map[string]interface{}{
+		logger.Error(ctx, "failed to convert amount to numeric: %v", map[string]interface{}{
 			"income_id": income.ID,
-			"amount":        income.Amount,
-			"error":         err,
+			"amount":    income.Amount,
+			"error":     err,
 		})
-		return ResponseIncome{}, fmt.Errorf("failed to convert amount type: %w", err)
+		return ResponseIncome{}, NewValidationError("amount", FieldInvalid, fmt.Sprintf("invalid amount: %v", err))
 	}
 
 	err = i.validateIncomeCategory(ctx, income.Category, income.UserID)
@@ -227,17 +241,17 @@ func (i IncomeService) UpdateIncomeInDB(ctx context.Context, income InputIncome)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeForeignKeyViolation {
-			logger.Warn(fmt.Sprintf("foreign key violation while updating income %s: non-existent category", income.ID))
-			return ResponseIncome{}, &database.ErrForeignKeyViolation{Message: "provide valid category"}
+			logger.Warn(ctx, fmt.Sprintf("foreign key violation while updating income %s: non-existent category", income.ID))
+			return ResponseIncome{}, NewValidationError("category", FieldNotFound, "provide valid category")
 		}
 		if errors.Is(err, pgx.ErrNoRows) {
-			logger.Warn(fmt.Sprintf("income %s not found for user %s", income.ID, income.UserID))
-			return ResponseIncome{}, errors.New("income not found")
+			logger.Warn(ctx, fmt.Sprintf("income %s not found for user %s", income.ID, income.UserID))
+			return ResponseIncome{}, NewNotFoundError("income")
 		}
-		logger.Error("failed to update income: %v", map[string]interface{}{
-			"user_id":       income.UserID,
+		logger.Error(ctx, "failed to update income: %v", map[string]interface{}{
+			"user_id":   income.UserID,
 			"income_id": income.ID,
-			"error":         err,
+			"error":     err,
 		})
 
 		return ResponseIncome{}, err
@@ -249,7 +263,7 @@ func (i IncomeService) UpdateIncomeInDB(ctx context.Context, income InputIncome)
 	}
 	date := ""
 	if dbIncome.Date.Valid {
-		date = dbIncome.Date.Time.Format("02/01/2006")
+		date = dbIncome.Date.Time.Format(DateLayout)
 	}
 
 	var dbMoney decimal.Decimal
@@ -263,7 +277,7 @@ func (i IncomeService) UpdateIncomeInDB(ctx context.Context, income InputIncome)
 		var err error
 		dbMoney, err = decimal.NewFromString(numStr)
 		if err != nil {
-			logger.Error("failed to convert amount to decimal: %v", map[string]interface{}{
+			logger.Error(ctx, "failed to convert amount to decimal: %v", map[string]interface{}{
 				"error": err,
 			})
 		}
@@ -287,45 +301,80 @@ func (i IncomeService) DeleteIncomeFromDB(ctx context.Context, id, userID uuid.U
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			logger.Warn(fmt.Sprintf("income %s not found for user %s", id, userID))
-			return errors.New("income not found")
+			logger.Warn(ctx, fmt.Sprintf("income %s not found for user %s", id, userID))
+			return NewNotFoundError("income")
 		}
-		logger.Error("failed to delete income", map[string]interface{}{
+		logger.Error(ctx, "failed to delete income", map[string]interface{}{
 			"income_id": id,
-			"user_id":       userID,
-			"error":         err,
+			"user_id":   userID,
+			"error":     err,
 		})
 		return err
 	}
 
 	rowAffected := result.RowsAffected()
 	if rowAffected == 0 {
-		logger.Warn(fmt.Sprintf("income %s not found for user %s", id, userID))
-		return errors.New("income not found")
+		logger.Warn(ctx, fmt.Sprintf("income %s not found for user %s", id, userID))
+		return NewNotFoundError("income")
 	}
 
 	return nil
 }
 
+// ApplyIncomeBatch validates every operation and then applies them atomically.
+// Either all operations are persisted or none are.
+func (i IncomeService) ApplyIncomeBatch(ctx context.Context, userID uuid.UUID, operations []BatchOperation) ([]BatchResult, error) {
+	err := validateBatch(operations, userNow(ctx, i.Queries, userID), func(categoryID uuid.UUID) error {
+		return i.validateIncomeCategory(ctx, categoryID, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return runBatch(ctx, i.DB, i.Queries, operations, func(queries *repository.Queries, op BatchOperation) (uuid.UUID, any, error) {
+		txService := IncomeService{Queries: queries}
+		income := InputIncome{
+			ID:       op.ID,
+			Name:     op.Name,
+			Amount:   op.Amount,
+			Category: op.Category,
+			Date:     op.Date,
+			Note:     op.Note,
+			UserID:   userID,
+		}
+
+		switch op.Op {
+		case BatchOpCreate:
+			created, err := txService.AddIncomeToDB(ctx, income)
+			return created.ID, created, err
+		case BatchOpUpdate:
+			updated, err := txService.UpdateIncomeInDB(ctx, income)
+			return updated.ID, updated, err
+		default:
+			return op.ID, nil, txService.DeleteIncomeFromDB(ctx, op.ID, userID)
+		}
+	})
+}
+
 func (i IncomeService) validateIncomeCategory(ctx context.Context, categoryID, userID uuid.UUID) error {
 	dbCategory, err := i.Queries.GetCategoryById(ctx, repository.GetCategoryByIdParams{
 		ID:     categoryID,
 		UserID: userID,
 	})
 	if err != nil {
-		logger.Error("Category not found", map[string]interface{}{
+		logger.Error(ctx, "Category not found", map[string]interface{}{
 			"category": categoryID,
 			"user_id":  userID,
 			"error":    err,
 		})
-		return fmt.Errorf("category type not found")
+		return NewValidationError("category", FieldNotFound, "category not found")
 	}
 	if dbCategory.Type != CategoryTypeIncome {
-		logger.Error("Category type should be Income", map[string]interface{}{
+		logger.Error(ctx, "Category type should be Income", map[string]interface{}{
 			"category_name": dbCategory.Name,
 			"category_type": dbCategory.Type,
 		})
-		return fmt.Errorf("category type should be income")
+		return NewValidationError("category", FieldInvalid, "category type should be income")
 	}
 	return nil
 }
@@ -0,0 +1,357 @@
+package model
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DefaultInsightTrailingMonths = 6
+	MaxInsightTrailingMonths     = 24
+
+	// categoryAnomalyZScore is how many standard deviations above its trailing
+	// monthly average a category has to be before it's flagged.
+	categoryAnomalyZScore = 2.0
+	// transactionAnomalyZScore is how many standard deviations above the category's
+	// typical transaction a single transaction has to be before it's flagged.
+	transactionAnomalyZScore = 3.0
+	minCategoryHistoryMonths = 3
+	minTransactionSamples    = 5
+)
+
+// LedgerEntry is a single dated amount of a user, used as input for the analytics.
+type LedgerEntry struct {
+	ID         uuid.UUID
+	Name       string
+	CategoryID uuid.UUID
+	Category   string
+	Date       time.Time
+	Amount     decimal.Decimal
+}
+
+type PeriodChange struct {
+	Current       decimal.Decimal `json:"current"`
+	Previous      decimal.Decimal `json:"previous"`
+	Change        decimal.Decimal `json:"change"`
+	ChangePercent *float64        `json:"change_percent"`
+}
+
+type CategoryInsight struct {
+	CategoryID      uuid.UUID       `json:"category_id"`
+	Category        string          `json:"category"`
+	CurrentMonth    decimal.Decimal `json:"current_month"`
+	TrailingAverage decimal.Decimal `json:"trailing_average"`
+	TrailingStdDev  decimal.Decimal `json:"trailing_std_dev"`
+	ZScore          *float64        `json:"z_score"`
+	Unusual         bool            `json:"unusual"`
+	MonthOverMonth  PeriodChange    `json:"month_over_month"`
+	YearOverYear    PeriodChange    `json:"year_over_year"`
+}
+
+type TransactionAnomaly struct {
+	ID            uuid.UUID       `json:"id"`
+	Name          string          `json:"name"`
+	Category      string          `json:"category"`
+	Date          string          `json:"date"`
+	Amount        decimal.Decimal `json:"amount"`
+	TypicalAmount decimal.Decimal `json:"typical_amount"`
+	ZScore        float64         `json:"z_score"`
+}
+
+type Insights struct {
+	From           string               `json:"from"`
+	To             string               `json:"to"`
+	TrailingMonths int                  `json:"trailing_months"`
+	Currency       string               `json:"currency"`
+	MonthOverMonth PeriodChange         `json:"month_over_month"`
+	YearOverYear   PeriodChange         `json:"year_over_year"`
+	Categories     []CategoryInsight    `json:"categories"`
+	Anomalies      []TransactionAnomaly `json:"anomalies"`
+}
+
+// GetInsightsFromDB compares the spending of the current month with the trailing
+// months and flags unusual categories and transactions.
+func (s ReportService) GetInsightsFromDB(ctx context.Context, userID uuid.UUID, now time.Time, trailingMonths int) (Insights, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := today.AddDate(0, 0, 1-today.Day())
+
+	from := monthStart.AddDate(-1, 0, 0)
+	if trailingMonths > 12 {
+		from = monthStart.AddDate(0, -trailingMonths, 0)
+	}
+
+	rows, err := s.Queries.GetTransactionsInRange(ctx, repository.GetTransactionsInRangeParams{
+		UserID:   userID,
+		FromDate: pgtype.Date{Time: from, Valid: true},
+		ToDate:   pgtype.Date{Time: today, Valid: true},
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to get transactions for insights", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return Insights{}, err
+	}
+
+	insights := BuildInsights(transactionRowsToEntries(ctx, rows), today, trailingMonths)
+	insights.Currency = userPreferences(ctx, s.Queries, userID).BaseCurrency
+	return insights, nil
+}
+
+func transactionRowsToEntries(ctx context.Context, rows []repository.GetTransactionsInRangeRow) []LedgerEntry {
+	entries := make([]LedgerEntry, 0, len(rows))
+	for _, row := range rows {
+		amount, err := numericToDecimal(row.Amount)
+		if err != nil {
+			logger.Error(ctx, "failed to convert amount to decimal", map[string]interface{}{
+				"transaction_id": row.ID,
+				"error":          err,
+			})
+			continue
+		}
+		entries = append(entries, LedgerEntry{
+			ID:         row.ID,
+			Name:       row.Name,
+			CategoryID: row.CategoryID,
+			Category:   row.Category,
+			Date:       row.Date.Time,
+			Amount:     amount,
+		})
+	}
+	return entries
+}
+
+func incomeRowsToEntries(ctx context.Context, rows []repository.GetIncomesInRangeRow) []LedgerEntry {
+	entries := make([]LedgerEntry, 0, len(rows))
+	for _, row := range rows {
+		amount, err := numericToDecimal(row.Amount)
+		if err != nil {
+			logger.Error(ctx, "failed to convert amount to decimal", map[string]interface{}{
+				"income_id": row.ID,
+				"error":     err,
+			})
+			continue
+		}
+		entries = append(entries, LedgerEntry{
+			ID:         row.ID,
+			Name:       row.Name,
+			CategoryID: row.CategoryID,
+			Category:   row.Category,
+			Date:       row.Date.Time,
+			Amount:     amount,
+		})
+	}
+	return entries
+}
+
+// BuildInsights computes the insights for the month containing today from the given
+// expense entries. It is deterministic: the same entries and date give the same result.
+// The current month has only run up to today, so category totals are compared with the
+// same days of each trailing month rather than with whole months.
+func BuildInsights(entries []LedgerEntry, today time.Time, trailingMonths int) Insights {
+	monthStart := today.AddDate(0, 0, 1-today.Day())
+	previousMonth := samePeriodIn(monthStart.AddDate(0, -1, 0), today.Day())
+	lastYear := samePeriodIn(monthStart.AddDate(-1, 0, 0), today.Day())
+
+	// On the last day of a month the whole of each trailing month counts.
+	elapsedDays := today.Day()
+	if today.AddDate(0, 0, 1).Day() == 1 {
+		elapsedDays = 31
+	}
+
+	type categoryData struct {
+		id       uuid.UUID
+		name     string
+		monthly  []float64
+		current  decimal.Decimal
+		samples  []float64
+		mom, yoy PeriodChange
+	}
+	categories := map[uuid.UUID]*categoryData{}
+	var currentEntries []LedgerEntry
+	var total, totalPreviousMonth, totalLastYear decimal.Decimal
+
+	for _, entry := range entries {
+		if entry.Date.After(today) {
+			continue
+		}
+
+		data, ok := categories[entry.CategoryID]
+		if !ok {
+			data = &categoryData{id: entry.CategoryID, name: entry.Category, monthly: make([]float64, trailingMonths)}
+			categories[entry.CategoryID] = data
+		}
+
+		offset := monthsBetween(entry.Date, monthStart)
+		switch {
+		case offset == 0:
+			data.current = data.current.Add(entry.Amount)
+			total = total.Add(entry.Amount)
+			currentEntries = append(currentEntries, entry)
+		case offset > 0 && offset <= trailingMonths:
+			if samePeriodIn(monthStart.AddDate(0, -offset, 0), elapsedDays).contains(entry.Date) {
+				data.monthly[trailingMonths-offset] += entry.Amount.InexactFloat64()
+			}
+			data.samples = append(data.samples, entry.Amount.InexactFloat64())
+		}
+
+		if previousMonth.contains(entry.Date) {
+			data.mom.Previous = data.mom.Previous.Add(entry.Amount)
+			totalPreviousMonth = totalPreviousMonth.Add(entry.Amount)
+		}
+		if lastYear.contains(entry.Date) {
+			data.yoy.Previous = data.yoy.Previous.Add(entry.Amount)
+			totalLastYear = totalLastYear.Add(entry.Amount)
+		}
+	}
+
+	insights := Insights{
+		From:           monthStart.Format(DateLayout),
+		To:             today.Format(DateLayout),
+		TrailingMonths: trailingMonths,
+		MonthOverMonth: newPeriodChange(total, totalPreviousMonth),
+		YearOverYear:   newPeriodChange(total, totalLastYear),
+		Categories:     []CategoryInsight{},
+		Anomalies:      []TransactionAnomaly{},
+	}
+
+	for _, data := range categories {
+		mean, stdDev := meanAndStdDev(data.monthly)
+		insight := CategoryInsight{
+			CategoryID:      data.id,
+			Category:        data.name,
+			CurrentMonth:    data.current,
+			TrailingAverage: decimal.NewFromFloat(mean).Round(2),
+			TrailingStdDev:  decimal.NewFromFloat(stdDev).Round(2),
+			MonthOverMonth:  newPeriodChange(data.current, data.mom.Previous),
+			YearOverYear:    newPeriodChange(data.current, data.yoy.Previous),
+		}
+
+		historyMonths := 0
+		for _, value := range data.monthly {
+			if value != 0 {
+				historyMonths++
+			}
+		}
+		if stdDev > 0 {
+			z := roundTo(zScore(data.current.InexactFloat64(), mean, stdDev), 2)
+			insight.ZScore = &z
+			insight.Unusual = historyMonths >= minCategoryHistoryMonths && z >= categoryAnomalyZScore
+		}
+		insights.Categories = append(insights.Categories, insight)
+	}
+
+	for _, entry := range currentEntries {
+		samples := categories[entry.CategoryID].samples
+		if len(samples) < minTransactionSamples {
+			continue
+		}
+		mean, stdDev := meanAndStdDev(samples)
+		if stdDev == 0 {
+			continue
+		}
+		z := roundTo(zScore(entry.Amount.InexactFloat64(), mean, stdDev), 2)
+		if z < transactionAnomalyZScore {
+			continue
+		}
+		insights.Anomalies = append(insights.Anomalies, TransactionAnomaly{
+			ID:            entry.ID,
+			Name:          entry.Name,
+			Category:      entry.Category,
+			Date:          entry.Date.Format(DateLayout),
+			Amount:        entry.Amount,
+			TypicalAmount: decimal.NewFromFloat(mean).Round(2),
+			ZScore:        z,
+		})
+	}
+
+	sort.Slice(insights.Categories, func(i, j int) bool {
+		a, b := insights.Categories[i], insights.Categories[j]
+		if !a.CurrentMonth.Equal(b.CurrentMonth) {
+			return a.CurrentMonth.GreaterThan(b.CurrentMonth)
+		}
+		return a.Category < b.Category
+	})
+	sort.Slice(insights.Anomalies, func(i, j int) bool {
+		a, b := insights.Anomalies[i], insights.Anomalies[j]
+		if a.ZScore != b.ZScore {
+			return a.ZScore > b.ZScore
+		}
+		return a.ID.String() < b.ID.String()
+	})
+
+	return insights
+}
+
+// dateRange is an inclusive range of days.
+type dateRange struct {
+	from, to time.Time
+}
+
+func (r dateRange) contains(date time.Time) bool {
+	return !date.Before(r.from) && !date.After(r.to)
+}
+
+// samePeriodIn returns the first day..day range of the month starting at monthStart,
+// clamped to the length of that month.
+func samePeriodIn(monthStart time.Time, day int) dateRange {
+	lastDay := monthStart.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return dateRange{from: monthStart, to: monthStart.AddDate(0, 0, day-1)}
+}
+
+// monthsBetween returns how many calendar months date lies before monthStart.
+func monthsBetween(date, monthStart time.Time) int {
+	return (monthStart.Year()-date.Year())*12 + int(monthStart.Month()-date.Month())
+}
+
+func newPeriodChange(current, previous decimal.Decimal) PeriodChange {
+	change := PeriodChange{
+		Current:  current,
+		Previous: previous,
+		Change:   current.Sub(previous),
+	}
+	if !previous.IsZero() {
+		percent := roundTo(change.Change.Div(previous).InexactFloat64()*100, 2)
+		change.ChangePercent = &percent
+	}
+	return change
+}
+
+// meanAndStdDev returns the mean and population standard deviation of values.
+func meanAndStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
+
+func zScore(value, mean, stdDev float64) float64 {
+	return (value - mean) / stdDev
+}
+
+func roundTo(value float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(value*factor) / factor
+}
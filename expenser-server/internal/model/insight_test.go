@@ -0,0 +1,272 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func mustDate(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(DateLayout, value)
+	if err != nil {
+		t.Fatalf("invalid date %q: %v", value, err)
+	}
+	return parsed
+}
+
+var (
+	groceries = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	rent      = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+)
+
+func ledgerEntry(t *testing.T, category uuid.UUID, day string, amount int64) LedgerEntry {
+	t.Helper()
+	name := "Groceries"
+	if category == rent {
+		name = "Rent"
+	}
+	return LedgerEntry{
+		ID:         uuid.New(),
+		Name:       name + " " + day,
+		CategoryID: category,
+		Category:   name,
+		Date:       mustDate(t, day),
+		Amount:     decimal.NewFromInt(amount),
+	}
+}
+
+func TestSamePeriodIn(t *testing.T) {
+	tests := []struct {
+		name       string
+		monthStart string
+		day        int
+		wantTo     string
+	}{
+		{"same day", "2025-02-01", 15, "2025-02-15"},
+		{"31st in a 30 day month", "2025-04-01", 31, "2025-04-30"},
+		{"31st in February", "2025-02-01", 31, "2025-02-28"},
+		{"29th in a leap February", "2024-02-01", 29, "2024-02-29"},
+		{"Feb 29 a year later", "2025-02-01", 29, "2025-02-28"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := samePeriodIn(mustDate(t, tt.monthStart), tt.day)
+			if got.from.Format(DateLayout) != tt.monthStart || got.to.Format(DateLayout) != tt.wantTo {
+				t.Errorf("samePeriodIn(%s, %d) = %s..%s, want %s..%s", tt.monthStart, tt.day,
+					got.from.Format(DateLayout), got.to.Format(DateLayout), tt.monthStart, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestBuildInsightsPeriodChanges(t *testing.T) {
+	tests := []struct {
+		name         string
+		today        string
+		entries      []string
+		wantCurrent  int64
+		wantPrevious int64
+		wantLastYear int64
+	}{
+		{
+			name:  "month over month up to the same day",
+			today: "2025-03-15",
+			entries: []string{
+				"2025-03-01", "2025-03-15", "2025-03-16",
+				"2025-02-10", "2025-02-15", "2025-02-16",
+				"2024-03-15", "2024-03-16",
+			},
+			wantCurrent:  2,
+			wantPrevious: 2,
+			wantLastYear: 1,
+		},
+		{
+			name:  "31st compares with all of February",
+			today: "2025-03-31",
+			entries: []string{
+				"2025-03-31",
+				"2025-02-01", "2025-02-28",
+				"2024-03-31",
+			},
+			wantCurrent:  1,
+			wantPrevious: 2,
+			wantLastYear: 1,
+		},
+		{
+			name:  "Feb 29 compares with all of February last year",
+			today: "2024-02-29",
+			entries: []string{
+				"2024-02-29",
+				"2024-01-29", "2024-01-30",
+				"2023-02-28",
+			},
+			wantCurrent:  1,
+			wantPrevious: 1,
+			wantLastYear: 1,
+		},
+		{
+			name:         "no previous spending leaves the percentage out",
+			today:        "2025-03-15",
+			entries:      []string{"2025-03-10"},
+			wantCurrent:  1,
+			wantPrevious: 0,
+			wantLastYear: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries []LedgerEntry
+			for _, day := range tt.entries {
+				entries = append(entries, ledgerEntry(t, groceries, day, 100))
+			}
+
+			insights := BuildInsights(entries, mustDate(t, tt.today), DefaultInsightTrailingMonths)
+
+			checkChange(t, "month over month", insights.MonthOverMonth, tt.wantCurrent*100, tt.wantPrevious*100)
+			checkChange(t, "year over year", insights.YearOverYear, tt.wantCurrent*100, tt.wantLastYear*100)
+		})
+	}
+}
+
+func checkChange(t *testing.T, name string, got PeriodChange, current, previous int64) {
+	t.Helper()
+	if !got.Current.Equal(decimal.NewFromInt(current)) || !got.Previous.Equal(decimal.NewFromInt(previous)) {
+		t.Errorf("%s = %s vs %s, want %d vs %d", name, got.Current, got.Previous, current, previous)
+	}
+	if !got.Change.Equal(decimal.NewFromInt(current - previous)) {
+		t.Errorf("%s change = %s, want %d", name, got.Change, current-previous)
+	}
+	if previous == 0 {
+		if got.ChangePercent != nil {
+			t.Errorf("%s change percent = %v, want nil", name, *got.ChangePercent)
+		}
+		return
+	}
+	want := roundTo(float64(current-previous)/float64(previous)*100, 2)
+	if got.ChangePercent == nil || *got.ChangePercent != want {
+		t.Errorf("%s change percent = %v, want %v", name, got.ChangePercent, want)
+	}
+}
+
+func TestBuildInsightsAnomalies(t *testing.T) {
+	// Six months of groceries averaging 110 with a standard deviation of about 22.36.
+	history := []struct {
+		day    string
+		amount int64
+	}{
+		{"2025-01-10", 100}, {"2025-02-10", 100}, {"2025-03-10", 100},
+		{"2025-04-10", 100}, {"2025-05-10", 100}, {"2025-06-10", 160},
+	}
+
+	tests := []struct {
+		name          string
+		history       int
+		current       []int64
+		wantUnusual   bool
+		wantAnomalies []int64
+	}{
+		{"below the category threshold", 6, []int64{150}, false, nil},
+		{"above the category threshold", 6, []int64{160}, true, nil},
+		{"too little history", 2, []int64{1000}, false, nil},
+		{"transaction above its threshold", 6, []int64{200, 60}, true, []int64{200}},
+		{"transaction below its threshold", 6, []int64{170}, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries []LedgerEntry
+			for _, h := range history[len(history)-tt.history:] {
+				entries = append(entries, ledgerEntry(t, groceries, h.day, h.amount))
+			}
+			for _, amount := range tt.current {
+				entries = append(entries, ledgerEntry(t, groceries, "2025-07-05", amount))
+			}
+			// A steady category is never unusual.
+			for _, h := range history {
+				entries = append(entries, ledgerEntry(t, rent, h.day, 1000))
+			}
+			entries = append(entries, ledgerEntry(t, rent, "2025-07-01", 1000))
+
+			insights := BuildInsights(entries, mustDate(t, "2025-07-15"), DefaultInsightTrailingMonths)
+
+			for _, category := range insights.Categories {
+				want := tt.wantUnusual && category.CategoryID == groceries
+				if category.Unusual != want {
+					t.Errorf("%s unusual = %v (z %v), want %v", category.Category, category.Unusual, category.ZScore, want)
+				}
+			}
+
+			if len(insights.Anomalies) != len(tt.wantAnomalies) {
+				t.Fatalf("got %d anomalies, want %d: %+v", len(insights.Anomalies), len(tt.wantAnomalies), insights.Anomalies)
+			}
+			for i, anomaly := range insights.Anomalies {
+				if !anomaly.Amount.Equal(decimal.NewFromInt(tt.wantAnomalies[i])) {
+					t.Errorf("anomaly %d amount = %s, want %d", i, anomaly.Amount, tt.wantAnomalies[i])
+				}
+				if anomaly.ZScore < transactionAnomalyZScore {
+					t.Errorf("anomaly %d z = %v, want at least %v", i, anomaly.ZScore, transactionAnomalyZScore)
+				}
+				if !anomaly.TypicalAmount.Equal(decimal.NewFromInt(110)) {
+					t.Errorf("anomaly %d typical amount = %s, want 110", i, anomaly.TypicalAmount)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildInsightsComparesSameDays(t *testing.T) {
+	// Groceries on the 10th average 110 with a standard deviation of about 22.36, and
+	// a steady 1000 on the 25th makes every whole month about ten times as much.
+	var history []LedgerEntry
+	for i, amount := range []int64{100, 100, 100, 100, 100, 160} {
+		month := time.Date(2025, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC)
+		history = append(history,
+			ledgerEntry(t, groceries, month.AddDate(0, 0, 9).Format(DateLayout), amount),
+			ledgerEntry(t, groceries, month.AddDate(0, 0, 24).Format(DateLayout), 1000))
+	}
+
+	tests := []struct {
+		name        string
+		today       string
+		current     []LedgerEntry
+		wantAverage int64
+	}{
+		{"mid-month", "2025-07-15", []LedgerEntry{ledgerEntry(t, groceries, "2025-07-05", 160)}, 110},
+		{"last day of the month", "2025-07-31", []LedgerEntry{
+			ledgerEntry(t, groceries, "2025-07-05", 160),
+			ledgerEntry(t, groceries, "2025-07-25", 1000),
+		}, 1110},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := append(append([]LedgerEntry{}, history...), tt.current...)
+			insights := BuildInsights(entries, mustDate(t, tt.today), DefaultInsightTrailingMonths)
+
+			if len(insights.Categories) != 1 {
+				t.Fatalf("got %d categories, want 1", len(insights.Categories))
+			}
+			category := insights.Categories[0]
+			if !category.TrailingAverage.Equal(decimal.NewFromInt(tt.wantAverage)) {
+				t.Errorf("trailing average = %s, want %d", category.TrailingAverage, tt.wantAverage)
+			}
+			if !category.Unusual {
+				t.Errorf("unusual = false (z %v), want true", category.ZScore)
+			}
+		})
+	}
+}
+
+func TestBuildInsightsIgnoresFutureEntries(t *testing.T) {
+	entries := []LedgerEntry{
+		ledgerEntry(t, groceries, "2025-03-10", 100),
+		ledgerEntry(t, groceries, "2025-03-20", 100),
+	}
+
+	insights := BuildInsights(entries, mustDate(t, "2025-03-15"), DefaultInsightTrailingMonths)
+
+	if !insights.MonthOverMonth.Current.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("current = %s, want 100", insights.MonthOverMonth.Current)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
 	"github.com/keertirajmalik/expenser/expenser-server/logger"
@@ -18,11 +19,11 @@ import (
 
 type InputInvestment struct {
 	ID       uuid.UUID       `json:"id"`
-	Name     string          `json:"name"`
-	Amount   decimal.Decimal `json:"amount"`
-	Category uuid.UUID       `json:"category"`
-	Date     string          `json:"date"`
-	Note     string          `json:"note"`
+	Name     string          `json:"name" validate:"required,max=100"`
+	Amount   decimal.Decimal `json:"amount" validate:"positive"`
+	Category uuid.UUID       `json:"category" validate:"required"`
+	Date     string          `json:"date" validate:"required,date,notfuture"`
+	Note     string          `json:"note" validate:"max=500"`
 	UserID   uuid.UUID       `json:"user_id"`
 }
 
@@ -38,12 +39,13 @@ type ResponseInvestment struct {
 
 type InvestmentService struct {
 	Queries *repository.Queries
+	DB      *pgxpool.Pool
 }
 
 func (i InvestmentService) GetInvestmentsFromDB(ctx context.Context, userID uuid.UUID) ([]ResponseInvestment, error) {
 	dbInvestments, err := i.Queries.GetInvestment(ctx, userID)
 	if err != nil {
-		logger.Error("failed to get investments: %v", map[string]interface{}{
+		logger.Error(ctx, "failed to get investments: %v", map[string]interface{}{
 			"user_id": userID,
 			"error":   err,
 		})
@@ -58,7 +60,7 @@ func (i InvestmentService) GetInvestmentsFromDB(ctx context.Context, userID uuid
 		}
 		date := ""
 		if investment.Date.Valid {
-			date = investment.Date.Time.Format("02/01/2006")
+			date = investment.Date.Time.Format(DateLayout)
 		}
 
 		var money decimal.Decimal
@@ -72,7 +74,7 @@ func (i InvestmentService) GetInvestmentsFromDB(ctx context.Context, userID uuid
 			var err error
 			money, err = decimal.NewFromString(numStr)
 			if err != nil {
-				logger.Error("failed to convert amount to decimal: %v", map[string]interface{}{
+				logger.Error(ctx, "failed to convert amount to decimal: %v", map[string]interface{}{
 					"amount": numStr,
 					"error":  err,
 				})
@@ -94,31 +96,39 @@ func (i InvestmentService) GetInvestmentsFromDB(ctx context.Context, userID uuid
 }
 
 func (i InvestmentService) AddInvestmentToDB(ctx context.Context, investment InputInvestment) (ResponseInvestment, error) {
-	parsedDate, err := time.Parse("02/01/2006", investment.Date)
+	// Quick entries may leave the category out to use the default of the user.
+	if investment.Category == uuid.Nil {
+		investment.Category = defaultCategory(ctx, i.Queries, investment.UserID, CategoryTypeInvestment)
+	}
+	if err := ValidateAt(investment, userNow(ctx, i.Queries, investment.UserID)); err != nil {
+		return ResponseInvestment{}, err
+	}
+
+	parsedDate, err := time.Parse(DateLayout, investment.Date)
 	if err != nil {
-		logger.Error("failed to parse date", map[string]interface{}{
+		logger.Error(ctx, "failed to parse date", map[string]interface{}{
 			"date":  investment.Date,
 			"error": err,
 		})
-		return ResponseInvestment{}, fmt.Errorf("invalid date format: %s", investment.Date)
+		return ResponseInvestment{}, NewValidationError("date", FieldInvalid, fmt.Sprintf("invalid date format: %s", investment.Date))
 	}
 
 	money := &pgtype.Numeric{}
 	err = money.Scan(investment.Amount.String())
 	if err != nil {
-		logger.Error("failed to convert amount to numeric: %v", map[string]interface{}{
+		logger.Error(ctx, "failed to convert amount to numeric: %v", map[string]interface{}{
 			"amount": investment.Amount,
 			"error":  err,
 		})
 
-		return ResponseInvestment{}, fmt.Errorf("failed to convert amount type: %w", err)
+		return ResponseInvestment{}, NewValidationError("amount", FieldInvalid, fmt.Sprintf("invalid amount: %v", err))
 	}
 
 	err = i.validateInvestmentCategory(ctx, investment.Category, investment.UserID)
 	if err != nil {
 		return ResponseInvestment{}, err
 	}
-	
+
 	dbInvestment, err := i.Queries.CreateInvestment(ctx, repository.CreateInvestmentParams{
 		ID:       uuid.New(),
 		Name:     investment.Name,
@@ -133,15 +143,15 @@ func (i InvestmentService) AddInvestmentToDB(ctx context.Context, investment Inp
 	})
 
 	if err != nil {
-		logger.Error("failed to create investment for user : %v", map[string]interface{}{
+		logger.Error(ctx, "failed to create investment for user : %v", map[string]interface{}{
 			"user_id": investment.UserID,
 			"error":   err,
 		})
 
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeForeignKeyViolation {
-			logger.Warn(fmt.Sprintf("foreign key violation while creating investment %s: non-existent category", investment.ID))
-			return ResponseInvestment{}, &database.ErrForeignKeyViolation{Message: "provide valid category"}
+			logger.Warn(ctx, fmt.Sprintf("foreign key violation while creating investment %s: non-existent category", investment.ID))
+			return ResponseInvestment{}, NewValidationError("category", FieldNotFound, "provide valid category")
 		}
 		return ResponseInvestment{}, fmt.Errorf("failed to create investment: %w", err)
 	}
@@ -152,7 +162,7 @@ func (i InvestmentService) AddInvestmentToDB(ctx context.Context, investment Inp
 	}
 	date := ""
 	if dbInvestment.Date.Valid {
-		date = dbInvestment.Date.Time.Format("02/01/2006")
+		date = dbInvestment.Date.Time.Format(DateLayout)
 	}
 
 	var dbMoney decimal.Decimal
@@ -166,7 +176,7 @@ func (i InvestmentService) AddInvestmentToDB(ctx context.Context, investment Inp
 		var err error
 		dbMoney, err = decimal.NewFromString(numStr)
 		if err != nil {
-			logger.Error("failed to convert amount to decimal: %v", map[string]interface{}{
+			logger.Error(ctx, "failed to convert amount to decimal: %v", map[string]interface{}{
 				"amount": numStr,
 				"error":  err,
 			})
@@ -185,25 +195,29 @@ func (i InvestmentService) AddInvestmentToDB(ctx context.Context, investment Inp
 }
 
 func (i InvestmentService) UpdateInvestmentInDB(ctx context.Context, investment InputInvestment) (ResponseInvestment, error) {
-	parsedDate, err := time.Parse("02/01/2006", investment.Date)
+	if err := ValidateAt(investment, userNow(ctx, i.Queries, investment.UserID)); err != nil {
+		return ResponseInvestment{}, err
+	}
+
+	parsedDate, err := time.Parse(DateLayout, investment.Date)
 	if err != nil {
-		logger.Error("failed to parse date", map[string]interface{}{
+		logger.Error(ctx, "failed to parse date", map[string]interface{}{
 			"investment_id": investment.ID,
 			"date":          investment.Date,
 			"error":         err,
 		})
-		return ResponseInvestment{}, fmt.Errorf("invalid date format: %s", investment.Date)
+		return ResponseInvestment{}, NewValidationError("date", FieldInvalid, fmt.Sprintf("invalid date format: %s", investment.Date))
 	}
 
 	money := &pgtype.Numeric{}
 	err = money.Scan(investment.Amount.String())
 	if err != nil {
-		logger.Error("failed to convert amount to numeric: %v", map[string]interface{}{
+		logger.Error(ctx, "failed to convert amount to numeric: %v", map[string]interface{}{
 			"investment_id": investment.ID,
 			"amount":        investment.Amount,
 			"error":         err,
 		})
-		return ResponseInvestment{}, fmt.Errorf("failed to convert amount type: %w", err)
+		return ResponseInvestment{}, NewValidationError("amount", FieldInvalid, fmt.Sprintf("invalid amount: %v", err))
 	}
 
 	err = i.validateInvestmentCategory(ctx, investment.Category, investment.UserID)
@@ -227,14 +241,14 @@ func (i InvestmentService) UpdateInvestmentInDB(ctx context.Context, investment
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeForeignKeyViolation {
-			logger.Warn(fmt.Sprintf("foreign key violation while updating investment %s: non-existent category", investment.ID))
-			return ResponseInvestment{}, &database.ErrForeignKeyViolation{Message: "provide valid category"}
+			logger.Warn(ctx, fmt.Sprintf("foreign key violation while updating investment %s: non-existent category", investment.ID))
+			return ResponseInvestment{}, NewValidationError("category", FieldNotFound, "provide valid category")
 		}
 		if errors.Is(err, pgx.ErrNoRows) {
-			logger.Warn(fmt.Sprintf("investment %s not found for user %s", investment.ID, investment.UserID))
-			return ResponseInvestment{}, errors.New("investment not found")
+			logger.Warn(ctx, fmt.Sprintf("investment %s not found for user %s", investment.ID, investment.UserID))
+			return ResponseInvestment{}, NewNotFoundError("investment")
 		}
-		logger.Error("failed to update investment: %v", map[string]interface{}{
+		logger.Error(ctx, "failed to update investment: %v", map[string]interface{}{
 			"user_id":       investment.UserID,
 			"investment_id": investment.ID,
 			"error":         err,
@@ -249,7 +263,7 @@ func (i InvestmentService) UpdateInvestmentInDB(ctx context.Context, investment
 	}
 	date := ""
 	if dbInvestment.Date.Valid {
-		date = dbInvestment.Date.Time.Format("02/01/2006")
+		date = dbInvestment.Date.Time.Format(DateLayout)
 	}
 
 	var dbMoney decimal.Decimal
@@ -263,7 +277,7 @@ func (i InvestmentService) UpdateInvestmentInDB(ctx context.Context, investment
 		var err error
 		dbMoney, err = decimal.NewFromString(numStr)
 		if err != nil {
-			logger.Error("failed to convert amount to decimal: %v", map[string]interface{}{
+			logger.Error(ctx, "failed to convert amount to decimal: %v", map[string]interface{}{
 				"error": err,
 			})
 		}
@@ -287,10 +301,10 @@ func (i InvestmentService) DeleteInvestmentFromDB(ctx context.Context, id, userI
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			logger.Warn(fmt.Sprintf("investment %s not found for user %s", id, userID))
-			return errors.New("investment not found")
+			logger.Warn(ctx, fmt.Sprintf("investment %s not found for user %s", id, userID))
+			return NewNotFoundError("investment")
 		}
-		logger.Error("failed to delete investment", map[string]interface{}{
+		logger.Error(ctx, "failed to delete investment", map[string]interface{}{
 			"investment_id": id,
 			"user_id":       userID,
 			"error":         err,
@@ -300,32 +314,67 @@ func (i InvestmentService) DeleteInvestmentFromDB(ctx context.Context, id, userI
 
 	rowAffected := result.RowsAffected()
 	if rowAffected == 0 {
-		logger.Warn(fmt.Sprintf("investment %s not found for user %s", id, userID))
-		return errors.New("investment not found")
+		logger.Warn(ctx, fmt.Sprintf("investment %s not found for user %s", id, userID))
+		return NewNotFoundError("investment")
 	}
 
 	return nil
 }
 
+// ApplyInvestmentBatch validates every operation and then applies them atomically.
+// Either all operations are persisted or none are.
+func (i InvestmentService) ApplyInvestmentBatch(ctx context.Context, userID uuid.UUID, operations []BatchOperation) ([]BatchResult, error) {
+	err := validateBatch(operations, userNow(ctx, i.Queries, userID), func(categoryID uuid.UUID) error {
+		return i.validateInvestmentCategory(ctx, categoryID, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return runBatch(ctx, i.DB, i.Queries, operations, func(queries *repository.Queries, op BatchOperation) (uuid.UUID, any, error) {
+		txService := InvestmentService{Queries: queries}
+		investment := InputInvestment{
+			ID:       op.ID,
+			Name:     op.Name,
+			Amount:   op.Amount,
+			Category: op.Category,
+			Date:     op.Date,
+			Note:     op.Note,
+			UserID:   userID,
+		}
+
+		switch op.Op {
+		case BatchOpCreate:
+			created, err := txService.AddInvestmentToDB(ctx, investment)
+			return created.ID, created, err
+		case BatchOpUpdate:
+			updated, err := txService.UpdateInvestmentInDB(ctx, investment)
+			return updated.ID, updated, err
+		default:
+			return op.ID, nil, txService.DeleteInvestmentFromDB(ctx, op.ID, userID)
+		}
+	})
+}
+
 func (i InvestmentService) validateInvestmentCategory(ctx context.Context, categoryID, userID uuid.UUID) error {
 	dbCategory, err := i.Queries.GetCategoryById(ctx, repository.GetCategoryByIdParams{
 		ID:     categoryID,
 		UserID: userID,
 	})
 	if err != nil {
-		logger.Error("Category not found", map[string]interface{}{
+		logger.Error(ctx, "Category not found", map[string]interface{}{
 			"category": categoryID,
 			"user_id":  userID,
 			"error":    err,
 		})
-		return fmt.Errorf("category type not found")
+		return NewValidationError("category", FieldNotFound, "category not found")
 	}
 	if dbCategory.Type != CategoryTypeInvestment {
-		logger.Error("Category type should be Investment", map[string]interface{}{
+		logger.Error(ctx, "Category type should be Investment", map[string]interface{}{
 			"category_name": dbCategory.Name,
 			"category_type": dbCategory.Type,
 		})
-		return fmt.Errorf("category type should be investment")
+		return NewValidationError("category", FieldInvalid, "category type should be investment")
 	}
 	return nil
 }
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+)
+
+// DateLayout is the ISO 8601 format of the dates accepted and returned by the API.
+// The date format preference of a user only applies to presentation and imports.
+const DateLayout = "2006-01-02"
+
+const (
+	DefaultLocale     = "en-IN"
+	DefaultDateFormat = "DD/MM/YYYY"
+	DefaultTimezone   = "UTC"
+)
+
+// DateFormats maps the supported date format preferences to their Go layouts.
+var DateFormats = map[string]string{
+	"DD/MM/YYYY": "02/01/2006",
+	"MM/DD/YYYY": "01/02/2006",
+	"YYYY-MM-DD": "2006-01-02",
+	"DD-MM-YYYY": "02-01-2006",
+	"DD.MM.YYYY": "02.01.2006",
+}
+
+// localePattern accepts language tags like en, en-IN or hi-Latn-IN.
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z][a-z]{3})?(-([A-Z]{2}|[0-9]{3}))?$`)
+
+func validLocale(locale string) bool {
+	return localePattern.MatchString(locale)
+}
+
+// currencyPattern accepts ISO 4217 alphabetic codes like INR or USD.
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func validCurrency(currency string) bool {
+	return currencyPattern.MatchString(currency)
+}
+
+func validTimezone(timezone string) bool {
+	_, err := time.LoadLocation(timezone)
+	return err == nil
+}
+
+// DateFormatLayout returns the Go layout of a date format preference, falling back
+// to the default format for unknown values.
+func DateFormatLayout(dateFormat string) string {
+	if layout, ok := DateFormats[dateFormat]; ok {
+		return layout
+	}
+	return DateFormats[DefaultDateFormat]
+}
+
+// userNow returns the current time in the timezone of the user, so "today" and
+// "this month" start at the user's midnight rather than the server's. It falls
+// back to UTC when the user or their timezone can't be loaded.
+func userNow(ctx context.Context, queries *repository.Queries, userID uuid.UUID) time.Time {
+	return time.Now().In(userPreferences(ctx, queries, userID).Location())
+}
@@ -0,0 +1,247 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/keertirajmalik/expenser/expenser-server/oidc"
+)
+
+// OIDCLoginTTL is how long a login may take at the identity provider.
+const OIDCLoginTTL = 10 * time.Minute
+
+// OIDCService logs users in with an OpenID provider. Provider is nil when OIDC login
+// isn't configured.
+type OIDCService struct {
+	Queries  *repository.Queries
+	DB       *pgxpool.Pool
+	Provider *oidc.Provider
+	// UsernameClaim and NameClaim name the ID token claims users are created from.
+	UsernameClaim string
+	NameClaim     string
+	// LinkExisting links a first login to the local account of the same username, see
+	// linksExisting.
+	LinkExisting bool
+	// PostLoginURL is the UI page receiving the access token, empty to answer with JSON.
+	PostLoginURL string
+}
+
+func (s OIDCService) Enabled() bool {
+	return s.Provider != nil
+}
+
+// StartLogin stores the state, nonce and PKCE verifier of a new login and returns the
+// provider URL to redirect the browser to, and the state to tie the login to the browser.
+// With reauth the provider is asked to authenticate the user again even when they have
+// a session there.
+func (s OIDCService) StartLogin(ctx context.Context, reauth bool) (authURL, state string, err error) {
+	state, err = oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, challenge, err := oidc.NewPKCE()
+	if err != nil {
+		return "", "", err
+	}
+
+	// Abandoned logins are cleaned up as new ones start.
+	if err := s.Queries.DeleteExpiredOIDCLogins(ctx); err != nil {
+		logger.Warn(ctx, "failed to delete expired oidc logins", map[string]interface{}{"error": err})
+	}
+
+	err = s.Queries.CreateOIDCLogin(ctx, repository.CreateOIDCLoginParams{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    pgtype.Timestamptz{Time: time.Now().UTC().Add(OIDCLoginTTL), Valid: true},
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to store oidc login", map[string]interface{}{"error": err})
+		return "", "", err
+	}
+
+	authURL, err = s.Provider.AuthCodeURL(ctx, state, nonce, challenge, reauth)
+	if err != nil {
+		logger.Error(ctx, "failed to build oidc authorization url", map[string]interface{}{"error": err})
+		return "", "", err
+	}
+	return authURL, state, nil
+}
+
+// FinishLogin redeems the code the provider sent back with state and returns the user of
+// the identity, linking or creating one on its first login.
+func (s OIDCService) FinishLogin(ctx context.Context, state, code string) (User, error) {
+	login, err := s.Queries.ConsumeOIDCLogin(ctx, state)
+	if errors.Is(err, pgx.ErrNoRows) {
+		logger.Warn(ctx, "unknown oidc login state")
+		return User{}, NewUnauthorizedError("login expired or already used, please try again")
+	}
+	if err != nil {
+		logger.Error(ctx, "failed to get oidc login", map[string]interface{}{"error": err})
+		return User{}, err
+	}
+	if !login.ExpiresAt.Time.After(time.Now()) {
+		return User{}, NewUnauthorizedError("login expired or already used, please try again")
+	}
+
+	claims, err := s.Provider.Exchange(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		logger.Warn(ctx, "oidc code exchange failed", map[string]interface{}{"error": err})
+		return User{}, NewUnauthorizedError("the identity provider didn't confirm the login")
+	}
+
+	subject := claims.String("sub")
+	username, err := s.username(claims)
+	if err != nil {
+		logger.Warn(ctx, "oidc id token has no usable username", map[string]interface{}{
+			"claim":   s.UsernameClaim,
+			"subject": subject,
+			"error":   err,
+		})
+		return User{}, err
+	}
+	var authenticatedAt pgtype.Timestamptz
+	authenticatedAt.Time, authenticatedAt.Valid = authTime(claims, time.Now())
+
+	var dbUser repository.User
+	err = database.WithTx(ctx, s.DB, func(tx pgx.Tx) error {
+		queries := s.Queries.WithTx(tx)
+		identity := repository.GetUserByIdentityParams{Issuer: s.Provider.Issuer(), Subject: subject}
+
+		var err error
+		dbUser, err = queries.GetUserByIdentity(ctx, identity)
+		if err == nil {
+			return queries.TouchUserIdentity(ctx, repository.TouchUserIdentityParams{
+				Issuer:          identity.Issuer,
+				Subject:         identity.Subject,
+				AuthenticatedAt: authenticatedAt,
+			})
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		dbUser, err = queries.GetUserByUsername(ctx, username)
+		switch {
+		case err == nil && !s.linksExisting():
+			return NewConflictError(CodeDuplicate, fmt.Sprintf("an account named %s already exists", username))
+		case err == nil:
+			logger.Info(ctx, "linking oidc identity to existing user", map[string]interface{}{"user_id": dbUser.ID})
+		case errors.Is(err, pgx.ErrNoRows):
+			dbUser, err = s.createUser(ctx, queries, username, claims.String(s.NameClaim))
+			if err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		return queries.CreateUserIdentity(ctx, repository.CreateUserIdentityParams{
+			ID:              uuid.New(),
+			UserID:          dbUser.ID,
+			Issuer:          identity.Issuer,
+			Subject:         identity.Subject,
+			AuthenticatedAt: authenticatedAt,
+		})
+	})
+	if err != nil {
+		var conflictErr *ConflictError
+		if errors.As(err, &conflictErr) {
+			return User{}, err
+		}
+		logger.Error(ctx, "failed to log in oidc identity", map[string]interface{}{
+			"subject": subject,
+			"error":   err,
+		})
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeUniqueViolation {
+			return User{}, NewConflictError(CodeConflict, "the login raced with another one, please try again")
+		}
+		return User{}, err
+	}
+
+	return convertDBUserToUser([]repository.User{dbUser})[0], nil
+}
+
+// username returns the username claim of an ID token. Email addresses are only taken
+// when the provider says they are verified, so an unverified address can't take over
+// the account it names.
+func (s OIDCService) username(claims oidc.Claims) (string, error) {
+	username := claims.String(s.UsernameClaim)
+	if username == "" {
+		return "", NewUnauthorizedError(fmt.Sprintf("the identity provider sent no %s", s.UsernameClaim))
+	}
+	if s.UsernameClaim == "email" {
+		if verified, _ := claims["email_verified"].(bool); !verified {
+			return "", NewUnauthorizedError("the email address isn't verified at the identity provider")
+		}
+	}
+	return username, nil
+}
+
+// linksExisting reports whether first logins are linked to local accounts. Only verified
+// email addresses, which username checks, are trusted to name the account: the provider
+// lets users set other claims like preferred_username to anything.
+func (s OIDCService) linksExisting() bool {
+	return s.LinkExisting && s.UsernameClaim == "email"
+}
+
+// authTime returns when the provider authenticated the user, from the auth_time claim.
+// Providers reusing a session send the time of the original sign in, so it tells a
+// fresh login from a silent one; ok is false without the claim, as the login may have
+// been silent. Times ahead of now are taken as now.
+func authTime(claims oidc.Claims, now time.Time) (authenticatedAt time.Time, ok bool) {
+	authenticatedAt, ok = claims.Time("auth_time")
+	if !ok {
+		return time.Time{}, false
+	}
+	if authenticatedAt.After(now) {
+		return now, true
+	}
+	return authenticatedAt, true
+}
+
+// createUser creates the user of a first OIDC login with the default categories. Its
+// password is random and never shown, so the account can only be used through OIDC.
+func (s OIDCService) createUser(ctx context.Context, queries *repository.Queries, username, name string) (repository.User, error) {
+	password, err := oidc.RandomString()
+	if err != nil {
+		return repository.User{}, err
+	}
+	hashedPassword, err := auth.HashPassword(password)
+	if err != nil {
+		return repository.User{}, err
+	}
+
+	dbUser, err := queries.CreateUser(ctx, repository.CreateUserParams{
+		ID:             uuid.New(),
+		Name:           name,
+		Username:       username,
+		HashedPassword: hashedPassword,
+		PasswordSet:    false,
+	})
+	if err != nil {
+		return repository.User{}, err
+	}
+
+	if _, err := applyCategoryTemplates(ctx, queries, dbUser.ID, DefaultCategoryTemplates); err != nil {
+		return repository.User{}, err
+	}
+	logger.Info(ctx, "created user on first oidc login", map[string]interface{}{"user_id": dbUser.ID})
+	return dbUser, nil
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/keertirajmalik/expenser/expenser-server/oidc"
+	"github.com/keertirajmalik/expenser/expenser-server/oidc/oidctest"
+)
+
+// devidpClaims signs email in at devidp and returns the claims of the ID token the
+// callback gets.
+func devidpClaims(t *testing.T, email string, verified bool) oidc.Claims {
+	t.Helper()
+	idp, err := oidctest.NewServer("expenser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(idp.Close)
+
+	ctx := context.Background()
+	provider := oidc.New(oidc.Config{Issuer: idp.URL, ClientID: "expenser", RedirectURL: "http://localhost/cxf/v1/oidc/callback"})
+	verifier, challenge, err := oidc.NewPKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", challenge, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	callback, err := idp.SignIn(ctx, authURL, email, verified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := provider.Exchange(ctx, callback.Query().Get("code"), verifier, "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return claims
+}
+
+func TestOIDCUsername(t *testing.T) {
+	verified := devidpClaims(t, "ada@example.com", true)
+	unverified := devidpClaims(t, "ada@example.com", false)
+	missing := oidc.Claims{"sub": "ada", "email": "ada@example.com"}
+
+	tests := []struct {
+		name   string
+		claim  string
+		claims oidc.Claims
+		want   string
+	}{
+		{"verified email", "email", verified, "ada@example.com"},
+		{"unverified email", "email", unverified, ""},
+		{"email_verified missing", "email", missing, ""},
+		{"email_verified as a string", "email", oidc.Claims{"email": "ada@example.com", "email_verified": "true"}, ""},
+		{"unverified email under another claim", "sub", unverified, "ada@example.com"},
+		{"claim missing", "preferred_username", verified, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OIDCService{UsernameClaim: tt.claim}.username(tt.claims)
+			if tt.want == "" {
+				var unauthorizedErr *UnauthorizedError
+				if !errors.As(err, &unauthorizedErr) {
+					t.Errorf("username = %q, %v, want an unauthorized error", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("username = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOIDCAuthTime(t *testing.T) {
+	signedIn := devidpClaims(t, "ada@example.com", true)
+	now := time.Now()
+	if got, ok := authTime(signedIn, now.Add(time.Minute)); !ok || now.Sub(got) > time.Minute || got.After(now) {
+		t.Errorf("auth time of a devidp sign in = %s, %v, want about %s", got, ok, now)
+	}
+
+	earlier := now.Add(-time.Hour).Truncate(time.Second)
+	tests := []struct {
+		name   string
+		claims oidc.Claims
+		want   time.Time
+		wantOK bool
+	}{
+		{"session reused at the provider", oidc.Claims{"auth_time": float64(earlier.Unix())}, earlier, true},
+		{"claim in the future", oidc.Claims{"auth_time": float64(now.Add(time.Hour).Unix())}, now, true},
+		{"claim missing", oidc.Claims{}, time.Time{}, false},
+		{"claim not a number", oidc.Claims{"auth_time": "yesterday"}, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := authTime(tt.claims, now)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("authTime = %s, %v, want %s, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestOIDCLinksExisting(t *testing.T) {
+	tests := []struct {
+		claim        string
+		linkExisting bool
+		want         bool
+	}{
+		{"email", true, true},
+		{"email", false, false},
+		{"preferred_username", true, false},
+		{"sub", true, false},
+	}
+	for _, tt := range tests {
+		service := OIDCService{UsernameClaim: tt.claim, LinkExisting: tt.linkExisting}
+		if got := service.linksExisting(); got != tt.want {
+			t.Errorf("linksExisting with %s and link existing %v = %v, want %v", tt.claim, tt.linkExisting, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxPeriodOffset limits how many periods back an identifier like month-3 may reach.
+const MaxPeriodOffset = 1200
+
+// Period is a named date range, From and To inclusive, at midnight UTC like all dates.
+type Period struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+// Contains reports whether date, in DateLayout, lies in the period.
+func (p Period) Contains(date string) bool {
+	return date >= p.From.Format(DateLayout) && date <= p.To.Format(DateLayout)
+}
+
+var (
+	periodPattern      = regexp.MustCompile(`^(week|month|quarter|year|fy|pay_cycle)(-([0-9]+))?$`)
+	periodMonthPattern = regexp.MustCompile(`^([0-9]{4})-([0-9]{2})$`)
+	periodYearPattern  = regexp.MustCompile(`^(fy)?([0-9]{4})$`)
+)
+
+// ResolvePeriod resolves the period identifier name into dates from today and the
+// preferences of the user. The identifiers are
+//
+//	week, month, quarter, year  the calendar period containing today
+//	fy                          the fiscal year, starting in FiscalYearStartMonth
+//	pay_cycle                   the pay cycle, starting on PayCycleStartDay
+//	<unit>-N                    the period N before the current one, like month-1
+//	this_<unit>, last_<unit>    aliases of <unit> and <unit>-1, like last_fy
+//	2025-09                     a calendar month
+//	2025                        a calendar year
+//	fy2025                      the fiscal year starting in 2025
+//
+// Weeks start on FirstDayOfWeek and quarters are calendar quarters.
+func ResolvePeriod(name string, today time.Time, preferences Preferences) (Period, error) {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	id := strings.ToLower(strings.TrimSpace(name))
+	if unit, ok := strings.CutPrefix(id, "this_"); ok {
+		id = unit
+	} else if unit, ok := strings.CutPrefix(id, "last_"); ok {
+		id = unit + "-1"
+	}
+
+	period := Period{Name: name}
+	if match := periodPattern.FindStringSubmatch(id); match != nil {
+		offset := 0
+		if match[3] != "" {
+			var err error
+			offset, err = strconv.Atoi(match[3])
+			if err != nil || offset > MaxPeriodOffset {
+				return Period{}, NewValidationError("period", FieldInvalid, fmt.Sprintf("period offset must be at most %d", MaxPeriodOffset))
+			}
+		}
+		period.From, period.To = periodRange(match[1], offset, today, preferences)
+		return period, nil
+	}
+
+	if match := periodMonthPattern.FindStringSubmatch(id); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		if month >= 1 && month <= 12 {
+			period.From = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+			period.To = period.From.AddDate(0, 1, -1)
+			return period, nil
+		}
+	}
+
+	if match := periodYearPattern.FindStringSubmatch(id); match != nil {
+		year, _ := strconv.Atoi(match[2])
+		startMonth := time.January
+		if match[1] == "fy" {
+			startMonth = fiscalYearStartMonth(preferences)
+		}
+		period.From = time.Date(year, startMonth, 1, 0, 0, 0, 0, time.UTC)
+		period.To = period.From.AddDate(1, 0, -1)
+		return period, nil
+	}
+
+	return Period{}, NewValidationError("period", FieldInvalid, fmt.Sprintf("unknown period %q (like this_month, last_fy, pay_cycle-2, 2025-09 or fy2025)", name))
+}
+
+// periodRange returns the period of unit offset periods before the one containing today.
+func periodRange(unit string, offset int, today time.Time, preferences Preferences) (time.Time, time.Time) {
+	switch unit {
+	case "week":
+		shift := (int(today.Weekday()) - int(preferences.WeekStart()) + 7) % 7
+		from := today.AddDate(0, 0, -shift-7*offset)
+		return from, from.AddDate(0, 0, 6)
+	case "quarter":
+		from := time.Date(today.Year(), today.Month()-(today.Month()-1)%3, 1, 0, 0, 0, 0, time.UTC).AddDate(0, -3*offset, 0)
+		return from, from.AddDate(0, 3, -1)
+	case "year":
+		from := time.Date(today.Year()-offset, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(1, 0, -1)
+	case "fy":
+		startMonth := fiscalYearStartMonth(preferences)
+		year := today.Year()
+		if today.Month() < startMonth {
+			year--
+		}
+		from := time.Date(year-offset, startMonth, 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(1, 0, -1)
+	case "pay_cycle":
+		year, month := today.Year(), today.Month()
+		if today.Before(payCycleStart(year, month, preferences.PayCycleStartDay)) {
+			month--
+		}
+		month -= time.Month(offset)
+		from := payCycleStart(year, month, preferences.PayCycleStartDay)
+		return from, payCycleStart(year, month+1, preferences.PayCycleStartDay).AddDate(0, 0, -1)
+	}
+
+	from := time.Date(today.Year(), today.Month()-time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(0, 1, -1)
+}
+
+// payCycleStart returns the day the pay cycle starting in month begins, the last day of
+// the month when it is shorter than day. Months out of range are normalized.
+func payCycleStart(year int, month time.Month, day int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	if day < 1 {
+		day = 1
+	}
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+func fiscalYearStartMonth(preferences Preferences) time.Month {
+	if preferences.FiscalYearStartMonth < 1 || preferences.FiscalYearStartMonth > 12 {
+		return time.January
+	}
+	return time.Month(preferences.FiscalYearStartMonth)
+}
+
+// ResolvePeriod resolves the period identifier name for the user, see ResolvePeriod.
+func (s ReportService) ResolvePeriod(ctx context.Context, userID uuid.UUID, name string) (Period, error) {
+	preferences := userPreferences(ctx, s.Queries, userID)
+	return ResolvePeriod(name, time.Now().In(preferences.Location()), preferences)
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResolvePeriod(t *testing.T) {
+	india := Preferences{FirstDayOfWeek: "Monday", FiscalYearStartMonth: 4, PayCycleStartDay: 25}
+	monthEnd := Preferences{FirstDayOfWeek: "Monday", FiscalYearStartMonth: 4, PayCycleStartDay: 31}
+	weekStarting := func(day string) Preferences { return Preferences{FirstDayOfWeek: day} }
+
+	tests := []struct {
+		name        string
+		period      string
+		today       string
+		preferences Preferences
+		wantFrom    string
+		wantTo      string
+	}{
+		{"fy on its last day", "fy", "2025-03-31", india, "2024-04-01", "2025-03-31"},
+		{"fy on its first day", "fy", "2025-04-01", india, "2025-04-01", "2026-03-31"},
+		{"last fy on the first day of the next", "last_fy", "2025-04-01", india, "2024-04-01", "2025-03-31"},
+		{"fy offset", "fy-2", "2025-03-31", india, "2022-04-01", "2023-03-31"},
+		{"fy without a start month", "fy", "2025-03-31", Preferences{}, "2025-01-01", "2025-12-31"},
+		{"fy by year", "fy2025", "2020-01-01", india, "2025-04-01", "2026-03-31"},
+
+		{"pay cycle the day before it starts", "pay_cycle", "2025-02-24", india, "2025-01-25", "2025-02-24"},
+		{"pay cycle on its first day", "pay_cycle", "2025-02-25", india, "2025-02-25", "2025-03-24"},
+		{"pay cycle across the year", "pay_cycle-1", "2025-01-10", india, "2024-11-25", "2024-12-24"},
+		{"pay cycle on the 31st before February ends", "pay_cycle", "2025-02-27", monthEnd, "2025-01-31", "2025-02-27"},
+		{"pay cycle on the 31st at February's end", "pay_cycle", "2025-02-28", monthEnd, "2025-02-28", "2025-03-30"},
+		{"pay cycle on the 31st in a leap February", "pay_cycle", "2024-02-28", monthEnd, "2024-01-31", "2024-02-28"},
+		{"pay cycle on the 31st on Feb 29", "pay_cycle", "2024-02-29", monthEnd, "2024-02-29", "2024-03-30"},
+		{"pay cycle on the 31st into a 30 day month", "pay_cycle", "2025-03-31", monthEnd, "2025-03-31", "2025-04-29"},
+		{"last pay cycle on the 31st", "last_pay_cycle", "2025-03-15", monthEnd, "2025-01-31", "2025-02-27"},
+
+		// 2025-03-12 is a Wednesday.
+		{"week from Monday", "week", "2025-03-12", weekStarting("Monday"), "2025-03-10", "2025-03-16"},
+		{"week from Sunday", "week", "2025-03-12", weekStarting("Sunday"), "2025-03-09", "2025-03-15"},
+		{"week starting today", "week", "2025-03-12", weekStarting("Wednesday"), "2025-03-12", "2025-03-18"},
+		{"week starting tomorrow", "week", "2025-03-12", weekStarting("Thursday"), "2025-03-06", "2025-03-12"},
+		{"week without a start day", "week", "2025-03-12", Preferences{}, "2025-03-10", "2025-03-16"},
+		{"week offset", "week-2", "2025-03-12", weekStarting("Monday"), "2025-02-24", "2025-03-02"},
+
+		{"quarter", "quarter", "2025-03-12", india, "2025-01-01", "2025-03-31"},
+		{"this quarter", "this_quarter", "2025-05-20", india, "2025-04-01", "2025-06-30"},
+		{"quarter offset across the year", "quarter-1", "2025-03-12", india, "2024-10-01", "2024-12-31"},
+		{"quarter offset over a year", "quarter-5", "2025-03-12", india, "2023-10-01", "2023-12-31"},
+
+		{"month", "month", "2025-03-12", india, "2025-03-01", "2025-03-31"},
+		{"this month", "this_month", "2025-03-12", india, "2025-03-01", "2025-03-31"},
+		{"month offset", "month-1", "2025-03-12", india, "2025-02-01", "2025-02-28"},
+		{"last month into a leap February", "last_month", "2024-03-31", india, "2024-02-01", "2024-02-29"},
+		{"month offset across the year", "month-3", "2025-03-12", india, "2024-12-01", "2024-12-31"},
+		{"month offset over a year", "month-14", "2025-03-12", india, "2024-01-01", "2024-01-31"},
+
+		{"year", "this_year", "2025-03-12", india, "2025-01-01", "2025-12-31"},
+		{"last year", "last_year", "2025-03-12", india, "2024-01-01", "2024-12-31"},
+		{"calendar month", "2024-02", "2025-03-12", india, "2024-02-01", "2024-02-29"},
+		{"calendar year", "2024", "2025-03-12", india, "2024-01-01", "2024-12-31"},
+		{"upper case and spaces", " Last_Month ", "2025-03-12", india, "2025-02-01", "2025-02-28"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolvePeriod(tt.period, mustDate(t, tt.today), tt.preferences)
+			if err != nil {
+				t.Fatalf("ResolvePeriod(%q): %v", tt.period, err)
+			}
+			if got.Name != tt.period {
+				t.Errorf("name = %q, want %q", got.Name, tt.period)
+			}
+			if from, to := got.From.Format(DateLayout), got.To.Format(DateLayout); from != tt.wantFrom || to != tt.wantTo {
+				t.Errorf("ResolvePeriod(%q) on %s = %s..%s, want %s..%s", tt.period, tt.today, from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestResolvePeriodUsesTheDateOfToday(t *testing.T) {
+	// Late in the evening in India is still the same day, whatever the time in UTC.
+	kolkata := time.FixedZone("IST", 5*60*60+30*60)
+	got, err := ResolvePeriod("month", time.Date(2025, 3, 31, 23, 0, 0, 0, kolkata), Preferences{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.From.Format(DateLayout) != "2025-03-01" || got.From.Location() != time.UTC {
+		t.Errorf("from = %s, want 2025-03-01 in UTC", got.From)
+	}
+}
+
+func TestResolvePeriodInvalid(t *testing.T) {
+	for _, name := range []string{"", "fortnight", "next_month", "last_", "month-", "month-x", "month-1201", "2025-13", "2025-9", "fy25"} {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolvePeriod(name, mustDate(t, "2025-03-12"), Preferences{})
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("ResolvePeriod(%q) = %+v, %v, want a validation error", name, got, err)
+			}
+		})
+	}
+}
+
+func TestPayCycleStart(t *testing.T) {
+	tests := []struct {
+		name  string
+		year  int
+		month time.Month
+		day   int
+		want  string
+	}{
+		{"within the month", 2025, time.March, 25, "2025-03-25"},
+		{"past a 30 day month", 2025, time.April, 31, "2025-04-30"},
+		{"past February", 2025, time.February, 30, "2025-02-28"},
+		{"past a leap February", 2024, time.February, 31, "2024-02-29"},
+		{"before January", 2025, 0, 25, "2024-12-25"},
+		{"after December", 2025, 13, 31, "2026-01-31"},
+		{"day zero", 2025, time.March, 0, "2025-03-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := payCycleStart(tt.year, tt.month, tt.day).Format(DateLayout); got != tt.want {
+				t.Errorf("payCycleStart(%d, %d, %d) = %s, want %s", tt.year, tt.month, tt.day, got, tt.want)
+			}
+		})
+	}
+}
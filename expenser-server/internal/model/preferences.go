@@ -0,0 +1,257 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+)
+
+const (
+	DefaultBaseCurrency         = "INR"
+	DefaultFirstDayOfWeek       = "Monday"
+	DefaultFiscalYearStartMonth = 4
+	DefaultPayCycleStartDay     = 1
+)
+
+// Preferences are the settings of a user that shape how entries are made and reported.
+type Preferences struct {
+	// BaseCurrency is the ISO 4217 code amounts are recorded and reported in.
+	BaseCurrency string `json:"base_currency"`
+	Locale       string `json:"locale"`
+	DateFormat   string `json:"date_format"`
+	Timezone     string `json:"timezone"`
+	// FirstDayOfWeek starts the weeks of reports, like Monday.
+	FirstDayOfWeek string `json:"first_day_of_week"`
+	// FiscalYearStartMonth is the month the fiscal year starts in, 4 for April.
+	FiscalYearStartMonth int `json:"fiscal_year_start_month"`
+	// PayCycleStartDay is the day of the month pay cycles start on, like 25 for cycles
+	// from the 25th to the 24th. Days past the end of a month start the cycle on its
+	// last day.
+	PayCycleStartDay int `json:"pay_cycle_start_day"`
+	// DefaultCategories maps every category type to the category new entries and imported
+	// rows get when none is given, nil when unset.
+	DefaultCategories map[string]*uuid.UUID `json:"default_categories"`
+}
+
+// PreferencesUpdate holds the preferences to change. Empty values keep the current
+// preference; default categories are only changed for the types listed, and a null
+// category clears the default of its type.
+type PreferencesUpdate struct {
+	BaseCurrency         string                `json:"base_currency" validate:"currency"`
+	Locale               string                `json:"locale" validate:"locale"`
+	DateFormat           string                `json:"date_format" validate:"dateformat"`
+	Timezone             string                `json:"timezone" validate:"timezone"`
+	FirstDayOfWeek       string                `json:"first_day_of_week" validate:"oneof=Sunday|Monday|Tuesday|Wednesday|Thursday|Friday|Saturday"`
+	FiscalYearStartMonth int                   `json:"fiscal_year_start_month"`
+	PayCycleStartDay     int                   `json:"pay_cycle_start_day"`
+	DefaultCategories    map[string]*uuid.UUID `json:"default_categories"`
+}
+
+// Location returns the timezone of the preferences, UTC when it can't be loaded.
+func (p Preferences) Location() *time.Location {
+	location, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// WeekStart returns FirstDayOfWeek as a time.Weekday.
+func (p Preferences) WeekStart() time.Weekday {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if day.String() == p.FirstDayOfWeek {
+			return day
+		}
+	}
+	return time.Monday
+}
+
+// DefaultPreferences are the preferences of new users.
+func DefaultPreferences() Preferences {
+	return Preferences{
+		BaseCurrency:         DefaultBaseCurrency,
+		Locale:               DefaultLocale,
+		DateFormat:           DefaultDateFormat,
+		Timezone:             DefaultTimezone,
+		FirstDayOfWeek:       DefaultFirstDayOfWeek,
+		FiscalYearStartMonth: DefaultFiscalYearStartMonth,
+		PayCycleStartDay:     DefaultPayCycleStartDay,
+		DefaultCategories:    map[string]*uuid.UUID{},
+	}
+}
+
+func (s UserService) GetPreferencesFromDB(ctx context.Context, userID uuid.UUID) (Preferences, error) {
+	dbUser, err := s.Queries.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Preferences{}, NewNotFoundError("user")
+		}
+		logger.Error(ctx, "failed to get user preferences", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return Preferences{}, err
+	}
+	return convertDBUserToPreferences(dbUser), nil
+}
+
+// UpdatePreferencesInDB applies update to the preferences of the user. Default
+// categories must belong to the user and be of the type they are the default of.
+func (s UserService) UpdatePreferencesInDB(ctx context.Context, userID uuid.UUID, update PreferencesUpdate) (Preferences, error) {
+	if err := Validate(update); err != nil {
+		return Preferences{}, err
+	}
+	if update.FiscalYearStartMonth < 0 || update.FiscalYearStartMonth > 12 {
+		return Preferences{}, NewValidationError("fiscal_year_start_month", FieldInvalid, "fiscal_year_start_month must be between 1 and 12")
+	}
+	if update.PayCycleStartDay < 0 || update.PayCycleStartDay > 31 {
+		return Preferences{}, NewValidationError("pay_cycle_start_day", FieldInvalid, "pay_cycle_start_day must be between 1 and 31")
+	}
+
+	var dbUser repository.User
+	err := database.WithTx(ctx, s.DB, func(tx pgx.Tx) error {
+		queries := s.Queries.WithTx(tx)
+
+		var err error
+		dbUser, err = queries.GetUserById(ctx, userID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return NewNotFoundError("user")
+		}
+		if err != nil {
+			return err
+		}
+
+		params := updatePreferencesParams(dbUser)
+		params.BaseCurrency = valueOr(update.BaseCurrency, params.BaseCurrency)
+		params.Locale = valueOr(update.Locale, params.Locale)
+		params.DateFormat = valueOr(update.DateFormat, params.DateFormat)
+		params.Timezone = valueOr(update.Timezone, params.Timezone)
+		params.FirstDayOfWeek = valueOr(update.FirstDayOfWeek, params.FirstDayOfWeek)
+		if update.FiscalYearStartMonth != 0 {
+			params.FiscalYearStartMonth = int32(update.FiscalYearStartMonth)
+		}
+		if update.PayCycleStartDay != 0 {
+			params.PayCycleStartDay = int32(update.PayCycleStartDay)
+		}
+
+		for categoryType, categoryID := range update.DefaultCategories {
+			if err := validateDefaultCategory(ctx, queries, userID, categoryType, categoryID); err != nil {
+				return err
+			}
+			switch categoryType {
+			case CategoryTypeExpense:
+				params.DefaultExpenseCategory = categoryID
+			case CategoryTypeIncome:
+				params.DefaultIncomeCategory = categoryID
+			case CategoryTypeInvestment:
+				params.DefaultInvestmentCategory = categoryID
+			}
+		}
+
+		dbUser, err = queries.UpdateUserPreferences(ctx, params)
+		return err
+	})
+	if err != nil {
+		var validationErr *ValidationError
+		var notFoundErr *NotFoundError
+		if errors.As(err, &validationErr) || errors.As(err, &notFoundErr) {
+			return Preferences{}, err
+		}
+		logger.Error(ctx, "failed to update user preferences", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return Preferences{}, err
+	}
+
+	return convertDBUserToPreferences(dbUser), nil
+}
+
+func validateDefaultCategory(ctx context.Context, queries *repository.Queries, userID uuid.UUID, categoryType string, categoryID *uuid.UUID) error {
+	field := "default_categories." + categoryType
+	if !ValidCategoryTypes[categoryType] {
+		return NewValidationError(field, FieldInvalid, fmt.Sprintf("invalid category type: %q (must be %s, %s or %s)", categoryType, CategoryTypeExpense, CategoryTypeIncome, CategoryTypeInvestment))
+	}
+	if categoryID == nil {
+		return nil
+	}
+
+	dbCategory, err := queries.GetCategoryById(ctx, repository.GetCategoryByIdParams{
+		ID:     *categoryID,
+		UserID: userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewValidationError(field, FieldNotFound, "category not found")
+	}
+	if err != nil {
+		return err
+	}
+	if dbCategory.Type != categoryType {
+		return NewValidationError(field, FieldInvalid, fmt.Sprintf("category type should be %s", categoryType))
+	}
+	return nil
+}
+
+// updatePreferencesParams returns the parameters that keep every preference of dbUser.
+func updatePreferencesParams(dbUser repository.User) repository.UpdateUserPreferencesParams {
+	return repository.UpdateUserPreferencesParams{
+		ID:                        dbUser.ID,
+		Locale:                    dbUser.Locale,
+		DateFormat:                dbUser.DateFormat,
+		Timezone:                  dbUser.Timezone,
+		BaseCurrency:              dbUser.BaseCurrency,
+		FirstDayOfWeek:            dbUser.FirstDayOfWeek,
+		FiscalYearStartMonth:      dbUser.FiscalYearStartMonth,
+		DefaultExpenseCategory:    dbUser.DefaultExpenseCategory,
+		DefaultIncomeCategory:     dbUser.DefaultIncomeCategory,
+		DefaultInvestmentCategory: dbUser.DefaultInvestmentCategory,
+		PayCycleStartDay:          dbUser.PayCycleStartDay,
+	}
+}
+
+func convertDBUserToPreferences(dbUser repository.User) Preferences {
+	return Preferences{
+		BaseCurrency:         dbUser.BaseCurrency,
+		Locale:               dbUser.Locale,
+		DateFormat:           dbUser.DateFormat,
+		Timezone:             dbUser.Timezone,
+		FirstDayOfWeek:       dbUser.FirstDayOfWeek,
+		FiscalYearStartMonth: int(dbUser.FiscalYearStartMonth),
+		PayCycleStartDay:     int(dbUser.PayCycleStartDay),
+		DefaultCategories: map[string]*uuid.UUID{
+			CategoryTypeExpense:    dbUser.DefaultExpenseCategory,
+			CategoryTypeIncome:     dbUser.DefaultIncomeCategory,
+			CategoryTypeInvestment: dbUser.DefaultInvestmentCategory,
+		},
+	}
+}
+
+// userPreferences returns the preferences of the user for services outside UserService,
+// falling back to the defaults when the user can't be loaded.
+func userPreferences(ctx context.Context, queries *repository.Queries, userID uuid.UUID) Preferences {
+	dbUser, err := queries.GetUserById(ctx, userID)
+	if err != nil {
+		logger.Warn(ctx, "failed to get user preferences, using defaults", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return DefaultPreferences()
+	}
+	return convertDBUserToPreferences(dbUser)
+}
+
+// defaultCategory returns the default category of categoryType for the user, or
+// uuid.Nil when there is none.
+func defaultCategory(ctx context.Context, queries *repository.Queries, userID uuid.UUID, categoryType string) uuid.UUID {
+	if categoryID := userPreferences(ctx, queries, userID).DefaultCategories[categoryType]; categoryID != nil {
+		return *categoryID
+	}
+	return uuid.Nil
+}
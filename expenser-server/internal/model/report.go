@@ -0,0 +1,136 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/shopspring/decimal"
+)
+
+type CategorySummary struct {
+	ID       uuid.UUID  `json:"id"`
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	ParentID *uuid.UUID `json:"parent_id"`
+	// Total is the sum of entries recorded directly against the category.
+	Total decimal.Decimal `json:"total"`
+	// RollupTotal is Total plus the rollup totals of all sub categories.
+	RollupTotal decimal.Decimal   `json:"rollup_total"`
+	Children    []CategorySummary `json:"children"`
+}
+
+type Summary struct {
+	// Period is the period identifier the summary was asked for, if any.
+	Period     string                     `json:"period,omitempty"`
+	From       string                     `json:"from"`
+	To         string                     `json:"to"`
+	Currency   string                     `json:"currency"`
+	Totals     map[string]decimal.Decimal `json:"totals"`
+	Categories []CategorySummary          `json:"categories"`
+}
+
+type ReportService struct {
+	Queries *repository.Queries
+	// DeductionCaps is the yearly cap of the deductions per tax section.
+	DeductionCaps map[string]decimal.Decimal
+}
+
+// Now returns the current time in the timezone of the user. Report periods like
+// "this month" are resolved against it.
+func (s ReportService) Now(ctx context.Context, userID uuid.UUID) time.Time {
+	return userNow(ctx, s.Queries, userID)
+}
+
+// GetSummaryFromDB totals every category of the user between from and to (inclusive)
+// and rolls the totals of sub categories up into their parents.
+func (s ReportService) GetSummaryFromDB(ctx context.Context, userID uuid.UUID, from, to time.Time) (Summary, error) {
+	if to.Before(from) {
+		return Summary{}, NewValidationError("from", FieldInvalid, "from date must not be after to date")
+	}
+
+	rows, err := s.Queries.GetCategoryTotals(ctx, repository.GetCategoryTotalsParams{
+		UserID:   userID,
+		FromDate: pgtype.Date{Time: from, Valid: true},
+		ToDate:   pgtype.Date{Time: to, Valid: true},
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to get category totals", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return Summary{}, err
+	}
+
+	children := map[uuid.UUID][]repository.GetCategoryTotalsRow{}
+	roots := []repository.GetCategoryTotalsRow{}
+	for _, row := range rows {
+		if row.ParentID == nil {
+			roots = append(roots, row)
+			continue
+		}
+		children[*row.ParentID] = append(children[*row.ParentID], row)
+	}
+
+	var build func(row repository.GetCategoryTotalsRow) CategorySummary
+	build = func(row repository.GetCategoryTotalsRow) CategorySummary {
+		total, err := numericToDecimal(row.Total)
+		if err != nil {
+			logger.Error(ctx, "failed to convert amount to decimal", map[string]interface{}{
+				"category_id": row.ID,
+				"error":       err,
+			})
+		}
+
+		node := CategorySummary{
+			ID:          row.ID,
+			Name:        row.Name,
+			Type:        row.Type,
+			ParentID:    row.ParentID,
+			Total:       total,
+			RollupTotal: total,
+			Children:    []CategorySummary{},
+		}
+		for _, child := range children[row.ID] {
+			childSummary := build(child)
+			node.RollupTotal = node.RollupTotal.Add(childSummary.RollupTotal)
+			node.Children = append(node.Children, childSummary)
+		}
+		return node
+	}
+
+	summary := Summary{
+		From:       from.Format(DateLayout),
+		To:         to.Format(DateLayout),
+		Currency:   userPreferences(ctx, s.Queries, userID).BaseCurrency,
+		Totals:     map[string]decimal.Decimal{},
+		Categories: []CategorySummary{},
+	}
+	for categoryType := range ValidCategoryTypes {
+		summary.Totals[categoryType] = decimal.Zero
+	}
+	for _, root := range roots {
+		node := build(root)
+		summary.Totals[node.Type] = summary.Totals[node.Type].Add(node.RollupTotal)
+		summary.Categories = append(summary.Categories, node)
+	}
+
+	return summary, nil
+}
+
+// numericToDecimal converts a pgtype.Numeric coming from the DB into a decimal.Decimal.
+func numericToDecimal(n pgtype.Numeric) (decimal.Decimal, error) {
+	if !n.Valid {
+		return decimal.Zero, nil
+	}
+
+	numStr := n.Int.String()
+	if n.Exp != 0 {
+		numStr = fmt.Sprintf("%se%d", numStr, n.Exp)
+	}
+	return decimal.NewFromString(numStr)
+}
@@ -0,0 +1,168 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// Search is a full-text search of the transactions, incomes and investments of a user.
+// Query is matched against the name, category name and note of the entries in web search
+// syntax: quotes for phrases, OR between alternatives and a leading - to exclude a word.
+// The other fields narrow the results down when they are set.
+type Search struct {
+	Query     string           `json:"q" validate:"required,max=200"`
+	Kind      string           `json:"kind" validate:"oneof=transaction|income|investment"`
+	Category  *uuid.UUID       `json:"category"`
+	From      *time.Time       `json:"from"`
+	To        *time.Time       `json:"to"`
+	MinAmount *decimal.Decimal `json:"min_amount"`
+	MaxAmount *decimal.Decimal `json:"max_amount"`
+	// Limit is the number of results, DefaultSearchLimit when zero.
+	Limit int `json:"limit"`
+}
+
+// SearchHighlights are the name and note of a result as HTML, with the matching words
+// wrapped in <mark> tags.
+type SearchHighlights struct {
+	Name string `json:"name"`
+	Note string `json:"note"`
+}
+
+type SearchResult struct {
+	Kind       string           `json:"kind"`
+	ID         uuid.UUID        `json:"id"`
+	Name       string           `json:"name"`
+	Amount     decimal.Decimal  `json:"amount"`
+	CategoryID uuid.UUID        `json:"category_id"`
+	Category   string           `json:"category"`
+	Date       string           `json:"date"`
+	Note       string           `json:"note"`
+	Rank       float32          `json:"rank"`
+	Highlights SearchHighlights `json:"highlights"`
+}
+
+type SearchResults struct {
+	Query   string         `json:"q"`
+	Results []SearchResult `json:"results"`
+}
+
+type SearchService struct {
+	Queries *repository.Queries
+}
+
+// SearchEntriesInDB returns the entries of the user matching search, best matches first.
+func (s SearchService) SearchEntriesInDB(ctx context.Context, userID uuid.UUID, search Search) (SearchResults, error) {
+	if err := Validate(search); err != nil {
+		return SearchResults{}, err
+	}
+	if search.Limit < 0 || search.Limit > MaxSearchLimit {
+		return SearchResults{}, NewValidationError("limit", FieldInvalid, fmt.Sprintf("limit must be between 1 and %d", MaxSearchLimit))
+	}
+	if search.MinAmount != nil && search.MaxAmount != nil && search.MinAmount.GreaterThan(*search.MaxAmount) {
+		return SearchResults{}, NewValidationError("min_amount", FieldInvalid, "min_amount can't be greater than max_amount")
+	}
+
+	params := repository.SearchEntriesParams{
+		Query:       search.Query,
+		UserID:      userID,
+		Category:    search.Category,
+		ResultLimit: int32(DefaultSearchLimit),
+	}
+	if search.Kind != "" {
+		params.Kind = &search.Kind
+	}
+	if search.From != nil {
+		params.FromDate = pgtype.Date{Time: *search.From, Valid: true}
+	}
+	if search.To != nil {
+		params.ToDate = pgtype.Date{Time: *search.To, Valid: true}
+	}
+	if search.MinAmount != nil {
+		if err := params.MinAmount.Scan(search.MinAmount.String()); err != nil {
+			return SearchResults{}, err
+		}
+	}
+	if search.MaxAmount != nil {
+		if err := params.MaxAmount.Scan(search.MaxAmount.String()); err != nil {
+			return SearchResults{}, err
+		}
+	}
+	if search.Limit > 0 {
+		params.ResultLimit = int32(search.Limit)
+	}
+
+	rows, err := s.Queries.SearchEntries(ctx, params)
+	if err != nil {
+		logger.Error(ctx, "failed to search entries", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return SearchResults{}, err
+	}
+
+	results := SearchResults{Query: search.Query, Results: []SearchResult{}}
+	for _, row := range rows {
+		amount, err := numericToDecimal(row.Amount)
+		if err != nil {
+			logger.Error(ctx, "failed to convert amount to decimal", map[string]interface{}{
+				"entry_id": row.ID,
+				"error":    err,
+			})
+		}
+
+		var date, note string
+		if row.Date.Valid {
+			date = row.Date.Time.Format(DateLayout)
+		}
+		if row.Note != nil {
+			note = *row.Note
+		}
+
+		results.Results = append(results.Results, SearchResult{
+			Kind:       row.Kind,
+			ID:         row.ID,
+			Name:       row.Name,
+			Amount:     amount,
+			CategoryID: row.CategoryID,
+			Category:   row.Category,
+			Date:       date,
+			Note:       note,
+			Rank:       row.Rank,
+			Highlights: SearchHighlights{
+				Name: escapeHighlight(row.NameHighlight),
+				Note: escapeHighlight(row.NoteHighlight),
+			},
+		})
+	}
+
+	return results, nil
+}
+
+// ts_headline marks the matching words with these control characters, which the search
+// query strips from names and notes, so a <mark> typed by the user stays text.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+var highlightMarker = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+// escapeHighlight escapes a highlight from ts_headline for HTML and turns its markers
+// into <mark> tags, so names and notes can't inject markup.
+func escapeHighlight(highlight string) string {
+	return highlightMarker.Replace(html.EscapeString(highlight))
+}
@@ -0,0 +1,23 @@
+package model
+
+import "testing"
+
+func TestEscapeHighlight(t *testing.T) {
+	tests := []struct {
+		name      string
+		highlight string
+		want      string
+	}{
+		{"match", "Weekly \x02groceries\x03", "Weekly <mark>groceries</mark>"},
+		{"typed mark tags", "<mark>\x02rent\x03</mark>", "&lt;mark&gt;<mark>rent</mark>&lt;/mark&gt;"},
+		{"markup", "<img src=x onerror=\"alert(1)\"> & \x02tea\x03", "&lt;img src=x onerror=&#34;alert(1)&#34;&gt; &amp; <mark>tea</mark>"},
+		{"no match", "Coffee", "Coffee"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeHighlight(tt.highlight); got != tt.want {
+				t.Errorf("escapeHighlight(%q) = %q, want %q", tt.highlight, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package model
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/shopspring/decimal"
+)
+
+// taxSectionPattern accepts sections of the Income Tax Act like 80C, 80CCD(1B) or 10(10D).
+var taxSectionPattern = regexp.MustCompile(`^[0-9]+[A-Z0-9()]*$`)
+
+type TaxCategoryTotal struct {
+	ID    uuid.UUID       `json:"id"`
+	Name  string          `json:"name"`
+	Total decimal.Decimal `json:"total"`
+}
+
+// TaxDeduction totals the investments of one tax section. Eligible is Invested limited
+// to Cap, which is nil for sections without a configured cap.
+type TaxDeduction struct {
+	Section    string             `json:"section"`
+	Invested   decimal.Decimal    `json:"invested"`
+	Cap        *decimal.Decimal   `json:"cap"`
+	Eligible   decimal.Decimal    `json:"eligible"`
+	Remaining  *decimal.Decimal   `json:"remaining"`
+	Categories []TaxCategoryTotal `json:"categories"`
+}
+
+type TaxIncome struct {
+	ID        uuid.UUID       `json:"id"`
+	Name      string          `json:"name"`
+	TaxExempt bool            `json:"tax_exempt"`
+	Total     decimal.Decimal `json:"total"`
+}
+
+type TaxSummary struct {
+	Period          string          `json:"period"`
+	From            string          `json:"from"`
+	To              string          `json:"to"`
+	Currency        string          `json:"currency"`
+	Deductions      []TaxDeduction  `json:"deductions"`
+	TotalDeductions decimal.Decimal `json:"total_deductions"`
+	Incomes         []TaxIncome     `json:"incomes"`
+	TaxableIncome   decimal.Decimal `json:"taxable_income"`
+	ExemptIncome    decimal.Decimal `json:"exempt_income"`
+}
+
+// NormalizeTaxSection upper-cases section and drops its spaces, so 80c and 80 C are 80C.
+func NormalizeTaxSection(section string) string {
+	return strings.ToUpper(strings.ReplaceAll(section, " ", ""))
+}
+
+// validateCategoryTax checks that tax sections are only set on Investment categories
+// and tax exemptions only on Income categories.
+func validateCategoryTax(category Category) error {
+	section := NormalizeTaxSection(category.TaxSection)
+	if section != "" {
+		if category.Type != CategoryTypeInvestment {
+			return NewValidationError("tax_section", FieldInvalid, "tax_section can only be set on Investment categories")
+		}
+		if !taxSectionPattern.MatchString(section) {
+			return NewValidationError("tax_section", FieldInvalid, "invalid tax_section: "+category.TaxSection+" (like 80C or 80CCD(1B))")
+		}
+	}
+	if category.TaxExempt && category.Type != CategoryTypeIncome {
+		return NewValidationError("tax_exempt", FieldInvalid, "tax_exempt can only be set on Income categories")
+	}
+	return nil
+}
+
+// taxSectionParam returns the normalized section to store, nil for none.
+func taxSectionParam(section string) *string {
+	section = NormalizeTaxSection(section)
+	if section == "" {
+		return nil
+	}
+	return &section
+}
+
+// GetTaxSummaryFromDB totals the investments per tax section against the deduction caps
+// and the taxable and exempt income of the user in period.
+func (s ReportService) GetTaxSummaryFromDB(ctx context.Context, userID uuid.UUID, period Period) (TaxSummary, error) {
+	rows, err := s.Queries.GetTaxTotals(ctx, repository.GetTaxTotalsParams{
+		UserID:   userID,
+		FromDate: pgtype.Date{Time: period.From, Valid: true},
+		ToDate:   pgtype.Date{Time: period.To, Valid: true},
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to get tax totals", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return TaxSummary{}, err
+	}
+
+	summary := BuildTaxSummary(ctx, rows, s.DeductionCaps)
+	summary.Period = period.Name
+	summary.From = period.From.Format(DateLayout)
+	summary.To = period.To.Format(DateLayout)
+	summary.Currency = userPreferences(ctx, s.Queries, userID).BaseCurrency
+	return summary, nil
+}
+
+// BuildTaxSummary totals the category rows. Every capped section is listed so the room
+// left under it shows, and sections are sorted by name.
+func BuildTaxSummary(ctx context.Context, rows []repository.GetTaxTotalsRow, caps map[string]decimal.Decimal) TaxSummary {
+	summary := TaxSummary{
+		Deductions:      []TaxDeduction{},
+		TotalDeductions: decimal.Zero,
+		Incomes:         []TaxIncome{},
+		TaxableIncome:   decimal.Zero,
+		ExemptIncome:    decimal.Zero,
+	}
+
+	sections := map[string]*TaxDeduction{}
+	section := func(name string) *TaxDeduction {
+		if deduction, ok := sections[name]; ok {
+			return deduction
+		}
+		deduction := &TaxDeduction{Section: name, Invested: decimal.Zero, Categories: []TaxCategoryTotal{}}
+		if limit, ok := caps[name]; ok {
+			deduction.Cap = &limit
+		}
+		sections[name] = deduction
+		return deduction
+	}
+	for name := range caps {
+		section(name)
+	}
+
+	for _, row := range rows {
+		total, err := numericToDecimal(row.Total)
+		if err != nil {
+			logger.Error(ctx, "failed to convert amount to decimal", map[string]interface{}{
+				"category_id": row.ID,
+				"error":       err,
+			})
+		}
+
+		switch row.Type {
+		case CategoryTypeInvestment:
+			if row.TaxSection == nil || total.IsZero() {
+				continue
+			}
+			deduction := section(*row.TaxSection)
+			deduction.Invested = deduction.Invested.Add(total)
+			deduction.Categories = append(deduction.Categories, TaxCategoryTotal{ID: row.ID, Name: row.Name, Total: total})
+		case CategoryTypeIncome:
+			if total.IsZero() {
+				continue
+			}
+			summary.Incomes = append(summary.Incomes, TaxIncome{ID: row.ID, Name: row.Name, TaxExempt: row.TaxExempt, Total: total})
+			if row.TaxExempt {
+				summary.ExemptIncome = summary.ExemptIncome.Add(total)
+			} else {
+				summary.TaxableIncome = summary.TaxableIncome.Add(total)
+			}
+		}
+	}
+
+	for _, deduction := range sections {
+		deduction.Eligible = deduction.Invested
+		if deduction.Cap != nil {
+			deduction.Eligible = decimal.Min(deduction.Invested, *deduction.Cap)
+			remaining := deduction.Cap.Sub(deduction.Eligible)
+			deduction.Remaining = &remaining
+		}
+		summary.TotalDeductions = summary.TotalDeductions.Add(deduction.Eligible)
+		summary.Deductions = append(summary.Deductions, *deduction)
+	}
+	sort.Slice(summary.Deductions, func(i, j int) bool {
+		return summary.Deductions[i].Section < summary.Deductions[j].Section
+	})
+
+	return summary
+}
@@ -0,0 +1,144 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+func taxRow(t *testing.T, name, categoryType, section string, exempt bool, total string) repository.GetTaxTotalsRow {
+	t.Helper()
+	row := repository.GetTaxTotalsRow{ID: uuid.New(), Name: name, Type: categoryType, TaxExempt: exempt}
+	if section != "" {
+		row.TaxSection = &section
+	}
+	if err := row.Total.Scan(total); err != nil {
+		t.Fatalf("invalid total %q: %v", total, err)
+	}
+	return row
+}
+
+// describeDeduction formats the amounts of a deduction, - for no cap.
+func describeDeduction(d TaxDeduction) string {
+	limit, remaining := "-", "-"
+	if d.Cap != nil {
+		limit = d.Cap.String()
+	}
+	if d.Remaining != nil {
+		remaining = d.Remaining.String()
+	}
+	return fmt.Sprintf("%s invested %s cap %s eligible %s remaining %s categories %d",
+		d.Section, d.Invested, limit, d.Eligible, remaining, len(d.Categories))
+}
+
+func TestBuildTaxSummary(t *testing.T) {
+	caps := map[string]decimal.Decimal{
+		"80C": decimal.NewFromInt(150000),
+		"80D": decimal.NewFromInt(25000),
+	}
+	const noInvestments = "80D invested 0 cap 25000 eligible 0 remaining 25000 categories 0"
+
+	tests := []struct {
+		name           string
+		rows           []repository.GetTaxTotalsRow
+		wantDeductions []string
+		wantTotal      string
+		wantTaxable    string
+		wantExempt     string
+		wantIncomes    int
+	}{
+		{
+			name: "under the cap",
+			rows: []repository.GetTaxTotalsRow{taxRow(t, "PPF", CategoryTypeInvestment, "80C", false, "100000")},
+			wantDeductions: []string{
+				"80C invested 100000 cap 150000 eligible 100000 remaining 50000 categories 1",
+				noInvestments,
+			},
+			wantTotal: "100000", wantTaxable: "0", wantExempt: "0",
+		},
+		{
+			name: "over the cap",
+			rows: []repository.GetTaxTotalsRow{
+				taxRow(t, "PPF", CategoryTypeInvestment, "80C", false, "120000"),
+				taxRow(t, "ELSS", CategoryTypeInvestment, "80C", false, "60000.50"),
+			},
+			wantDeductions: []string{
+				"80C invested 180000.5 cap 150000 eligible 150000 remaining 0 categories 2",
+				noInvestments,
+			},
+			wantTotal: "150000", wantTaxable: "0", wantExempt: "0",
+		},
+		{
+			name: "uncapped section",
+			rows: []repository.GetTaxTotalsRow{taxRow(t, "NPS", CategoryTypeInvestment, "80CCD(1B)", false, "50000")},
+			wantDeductions: []string{
+				"80C invested 0 cap 150000 eligible 0 remaining 150000 categories 0",
+				"80CCD(1B) invested 50000 cap - eligible 50000 remaining - categories 1",
+				noInvestments,
+			},
+			wantTotal: "50000", wantTaxable: "0", wantExempt: "0",
+		},
+		{
+			name: "capped sections without investments",
+			rows: []repository.GetTaxTotalsRow{
+				taxRow(t, "Stocks", CategoryTypeInvestment, "", false, "90000"),
+				taxRow(t, "PPF", CategoryTypeInvestment, "80C", false, "0"),
+			},
+			wantDeductions: []string{
+				"80C invested 0 cap 150000 eligible 0 remaining 150000 categories 0",
+				noInvestments,
+			},
+			wantTotal: "0", wantTaxable: "0", wantExempt: "0",
+		},
+		{
+			name: "exempt and taxable income",
+			rows: []repository.GetTaxTotalsRow{
+				taxRow(t, "Salary", CategoryTypeIncome, "", false, "1000000"),
+				taxRow(t, "Freelance", CategoryTypeIncome, "", false, "5000"),
+				taxRow(t, "PPF interest", CategoryTypeIncome, "", true, "8000"),
+				taxRow(t, "Gifts", CategoryTypeIncome, "", true, "0"),
+				taxRow(t, "Rent", CategoryTypeExpense, "", false, "240000"),
+			},
+			wantDeductions: []string{
+				"80C invested 0 cap 150000 eligible 0 remaining 150000 categories 0",
+				noInvestments,
+			},
+			wantTotal: "0", wantTaxable: "1005000", wantExempt: "8000", wantIncomes: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := BuildTaxSummary(context.Background(), tt.rows, caps)
+
+			var deductions []string
+			for _, deduction := range summary.Deductions {
+				deductions = append(deductions, describeDeduction(deduction))
+			}
+			if !reflect.DeepEqual(deductions, tt.wantDeductions) {
+				t.Errorf("deductions =\n%q\nwant\n%q", deductions, tt.wantDeductions)
+			}
+			if summary.TotalDeductions.String() != tt.wantTotal {
+				t.Errorf("total deductions = %s, want %s", summary.TotalDeductions, tt.wantTotal)
+			}
+			if summary.TaxableIncome.String() != tt.wantTaxable || summary.ExemptIncome.String() != tt.wantExempt {
+				t.Errorf("taxable, exempt income = %s, %s, want %s, %s",
+					summary.TaxableIncome, summary.ExemptIncome, tt.wantTaxable, tt.wantExempt)
+			}
+			if len(summary.Incomes) != tt.wantIncomes {
+				t.Errorf("got %d incomes, want %d: %+v", len(summary.Incomes), tt.wantIncomes, summary.Incomes)
+			}
+		})
+	}
+}
+
+func TestBuildTaxSummaryWithoutCaps(t *testing.T) {
+	summary := BuildTaxSummary(context.Background(), nil, nil)
+	if summary.Deductions == nil || summary.Incomes == nil || len(summary.Deductions)+len(summary.Incomes) != 0 {
+		t.Errorf("deductions, incomes = %#v, %#v, want empty lists", summary.Deductions, summary.Incomes)
+	}
+}
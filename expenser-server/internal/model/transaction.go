@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
 	"github.com/keertirajmalik/expenser/expenser-server/logger"
@@ -18,11 +19,11 @@ import (
 
 type InputTransaction struct {
 	ID       uuid.UUID       `json:"id"`
-	Name     string          `json:"name"`
-	Amount   decimal.Decimal `json:"amount"`
-	Category uuid.UUID       `json:"category"`
-	Date     string          `json:"date"`
-	Note     string          `json:"note"`
+	Name     string          `json:"name" validate:"required,max=100"`
+	Amount   decimal.Decimal `json:"amount" validate:"positive"`
+	Category uuid.UUID       `json:"category" validate:"required"`
+	Date     string          `json:"date" validate:"required,date,notfuture"`
+	Note     string          `json:"note" validate:"max=500"`
 	UserID   uuid.UUID       `json:"user_id"`
 }
 
@@ -38,12 +39,13 @@ type ResponseTransaction struct {
 
 type TransactionService struct {
 	Queries *repository.Queries
+	DB      *pgxpool.Pool
 }
 
 func (t TransactionService) GetTransactionsFromDB(ctx context.Context, userID uuid.UUID) ([]ResponseTransaction, error) {
 	dbTransactions, err := t.Queries.GetTransaction(ctx, userID)
 	if err != nil {
-		logger.Error("failed to get transactions: %v", map[string]interface{}{
+		logger.Error(ctx, "failed to get transactions: %v", map[string]interface{}{
 			"user_id": userID,
 			"error":   err,
 		})
@@ -58,7 +60,7 @@ func (t TransactionService) GetTransactionsFromDB(ctx context.Context, userID uu
 		}
 		date := ""
 		if transaction.Date.Valid {
-			date = transaction.Date.Time.Format("02/01/2006")
+			date = transaction.Date.Time.Format(DateLayout)
 		}
 
 		var money decimal.Decimal
@@ -72,7 +74,7 @@ func (t TransactionService) GetTransactionsFromDB(ctx context.Context, userID uu
 			var err error
 			money, err = decimal.NewFromString(numStr)
 			if err != nil {
-				logger.Error("failed to convert amount to decimal: %v", map[string]interface{}{
+				logger.Error(ctx, "failed to convert amount to decimal: %v", map[string]interface{}{
 					"amount": numStr,
 					"error":  err,
 				})
@@ -94,26 +96,34 @@ func (t TransactionService) GetTransactionsFromDB(ctx context.Context, userID uu
 }
 
 func (t TransactionService) AddTransactionToDB(ctx context.Context, transaction InputTransaction) (ResponseTransaction, error) {
-	parsedDate, err := time.Parse("02/01/2006", transaction.Date)
+	// Quick entries may leave the category out to use the default of the user.
+	if transaction.Category == uuid.Nil {
+		transaction.Category = defaultCategory(ctx, t.Queries, transaction.UserID, CategoryTypeExpense)
+	}
+	if err := ValidateAt(transaction, userNow(ctx, t.Queries, transaction.UserID)); err != nil {
+		return ResponseTransaction{}, err
+	}
+
+	parsedDate, err := time.Parse(DateLayout, transaction.Date)
 	if err != nil {
-		logger.Error("failed to parse date", map[string]interface{}{
+		logger.Error(ctx, "failed to parse date", map[string]interface{}{
 			"date":  transaction.Date,
 			"error": err,
 		})
 
-		return ResponseTransaction{}, fmt.Errorf("invalid date format: %s", transaction.Date)
+		return ResponseTransaction{}, NewValidationError("date", FieldInvalid, fmt.Sprintf("invalid date format: %s", transaction.Date))
 
 	}
 
 	money := &pgtype.Numeric{}
 	err = money.Scan(transaction.Amount.String())
 	if err != nil {
-		logger.Error("failed to convert amount to numeric: %v", map[string]interface{}{
+		logger.Error(ctx, "failed to convert amount to numeric: %v", map[string]interface{}{
 			"amount": transaction.Amount,
 			"error":  err,
 		})
 
-		return ResponseTransaction{}, fmt.Errorf("failed to convert amount type: %w", err)
+		return ResponseTransaction{}, NewValidationError("amount", FieldInvalid, fmt.Sprintf("invalid amount: %v", err))
 	}
 
 	err = t.validateTransactionCategory(ctx, transaction.Category, transaction.UserID)
@@ -135,15 +145,15 @@ func (t TransactionService) AddTransactionToDB(ctx context.Context, transaction
 	})
 
 	if err != nil {
-		logger.Error("failed to create transaction for user : %v", map[string]interface{}{
+		logger.Error(ctx, "failed to create transaction for user : %v", map[string]interface{}{
 			"user_id": transaction.UserID,
 			"error":   err,
 		})
 
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeForeignKeyViolation {
-			logger.Warn(fmt.Sprintf("foreign key violation while creating transaction %s: non-existent category", transaction.ID))
-			return ResponseTransaction{}, &database.ErrForeignKeyViolation{Message: "provide valid category"}
+			logger.Warn(ctx, fmt.Sprintf("foreign key violation while creating transaction %s: non-existent category", transaction.ID))
+			return ResponseTransaction{}, NewValidationError("category", FieldNotFound, "provide valid category")
 		}
 		return ResponseTransaction{}, fmt.Errorf("failed to create transaction: %w", err)
 	}
@@ -154,7 +164,7 @@ func (t TransactionService) AddTransactionToDB(ctx context.Context, transaction
 	}
 	date := ""
 	if dbTransaction.Date.Valid {
-		date = dbTransaction.Date.Time.Format("02/01/2006")
+		date = dbTransaction.Date.Time.Format(DateLayout)
 	}
 
 	var dbMoney decimal.Decimal
@@ -168,7 +178,7 @@ func (t TransactionService) AddTransactionToDB(ctx context.Context, transaction
 		var err error
 		dbMoney, err = decimal.NewFromString(numStr)
 		if err != nil {
-			logger.Error("failed to convert amount to decimal: %v", map[string]interface{}{
+			logger.Error(ctx, "failed to convert amount to decimal: %v", map[string]interface{}{
 				"amount": numStr,
 				"error":  err,
 			})
@@ -187,25 +197,29 @@ func (t TransactionService) AddTransactionToDB(ctx context.Context, transaction
 }
 
 func (t TransactionService) UpdateTransactionInDB(ctx context.Context, transaction InputTransaction) (ResponseTransaction, error) {
-	parsedDate, err := time.Parse("02/01/2006", transaction.Date)
+	if err := ValidateAt(transaction, userNow(ctx, t.Queries, transaction.UserID)); err != nil {
+		return ResponseTransaction{}, err
+	}
+
+	parsedDate, err := time.Parse(DateLayout, transaction.Date)
 	if err != nil {
-		logger.Error("failed to parse date", map[string]interface{}{
+		logger.Error(ctx, "failed to parse date", map[string]interface{}{
 			"transaction_id": transaction.ID,
 			"date":           transaction.Date,
 			"error":          err,
 		})
-		return ResponseTransaction{}, fmt.Errorf("invalid date format: %s", transaction.Date)
+		return ResponseTransaction{}, NewValidationError("date", FieldInvalid, fmt.Sprintf("invalid date format: %s", transaction.Date))
 	}
 
 	money := &pgtype.Numeric{}
 	err = money.Scan(transaction.Amount.String())
 	if err != nil {
-		logger.Error("failed to convert amount to numeric: %v", map[string]interface{}{
+		logger.Error(ctx, "failed to convert amount to numeric: %v", map[string]interface{}{
 			"transaction_id": transaction.ID,
 			"amount":         transaction.Amount,
 			"error":          err,
 		})
-		return ResponseTransaction{}, fmt.Errorf("failed to convert amount type: %w", err)
+		return ResponseTransaction{}, NewValidationError("amount", FieldInvalid, fmt.Sprintf("invalid amount: %v", err))
 	}
 
 	err = t.validateTransactionCategory(ctx, transaction.Category, transaction.UserID)
@@ -229,14 +243,14 @@ func (t TransactionService) UpdateTransactionInDB(ctx context.Context, transacti
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeForeignKeyViolation {
-			logger.Warn(fmt.Sprintf("foreign key violation while updating transaction %s: non-existent category", transaction.ID))
-			return ResponseTransaction{}, &database.ErrForeignKeyViolation{Message: "provide valid category"}
+			logger.Warn(ctx, fmt.Sprintf("foreign key violation while updating transaction %s: non-existent category", transaction.ID))
+			return ResponseTransaction{}, NewValidationError("category", FieldNotFound, "provide valid category")
 		}
 		if errors.Is(err, pgx.ErrNoRows) {
-			logger.Warn(fmt.Sprintf("transaction %s not found for user %s", transaction.ID, transaction.UserID))
-			return ResponseTransaction{}, errors.New("transaction not found")
+			logger.Warn(ctx, fmt.Sprintf("transaction %s not found for user %s", transaction.ID, transaction.UserID))
+			return ResponseTransaction{}, NewNotFoundError("transaction")
 		}
-		logger.Error("failed to update transaction: %v", map[string]interface{}{
+		logger.Error(ctx, "failed to update transaction: %v", map[string]interface{}{
 			"user_id":        transaction.UserID,
 			"transaction_id": transaction.ID,
 			"error":          err,
@@ -251,7 +265,7 @@ func (t TransactionService) UpdateTransactionInDB(ctx context.Context, transacti
 	}
 	date := ""
 	if dbTransaction.Date.Valid {
-		date = dbTransaction.Date.Time.Format("02/01/2006")
+		date = dbTransaction.Date.Time.Format(DateLayout)
 	}
 
 	var dbMoney decimal.Decimal
@@ -265,7 +279,7 @@ func (t TransactionService) UpdateTransactionInDB(ctx context.Context, transacti
 		var err error
 		dbMoney, err = decimal.NewFromString(numStr)
 		if err != nil {
-			logger.Error("failed to convert amount to decimal: %v", map[string]interface{}{
+			logger.Error(ctx, "failed to convert amount to decimal: %v", map[string]interface{}{
 				"error": err,
 			})
 		}
@@ -289,10 +303,10 @@ func (t TransactionService) DeleteTransactionFromDB(ctx context.Context, id, use
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			logger.Warn(fmt.Sprintf("transaction %s not found for user %s", id, userID))
-			return errors.New("transaction not found")
+			logger.Warn(ctx, fmt.Sprintf("transaction %s not found for user %s", id, userID))
+			return NewNotFoundError("transaction")
 		}
-		logger.Error("failed to delete transaction", map[string]interface{}{
+		logger.Error(ctx, "failed to delete transaction", map[string]interface{}{
 			"transaction_id": id,
 			"user_id":        userID,
 			"error":          err,
@@ -302,32 +316,67 @@ func (t TransactionService) DeleteTransactionFromDB(ctx context.Context, id, use
 
 	rowAffected := result.RowsAffected()
 	if rowAffected == 0 {
-		logger.Warn(fmt.Sprintf("transaction %s not found for user %s", id, userID))
-		return errors.New("transaction not found")
+		logger.Warn(ctx, fmt.Sprintf("transaction %s not found for user %s", id, userID))
+		return NewNotFoundError("transaction")
 	}
 
 	return nil
 }
 
+// ApplyTransactionBatch validates every operation and then applies them atomically.
+// Either all operations are persisted or none are.
+func (t TransactionService) ApplyTransactionBatch(ctx context.Context, userID uuid.UUID, operations []BatchOperation) ([]BatchResult, error) {
+	err := validateBatch(operations, userNow(ctx, t.Queries, userID), func(categoryID uuid.UUID) error {
+		return t.validateTransactionCategory(ctx, categoryID, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return runBatch(ctx, t.DB, t.Queries, operations, func(queries *repository.Queries, op BatchOperation) (uuid.UUID, any, error) {
+		txService := TransactionService{Queries: queries}
+		transaction := InputTransaction{
+			ID:       op.ID,
+			Name:     op.Name,
+			Amount:   op.Amount,
+			Category: op.Category,
+			Date:     op.Date,
+			Note:     op.Note,
+			UserID:   userID,
+		}
+
+		switch op.Op {
+		case BatchOpCreate:
+			created, err := txService.AddTransactionToDB(ctx, transaction)
+			return created.ID, created, err
+		case BatchOpUpdate:
+			updated, err := txService.UpdateTransactionInDB(ctx, transaction)
+			return updated.ID, updated, err
+		default:
+			return op.ID, nil, txService.DeleteTransactionFromDB(ctx, op.ID, userID)
+		}
+	})
+}
+
 func (t TransactionService) validateTransactionCategory(ctx context.Context, categoryID, userID uuid.UUID) error {
 	dbCategory, err := t.Queries.GetCategoryById(ctx, repository.GetCategoryByIdParams{
 		ID:     categoryID,
 		UserID: userID,
 	})
 	if err != nil {
-		logger.Error("Category not found", map[string]interface{}{
+		logger.Error(ctx, "Category not found", map[string]interface{}{
 			"category": categoryID,
 			"user_id":  userID,
 			"error":    err,
 		})
-		return fmt.Errorf("category type not found")
+		return NewValidationError("category", FieldNotFound, "category not found")
 	}
 	if dbCategory.Type != CategoryTypeExpense {
-		logger.Error("Category type should be Expense", map[string]interface{}{
+		logger.Error(ctx, "Category type should be Expense", map[string]interface{}{
 			"category_name": dbCategory.Name,
 			"category_type": dbCategory.Type,
 		})
-		return fmt.Errorf("category type should be expense")
+		return NewValidationError("category", FieldInvalid, "category type should be expense")
 	}
 	return nil
 }
@@ -0,0 +1,256 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+)
+
+const (
+	// TOTPIssuer names the account in authenticator apps.
+	TOTPIssuer = "Expenser"
+	// MaxTwoFactorAttempts wrong codes in a row lock the second factor for
+	// TwoFactorLockout, so the million TOTP codes can't be guessed.
+	MaxTwoFactorAttempts = 5
+	TwoFactorLockout     = 15 * time.Minute
+	// TwoFactorChallengeTTL is how long the second factor may be entered after the
+	// password.
+	TwoFactorChallengeTTL = 5 * time.Minute
+)
+
+// TOTPEnrollment is the secret to add to an authenticator app. Two-factor
+// authentication is only enabled once a code generated from it is confirmed.
+type TOTPEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorService struct {
+	Queries *repository.Queries
+	DB      *pgxpool.Pool
+}
+
+// IsTwoFactorEnabled reports whether the user has confirmed a TOTP enrollment.
+func (s TwoFactorService) IsTwoFactorEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	totp, err := s.Queries.GetUserTotp(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "failed to get totp", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return false, err
+	}
+	return totp.EnabledAt.Valid, nil
+}
+
+// EnrollTOTP stores a new pending TOTP secret for the user, replacing any earlier
+// enrollment that was never confirmed.
+func (s TwoFactorService) EnrollTOTP(ctx context.Context, userID uuid.UUID, account string) (TOTPEnrollment, error) {
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		logger.Error(ctx, "failed to generate totp secret", map[string]interface{}{"error": err})
+		return TOTPEnrollment{}, err
+	}
+
+	_, err = s.Queries.UpsertUserTotp(ctx, repository.UpsertUserTotpParams{
+		UserID: userID,
+		Secret: secret,
+	})
+	// The upsert leaves enabled enrollments alone and then returns no row.
+	if errors.Is(err, pgx.ErrNoRows) {
+		return TOTPEnrollment{}, NewConflictError(CodeConflict, "two-factor authentication is already enabled")
+	}
+	if err != nil {
+		logger.Error(ctx, "failed to store totp secret", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return TOTPEnrollment{}, err
+	}
+
+	return TOTPEnrollment{
+		Secret:     secret,
+		OTPAuthURI: auth.TOTPURI(TOTPIssuer, account, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication once code matches the pending secret and
+// returns the recovery codes. Only their hashes are stored, so this is the only time
+// they can be shown.
+func (s TwoFactorService) ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	totp, err := s.Queries.GetUserTotp(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, NewNotFoundError("two_factor_enrollment")
+	}
+	if err != nil {
+		logger.Error(ctx, "failed to get totp", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return nil, err
+	}
+	if totp.EnabledAt.Valid {
+		return nil, NewConflictError(CodeConflict, "two-factor authentication is already enabled")
+	}
+
+	step, ok := auth.ValidateTOTP(totp.Secret, code, time.Now(), totp.LastUsedStep)
+	if !ok {
+		return nil, NewValidationError("code", FieldInvalid, "code doesn't match the secret")
+	}
+
+	codes, err := auth.GenerateRecoveryCodes(auth.RecoveryCodeCount)
+	if err != nil {
+		logger.Error(ctx, "failed to generate recovery codes", map[string]interface{}{"error": err})
+		return nil, err
+	}
+
+	err = database.WithTx(ctx, s.DB, func(tx pgx.Tx) error {
+		queries := s.Queries.WithTx(tx)
+
+		result, err := queries.EnableUserTotp(ctx, repository.EnableUserTotpParams{
+			UserID:       userID,
+			LastUsedStep: step,
+		})
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return NewConflictError(CodeConflict, "two-factor authentication is already enabled")
+		}
+
+		if err := queries.DeleteRecoveryCodes(ctx, userID); err != nil {
+			return err
+		}
+		for _, code := range codes {
+			err := queries.CreateRecoveryCode(ctx, repository.CreateRecoveryCodeParams{
+				ID:       uuid.New(),
+				UserID:   userID,
+				CodeHash: auth.HashRecoveryCode(code),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to enable two-factor authentication", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// VerifySecondFactor checks a TOTP code or an unused recovery code, which is then spent.
+// Too many wrong codes lock the second factor for a while.
+func (s TwoFactorService) VerifySecondFactor(ctx context.Context, userID uuid.UUID, code string) error {
+	totp, err := s.Queries.GetUserTotp(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !totp.EnabledAt.Valid) {
+		return NewUnauthorizedError("two-factor authentication isn't enabled")
+	}
+	if err != nil {
+		logger.Error(ctx, "failed to get totp", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return err
+	}
+
+	now := time.Now()
+	if totp.LockedUntil.Valid && totp.LockedUntil.Time.After(now) {
+		logger.Warn(ctx, "second factor locked", map[string]interface{}{"user_id": userID})
+		return NewUnauthorizedError("too many invalid codes, try again later")
+	}
+
+	step, ok := auth.ValidateTOTP(totp.Secret, code, now, totp.LastUsedStep)
+	if !ok {
+		result, err := s.Queries.UseRecoveryCode(ctx, repository.UseRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: auth.HashRecoveryCode(code),
+		})
+		if err != nil {
+			logger.Error(ctx, "failed to use recovery code", map[string]interface{}{
+				"user_id": userID,
+				"error":   err,
+			})
+			return err
+		}
+		ok = result.RowsAffected() == 1
+		step = totp.LastUsedStep
+		if ok {
+			logger.Info(ctx, "recovery code used", map[string]interface{}{"user_id": userID})
+		}
+	}
+
+	if !ok {
+		_, err := s.Queries.RecordTotpFailure(ctx, repository.RecordTotpFailureParams{
+			MaxAttempts: MaxTwoFactorAttempts,
+			LockedUntil: pgtype.Timestamptz{Time: now.Add(TwoFactorLockout), Valid: true},
+			UserID:      userID,
+		})
+		if err != nil {
+			logger.Error(ctx, "failed to record invalid code", map[string]interface{}{
+				"user_id": userID,
+				"error":   err,
+			})
+			return err
+		}
+		return NewUnauthorizedError("invalid code")
+	}
+
+	err = s.Queries.RecordTotpSuccess(ctx, repository.RecordTotpSuccessParams{
+		UserID:       userID,
+		LastUsedStep: step,
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to record used code", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return err
+	}
+	return nil
+}
+
+// DisableTOTP turns two-factor authentication off after checking code, dropping the
+// secret and the recovery codes.
+func (s TwoFactorService) DisableTOTP(ctx context.Context, userID uuid.UUID, code string) error {
+	if err := s.VerifySecondFactor(ctx, userID, code); err != nil {
+		return err
+	}
+
+	err := database.WithTx(ctx, s.DB, func(tx pgx.Tx) error {
+		queries := s.Queries.WithTx(tx)
+
+		if _, err := queries.DeleteUserTotp(ctx, userID); err != nil {
+			return err
+		}
+		return queries.DeleteRecoveryCodes(ctx, userID)
+	})
+	if err != nil {
+		logger.Error(ctx, "failed to disable two-factor authentication", map[string]interface{}{
+			"user_id": userID,
+			"error":   err,
+		})
+		return err
+	}
+
+	logger.Info(ctx, fmt.Sprintf("two-factor authentication disabled for user %s", userID))
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
 	"github.com/keertirajmalik/expenser/expenser-server/logger"
@@ -18,17 +19,25 @@ type User struct {
 	Name           string    `json:"name"`
 	Username       string    `json:"username"`
 	HashedPassword string    `json:"-"`
-	Image          string    `json:"image"`
+	// PasswordSet is false for users created through single sign-on, whose random
+	// password nobody knows.
+	PasswordSet bool   `json:"-"`
+	Image       string `json:"image"`
+	Locale      string `json:"locale"`
+	DateFormat  string `json:"date_format"`
+	Timezone    string `json:"timezone"`
+	Role        string `json:"role"`
 }
 
 type UserService struct {
-    Queries *repository.Queries
+	Queries *repository.Queries
+	DB      *pgxpool.Pool
 }
 
 func (s UserService) GetUsersFromDB(ctx context.Context) ([]User, error) {
 	dbUsers, err := s.Queries.GetUser(ctx)
 	if err != nil {
-		logger.Error("failed to get user from database", map[string]interface{}{
+		logger.Error(ctx, "failed to get user from database", map[string]interface{}{
 			"error": err,
 		})
 		return []User{}, err
@@ -37,21 +46,35 @@ func (s UserService) GetUsersFromDB(ctx context.Context) ([]User, error) {
 	return convertDBUserToUser(dbUsers), nil
 }
 
+// AddUserToDB creates the user and seeds the default category templates atomically.
 func (s UserService) AddUserToDB(ctx context.Context, user User) (User, error) {
-	dbUser, err := s.Queries.CreateUser(ctx, repository.CreateUserParams{
-		ID:             user.ID,
-		Name:           user.Name,
-		Username:       user.Username,
-		HashedPassword: user.HashedPassword,
+	var dbUser repository.User
+	err := database.WithTx(ctx, s.DB, func(tx pgx.Tx) error {
+		queries := s.Queries.WithTx(tx)
+
+		var err error
+		dbUser, err = queries.CreateUser(ctx, repository.CreateUserParams{
+			ID:             user.ID,
+			Name:           user.Name,
+			Username:       user.Username,
+			HashedPassword: user.HashedPassword,
+			PasswordSet:    true,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = applyCategoryTemplates(ctx, queries, dbUser.ID, DefaultCategoryTemplates)
+		return err
 	})
 
 	if err != nil {
-		logger.Error("failed to create user in database", map[string]interface{}{
+		logger.Error(ctx, "failed to create user in database", map[string]interface{}{
 			"error": err,
 		})
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeUniqueViolation {
-			return User{}, &database.ErrDuplicateData{Column: user.Username}
+			return User{}, NewConflictError(CodeDuplicate, fmt.Sprintf("%s already exist", user.Username))
 		}
 		return User{}, err
 	}
@@ -65,13 +88,14 @@ func (s UserService) GetUserByUsernameFromDB(ctx context.Context, username strin
 	dbUser, err := s.Queries.GetUserByUsername(ctx, username)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			logger.Error("User not found", map[string]interface{}{"username": username, "error": err})
-			return User{}, fmt.Errorf("user not found")
+			logger.Error(ctx, "User not found", map[string]interface{}{"username": username, "error": err})
+			return User{}, NewNotFoundError("user")
 		}
-		logger.Error("Failed to get user from DB", map[string]interface{}{
+		logger.Error(ctx, "Failed to get user from DB", map[string]interface{}{
 			"username": username,
 			"error":    err,
 		})
+		return User{}, err
 	}
 	user := convertDBUserToUser([]repository.User{dbUser})
 	return user[0], nil
@@ -81,13 +105,14 @@ func (s UserService) GetUserByUserIdFromDB(ctx context.Context, userId uuid.UUID
 	dbUser, err := s.Queries.GetUserById(ctx, userId)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			logger.Error("User not found", map[string]interface{}{"userId": userId})
-			return User{}, fmt.Errorf("user not found")
+			logger.Error(ctx, "User not found", map[string]interface{}{"userId": userId})
+			return User{}, NewNotFoundError("user")
 		}
-		logger.Error("Failed to get user from DB", map[string]interface{}{
+		logger.Error(ctx, "Failed to get user from DB", map[string]interface{}{
 			"userId": userId,
 			"error":  err,
 		})
+		return User{}, err
 	}
 
 	user := convertDBUserToUser([]repository.User{dbUser})
@@ -107,28 +132,54 @@ func convertDBUserToUser(dbUsers []repository.User) []User {
 			Name:           user.Name,
 			Username:       user.Username,
 			HashedPassword: user.HashedPassword,
+			PasswordSet:    user.PasswordSet,
 			Image:          image,
+			Locale:         user.Locale,
+			DateFormat:     user.DateFormat,
+			Timezone:       user.Timezone,
+			Role:           user.Role,
 		})
 	}
 
 	return users
 }
 
+// UpdateUserInDB updates the profile of the user. Empty locale, date format and
+// timezone values keep the current preference.
 func (s UserService) UpdateUserInDB(ctx context.Context, user User) (User, error) {
-	dbUser, err := s.Queries.UpdateUser(ctx, repository.UpdateUserParams{
-		ID:    user.ID,
-		Name:  user.Name,
-		Image: &user.Image,
+	var dbUser repository.User
+	err := database.WithTx(ctx, s.DB, func(tx pgx.Tx) error {
+		queries := s.Queries.WithTx(tx)
+
+		var err error
+		dbUser, err = queries.UpdateUser(ctx, repository.UpdateUserParams{
+			ID:    user.ID,
+			Name:  user.Name,
+			Image: &user.Image,
+		})
+		if err != nil {
+			return err
+		}
+
+		if user.Locale == "" && user.DateFormat == "" && user.Timezone == "" {
+			return nil
+		}
+		params := updatePreferencesParams(dbUser)
+		params.Locale = valueOr(user.Locale, params.Locale)
+		params.DateFormat = valueOr(user.DateFormat, params.DateFormat)
+		params.Timezone = valueOr(user.Timezone, params.Timezone)
+		dbUser, err = queries.UpdateUserPreferences(ctx, params)
+		return err
 	})
 
 	if err != nil {
-		logger.Error("failed to update user in database", map[string]interface{}{
+		logger.Error(ctx, "failed to update user in database", map[string]interface{}{
 			"user_id": user.ID,
 			"error":   err,
 		})
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == database.ErrCodeUniqueViolation {
-			return User{}, &database.ErrDuplicateData{Column: user.Username}
+			return User{}, NewConflictError(CodeDuplicate, fmt.Sprintf("%s already exist", user.Username))
 		}
 		return User{}, err
 	}
@@ -137,3 +188,10 @@ func (s UserService) UpdateUserInDB(ctx context.Context, user User) (User, error
 
 	return users[0], nil
 }
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,191 @@
+package model
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	decimalType = reflect.TypeOf(decimal.Decimal{})
+	uuidType    = reflect.TypeOf(uuid.UUID{})
+)
+
+// Validate checks the exported fields of the struct v against the rules in their
+// `validate` tags and returns a ValidationError listing every invalid field, or nil.
+// Fields are reported under their json name. The comma separated rules are:
+//
+//	required   not blank, not nil, not the zero UUID and not an empty slice
+//	max=N      at most N characters, or N elements for slices
+//	positive   a decimal greater than zero
+//	date       a string in DateLayout
+//	notfuture  a date that isn't after today, see ValidateAt
+//	oneof=a|b  one of the listed values
+//	locale     a language tag like en-IN
+//	timezone   an IANA time zone name like Asia/Kolkata
+//	dateformat one of the DateFormats like DD/MM/YYYY
+//	currency   an ISO 4217 currency code like INR
+//
+// Apart from required, rules are skipped for empty values so optional fields only
+// get checked when they are set.
+func Validate(v any) error {
+	return ValidateAt(v, time.Now())
+}
+
+// ValidateAt is Validate with notfuture dates checked against the day of now. Pass
+// the time in the user's timezone so their today is accepted wherever they are.
+func ValidateAt(v any, now time.Time) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("model.Validate: expected a struct, got %s", value.Kind()))
+	}
+
+	validation := &ValidationError{}
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		rules, ok := field.Tag.Lookup("validate")
+		if !ok || !field.IsExported() {
+			continue
+		}
+		if fieldErr := validateField(jsonName(field), value.Field(i), rules, now); fieldErr != nil {
+			validation.Fields = append(validation.Fields, *fieldErr)
+		}
+	}
+
+	if len(validation.Fields) > 0 {
+		return validation
+	}
+	return nil
+}
+
+// validateField returns the error of the first rule the value breaks.
+func validateField(name string, value reflect.Value, rules string, now time.Time) *FieldError {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			if hasRule(rules, "required") {
+				return &FieldError{Field: name, Code: FieldRequired, Message: fmt.Sprintf("%s is required", name)}
+			}
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if isEmpty(value) {
+		if hasRule(rules, "required") {
+			return &FieldError{Field: name, Code: FieldRequired, Message: fmt.Sprintf("%s is required", name)}
+		}
+		return nil
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		rule, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch rule {
+		case "required", "":
+		case "max":
+			limit, err := strconv.Atoi(arg)
+			if err != nil {
+				panic(fmt.Sprintf("model.Validate: invalid max %q on %s", arg, name))
+			}
+			if length(value) > limit {
+				unit := "characters"
+				if value.Kind() == reflect.Slice {
+					unit = "items"
+				}
+				return &FieldError{Field: name, Code: FieldTooLong, Message: fmt.Sprintf("%s must be at most %d %s", name, limit, unit)}
+			}
+		case "positive":
+			amount, ok := value.Interface().(decimal.Decimal)
+			if !ok || !amount.IsPositive() {
+				return &FieldError{Field: name, Code: FieldInvalid, Message: fmt.Sprintf("%s must be greater than zero", name)}
+			}
+		case "date":
+			if _, err := time.Parse(DateLayout, value.String()); err != nil {
+				return &FieldError{Field: name, Code: FieldInvalid, Message: fmt.Sprintf("invalid date format: %s", value.String())}
+			}
+		case "notfuture":
+			date, err := time.Parse(DateLayout, value.String())
+			if err == nil && isFutureDate(date, now) {
+				return &FieldError{Field: name, Code: FieldInvalid, Message: fmt.Sprintf("%s can't be in the future", name)}
+			}
+		case "oneof":
+			options := strings.Split(arg, "|")
+			if !slices.Contains(options, value.String()) {
+				return &FieldError{Field: name, Code: FieldInvalid, Message: fmt.Sprintf("invalid %s: %q (must be %s)", name, value.String(), strings.Join(options, ", "))}
+			}
+		case "locale":
+			if !validLocale(value.String()) {
+				return &FieldError{Field: name, Code: FieldInvalid, Message: fmt.Sprintf("invalid %s: %q", name, value.String())}
+			}
+		case "dateformat":
+			if _, ok := DateFormats[value.String()]; !ok {
+				formats := slices.Sorted(maps.Keys(DateFormats))
+				return &FieldError{Field: name, Code: FieldInvalid, Message: fmt.Sprintf("invalid %s: %q (must be %s)", name, value.String(), strings.Join(formats, ", "))}
+			}
+		case "currency":
+			if !validCurrency(value.String()) {
+				return &FieldError{Field: name, Code: FieldInvalid, Message: fmt.Sprintf("invalid %s: %q (must be a currency code like INR)", name, value.String())}
+			}
+		case "timezone":
+			if !validTimezone(value.String()) {
+				return &FieldError{Field: name, Code: FieldInvalid, Message: fmt.Sprintf("invalid %s: %q", name, value.String())}
+			}
+		default:
+			panic(fmt.Sprintf("model.Validate: unknown rule %q on %s", rule, name))
+		}
+	}
+
+	return nil
+}
+
+// isFutureDate reports whether date lies after the day of now in the location of now.
+func isFutureDate(date, now time.Time) bool {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return date.After(today)
+}
+
+func isEmpty(value reflect.Value) bool {
+	switch {
+	case value.Type() == uuidType:
+		return value.Interface().(uuid.UUID) == uuid.Nil
+	case value.Type() == decimalType:
+		return false
+	case value.Kind() == reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case value.Kind() == reflect.Slice, value.Kind() == reflect.Map:
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+func length(value reflect.Value) int {
+	if value.Kind() == reflect.String {
+		return utf8.RuneCountInString(value.String())
+	}
+	return value.Len()
+}
+
+func hasRule(rules, name string) bool {
+	for _, rule := range strings.Split(rules, ",") {
+		if strings.TrimSpace(rule) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestValidateAtNotFuture(t *testing.T) {
+	kolkata := time.FixedZone("IST", 5*60*60+30*60)
+	losAngeles := time.FixedZone("PDT", -7*60*60)
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		date    string
+		wantErr bool
+	}{
+		{"today ahead of UTC", time.Date(2025, 3, 15, 2, 0, 0, 0, kolkata), "2025-03-15", false},
+		{"tomorrow ahead of UTC", time.Date(2025, 3, 15, 2, 0, 0, 0, kolkata), "2025-03-16", true},
+		{"today behind UTC", time.Date(2025, 3, 14, 20, 0, 0, 0, losAngeles), "2025-03-14", false},
+		{"UTC today behind UTC", time.Date(2025, 3, 14, 20, 0, 0, 0, losAngeles), "2025-03-15", true},
+		{"yesterday", time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC), "2025-03-14", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAt(batchPayload{Name: "Coffee", Amount: decimal.NewFromInt(10), Category: groceries, Date: tt.date}, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAt(%s at %s) = %v, want error %v", tt.date, tt.now, err, tt.wantErr)
+			}
+		})
+	}
+}
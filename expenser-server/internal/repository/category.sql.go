@@ -15,15 +15,18 @@ import (
 
 const createCategory = `-- name: CreateCategory :one
 WITH inserted AS (
-    INSERT INTO categories(id, name,type, description, user_id)
-    VALUES ($1, $2, $3, $4, $5)
-    RETURNING id, name, description, user_id, created_at, updated_at, type
+    INSERT INTO categories(id, name,type, description, user_id, parent_id, tax_section, tax_exempt)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    RETURNING id, name, description, user_id, created_at, updated_at, type, parent_id, tax_section, tax_exempt
 )
 SELECT
     inserted.id,
     inserted.name,
     inserted.description,
     inserted.type,
+    inserted.parent_id,
+    inserted.tax_section,
+    inserted.tax_exempt,
     users.name AS user,
     inserted.created_at,
     inserted.updated_at
@@ -32,11 +35,14 @@ INNER JOIN users ON inserted.user_id = users.id
 `
 
 type CreateCategoryParams struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Type        string    `json:"type"`
-	Description *string   `json:"description"`
-	UserID      uuid.UUID `json:"user_id"`
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	Description *string    `json:"description"`
+	UserID      uuid.UUID  `json:"user_id"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	TaxSection  *string    `json:"tax_section"`
+	TaxExempt   bool       `json:"tax_exempt"`
 }
 
 type CreateCategoryRow struct {
@@ -44,6 +50,9 @@ type CreateCategoryRow struct {
 	Name        string             `json:"name"`
 	Description *string            `json:"description"`
 	Type        string             `json:"type"`
+	ParentID    *uuid.UUID         `json:"parent_id"`
+	TaxSection  *string            `json:"tax_section"`
+	TaxExempt   bool               `json:"tax_exempt"`
 	User        string             `json:"user"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
@@ -56,6 +65,9 @@ func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams)
 		arg.Type,
 		arg.Description,
 		arg.UserID,
+		arg.ParentID,
+		arg.TaxSection,
+		arg.TaxExempt,
 	)
 	var i CreateCategoryRow
 	err := row.Scan(
@@ -63,6 +75,9 @@ func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams)
 		&i.Name,
 		&i.Description,
 		&i.Type,
+		&i.ParentID,
+		&i.TaxSection,
+		&i.TaxExempt,
 		&i.User,
 		&i.CreatedAt,
 		&i.UpdatedAt,
@@ -89,6 +104,9 @@ SELECT
     categories.name,
     categories.description,
     categories.type,
+    categories.parent_id,
+    categories.tax_section,
+    categories.tax_exempt,
     users.name AS user,
     categories.created_at,
     categories.updated_at
@@ -103,6 +121,9 @@ type GetCategoryRow struct {
 	Name        string             `json:"name"`
 	Description *string            `json:"description"`
 	Type        string             `json:"type"`
+	ParentID    *uuid.UUID         `json:"parent_id"`
+	TaxSection  *string            `json:"tax_section"`
+	TaxExempt   bool               `json:"tax_exempt"`
 	User        string             `json:"user"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
@@ -122,6 +143,9 @@ func (q *Queries) GetCategory(ctx context.Context, userID uuid.UUID) ([]GetCateg
 			&i.Name,
 			&i.Description,
 			&i.Type,
+			&i.ParentID,
+			&i.TaxSection,
+			&i.TaxExempt,
 			&i.User,
 			&i.CreatedAt,
 			&i.UpdatedAt,
@@ -142,6 +166,9 @@ SELECT
     categories.name,
     categories.description,
     categories.type,
+    categories.parent_id,
+    categories.tax_section,
+    categories.tax_exempt,
     users.name AS user,
     categories.created_at,
     categories.updated_at
@@ -161,6 +188,9 @@ type GetCategoryByIdRow struct {
 	Name        string             `json:"name"`
 	Description *string            `json:"description"`
 	Type        string             `json:"type"`
+	ParentID    *uuid.UUID         `json:"parent_id"`
+	TaxSection  *string            `json:"tax_section"`
+	TaxExempt   bool               `json:"tax_exempt"`
 	User        string             `json:"user"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
@@ -174,6 +204,9 @@ func (q *Queries) GetCategoryById(ctx context.Context, arg GetCategoryByIdParams
 		&i.Name,
 		&i.Description,
 		&i.Type,
+		&i.ParentID,
+		&i.TaxSection,
+		&i.TaxExempt,
 		&i.User,
 		&i.CreatedAt,
 		&i.UpdatedAt,
@@ -181,20 +214,111 @@ func (q *Queries) GetCategoryById(ctx context.Context, arg GetCategoryByIdParams
 	return i, err
 }
 
+const getCategoryTotals = `-- name: GetCategoryTotals :many
+SELECT
+    categories.id,
+    categories.name,
+    categories.type,
+    categories.parent_id,
+    COALESCE(totals.total, 0)::numeric AS total
+FROM categories
+LEFT JOIN (
+    SELECT entries.category, SUM(entries.amount) AS total
+    FROM (
+        SELECT transactions.category, transactions.amount
+        FROM transactions
+        WHERE transactions.user_id = $1 AND transactions."date" BETWEEN $2 AND $3
+        UNION ALL
+        SELECT incomes.category, incomes.amount
+        FROM incomes
+        WHERE incomes.user_id = $1 AND incomes."date" BETWEEN $2 AND $3
+        UNION ALL
+        SELECT investments.category, investments.amount
+        FROM investments
+        WHERE investments.user_id = $1 AND investments."date" BETWEEN $2 AND $3
+    ) entries
+    GROUP BY entries.category
+) totals ON totals.category = categories.id
+WHERE categories.user_id = $1
+ORDER BY categories.name
+`
+
+type GetCategoryTotalsParams struct {
+	UserID   uuid.UUID   `json:"user_id"`
+	FromDate pgtype.Date `json:"from_date"`
+	ToDate   pgtype.Date `json:"to_date"`
+}
+
+type GetCategoryTotalsRow struct {
+	ID       uuid.UUID      `json:"id"`
+	Name     string         `json:"name"`
+	Type     string         `json:"type"`
+	ParentID *uuid.UUID     `json:"parent_id"`
+	Total    pgtype.Numeric `json:"total"`
+}
+
+func (q *Queries) GetCategoryTotals(ctx context.Context, arg GetCategoryTotalsParams) ([]GetCategoryTotalsRow, error) {
+	rows, err := q.db.Query(ctx, getCategoryTotals, arg.UserID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCategoryTotalsRow
+	for rows.Next() {
+		var i GetCategoryTotalsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Type,
+			&i.ParentID,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reassignCategoryParent = `-- name: ReassignCategoryParent :execresult
+UPDATE categories
+SET parent_id = $1
+WHERE parent_id = $2 AND user_id = $3
+`
+
+type ReassignCategoryParentParams struct {
+	TargetParent *uuid.UUID `json:"target_parent"`
+	SourceParent *uuid.UUID `json:"source_parent"`
+	UserID       uuid.UUID  `json:"user_id"`
+}
+
+func (q *Queries) ReassignCategoryParent(ctx context.Context, arg ReassignCategoryParentParams) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, reassignCategoryParent, arg.TargetParent, arg.SourceParent, arg.UserID)
+}
+
 const updateCategory = `-- name: UpdateCategory :one
 WITH updated AS (
     UPDATE categories
     SET name = $2,
     description = $3,
-    type = $4
+    type = $4,
+    parent_id = $6,
+    tax_section = $7,
+    tax_exempt = $8
     WHERE categories.id = $1 And categories.user_id=$5
-    RETURNING id, name, description, user_id, created_at, updated_at, type
+    RETURNING id, name, description, user_id, created_at, updated_at, type, parent_id, tax_section, tax_exempt
 )
 SELECT
     updated.id,
     updated.name,
     updated.description,
     updated.type,
+    updated.parent_id,
+    updated.tax_section,
+    updated.tax_exempt,
     users.name AS user,
     updated.created_at,
     updated.updated_at
@@ -203,11 +327,14 @@ INNER JOIN users ON updated.user_id = users.id
 `
 
 type UpdateCategoryParams struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description *string   `json:"description"`
-	Type        string    `json:"type"`
-	UserID      uuid.UUID `json:"user_id"`
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	Type        string     `json:"type"`
+	UserID      uuid.UUID  `json:"user_id"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	TaxSection  *string    `json:"tax_section"`
+	TaxExempt   bool       `json:"tax_exempt"`
 }
 
 type UpdateCategoryRow struct {
@@ -215,6 +342,9 @@ type UpdateCategoryRow struct {
 	Name        string             `json:"name"`
 	Description *string            `json:"description"`
 	Type        string             `json:"type"`
+	ParentID    *uuid.UUID         `json:"parent_id"`
+	TaxSection  *string            `json:"tax_section"`
+	TaxExempt   bool               `json:"tax_exempt"`
 	User        string             `json:"user"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
@@ -227,6 +357,9 @@ func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams)
 		arg.Description,
 		arg.Type,
 		arg.UserID,
+		arg.ParentID,
+		arg.TaxSection,
+		arg.TaxExempt,
 	)
 	var i UpdateCategoryRow
 	err := row.Scan(
@@ -234,6 +367,9 @@ func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams)
 		&i.Name,
 		&i.Description,
 		&i.Type,
+		&i.ParentID,
+		&i.TaxSection,
+		&i.TaxExempt,
 		&i.User,
 		&i.CreatedAt,
 		&i.UpdatedAt,
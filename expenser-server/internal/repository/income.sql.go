@@ -152,6 +152,77 @@ func (q *Queries) GetIncome(ctx context.Context, userID uuid.UUID) ([]GetIncomeR
 	return items, nil
 }
 
+const getIncomesInRange = `-- name: GetIncomesInRange :many
+SELECT incomes.id,
+    incomes."name",
+    incomes.amount,
+    incomes.category AS category_id,
+    categories."name" AS category,
+    incomes."date"
+FROM incomes
+INNER JOIN categories ON incomes.category = categories.id
+WHERE incomes.user_id = $1 AND incomes."date" BETWEEN $2 AND $3
+ORDER BY incomes."date"
+`
+
+type GetIncomesInRangeParams struct {
+	UserID   uuid.UUID   `json:"user_id"`
+	FromDate pgtype.Date `json:"from_date"`
+	ToDate   pgtype.Date `json:"to_date"`
+}
+
+type GetIncomesInRangeRow struct {
+	ID         uuid.UUID      `json:"id"`
+	Name       string         `json:"name"`
+	Amount     pgtype.Numeric `json:"amount"`
+	CategoryID uuid.UUID      `json:"category_id"`
+	Category   string         `json:"category"`
+	Date       pgtype.Date    `json:"date"`
+}
+
+func (q *Queries) GetIncomesInRange(ctx context.Context, arg GetIncomesInRangeParams) ([]GetIncomesInRangeRow, error) {
+	rows, err := q.db.Query(ctx, getIncomesInRange, arg.UserID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetIncomesInRangeRow
+	for rows.Next() {
+		var i GetIncomesInRangeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Amount,
+			&i.CategoryID,
+			&i.Category,
+			&i.Date,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reassignIncomeCategory = `-- name: ReassignIncomeCategory :execresult
+UPDATE incomes
+SET category = $1
+WHERE category = $2 AND user_id = $3
+`
+
+type ReassignIncomeCategoryParams struct {
+	TargetCategory uuid.UUID `json:"target_category"`
+	SourceCategory uuid.UUID `json:"source_category"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) ReassignIncomeCategory(ctx context.Context, arg ReassignIncomeCategoryParams) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, reassignIncomeCategory, arg.TargetCategory, arg.SourceCategory, arg.UserID)
+}
+
 const updateIncome = `-- name: UpdateIncome :one
 WITH updated AS (
     UPDATE incomes
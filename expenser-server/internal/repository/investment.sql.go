@@ -152,6 +152,77 @@ func (q *Queries) GetInvestment(ctx context.Context, userID uuid.UUID) ([]GetInv
 	return items, nil
 }
 
+const getInvestmentsInRange = `-- name: GetInvestmentsInRange :many
+SELECT investments.id,
+    investments."name",
+    investments.amount,
+    investments.category AS category_id,
+    categories."name" AS category,
+    investments."date"
+FROM investments
+INNER JOIN categories ON investments.category = categories.id
+WHERE investments.user_id = $1 AND investments."date" BETWEEN $2 AND $3
+ORDER BY investments."date"
+`
+
+type GetInvestmentsInRangeParams struct {
+	UserID   uuid.UUID   `json:"user_id"`
+	FromDate pgtype.Date `json:"from_date"`
+	ToDate   pgtype.Date `json:"to_date"`
+}
+
+type GetInvestmentsInRangeRow struct {
+	ID         uuid.UUID      `json:"id"`
+	Name       string         `json:"name"`
+	Amount     pgtype.Numeric `json:"amount"`
+	CategoryID uuid.UUID      `json:"category_id"`
+	Category   string         `json:"category"`
+	Date       pgtype.Date    `json:"date"`
+}
+
+func (q *Queries) GetInvestmentsInRange(ctx context.Context, arg GetInvestmentsInRangeParams) ([]GetInvestmentsInRangeRow, error) {
+	rows, err := q.db.Query(ctx, getInvestmentsInRange, arg.UserID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetInvestmentsInRangeRow
+	for rows.Next() {
+		var i GetInvestmentsInRangeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Amount,
+			&i.CategoryID,
+			&i.Category,
+			&i.Date,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reassignInvestmentCategory = `-- name: ReassignInvestmentCategory :execresult
+UPDATE investments
+SET category = $1
+WHERE category = $2 AND user_id = $3
+`
+
+type ReassignInvestmentCategoryParams struct {
+	TargetCategory uuid.UUID `json:"target_category"`
+	SourceCategory uuid.UUID `json:"source_category"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) ReassignInvestmentCategory(ctx context.Context, arg ReassignInvestmentCategoryParams) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, reassignInvestmentCategory, arg.TargetCategory, arg.SourceCategory, arg.UserID)
+}
+
 const updateInvestment = `-- name: UpdateInvestment :one
 WITH updated AS (
     UPDATE investments
@@ -9,6 +9,18 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApiToken struct {
+	ID          uuid.UUID          `json:"id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	Name        string             `json:"name"`
+	Scope       string             `json:"scope"`
+	TokenHash   string             `json:"token_hash"`
+	TokenPrefix string             `json:"token_prefix"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt  pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
 type Category struct {
 	ID          uuid.UUID          `json:"id"`
 	Name        string             `json:"name"`
@@ -17,50 +29,125 @@ type Category struct {
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
 	Type        string             `json:"type"`
+	ParentID    *uuid.UUID         `json:"parent_id"`
+	TaxSection  *string            `json:"tax_section"`
+	TaxExempt   bool               `json:"tax_exempt"`
+}
+
+type Goal struct {
+	ID           uuid.UUID          `json:"id"`
+	Name         string             `json:"name"`
+	TargetAmount pgtype.Numeric     `json:"target_amount"`
+	StartDate    pgtype.Date        `json:"start_date"`
+	TargetDate   pgtype.Date        `json:"target_date"`
+	Note         *string            `json:"note"`
+	UserID       uuid.UUID          `json:"user_id"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+type GoalCategory struct {
+	GoalID     uuid.UUID `json:"goal_id"`
+	CategoryID uuid.UUID `json:"category_id"`
 }
 
 type Income struct {
-	ID        uuid.UUID          `json:"id"`
-	Name      string             `json:"name"`
-	Amount    pgtype.Numeric     `json:"amount"`
-	Category  uuid.UUID          `json:"category"`
-	Date      pgtype.Date        `json:"date"`
-	Note      *string            `json:"note"`
-	UserID    uuid.UUID          `json:"user_id"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	ID           uuid.UUID          `json:"id"`
+	Name         string             `json:"name"`
+	Amount       pgtype.Numeric     `json:"amount"`
+	Category     uuid.UUID          `json:"category"`
+	Date         pgtype.Date        `json:"date"`
+	Note         *string            `json:"note"`
+	UserID       uuid.UUID          `json:"user_id"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	SearchVector interface{}        `json:"search_vector"`
 }
 
 type Investment struct {
+	ID           uuid.UUID          `json:"id"`
+	Name         string             `json:"name"`
+	Amount       pgtype.Numeric     `json:"amount"`
+	Category     uuid.UUID          `json:"category"`
+	Date         pgtype.Date        `json:"date"`
+	Note         *string            `json:"note"`
+	UserID       uuid.UUID          `json:"user_id"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	SearchVector interface{}        `json:"search_vector"`
+}
+
+type OidcLogin struct {
+	State        string             `json:"state"`
+	Nonce        string             `json:"nonce"`
+	CodeVerifier string             `json:"code_verifier"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+}
+
+type RecoveryCode struct {
 	ID        uuid.UUID          `json:"id"`
-	Name      string             `json:"name"`
-	Amount    pgtype.Numeric     `json:"amount"`
-	Category  uuid.UUID          `json:"category"`
-	Date      pgtype.Date        `json:"date"`
-	Note      *string            `json:"note"`
 	UserID    uuid.UUID          `json:"user_id"`
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
 
-type Transaction struct {
-	ID        uuid.UUID          `json:"id"`
-	Name      string             `json:"name"`
-	Amount    pgtype.Numeric     `json:"amount"`
-	Category  uuid.UUID          `json:"category"`
-	Date      pgtype.Date        `json:"date"`
-	Note      *string            `json:"note"`
+type RevokedUser struct {
 	UserID    uuid.UUID          `json:"user_id"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+}
+
+type Transaction struct {
+	ID           uuid.UUID          `json:"id"`
+	Name         string             `json:"name"`
+	Amount       pgtype.Numeric     `json:"amount"`
+	Category     uuid.UUID          `json:"category"`
+	Date         pgtype.Date        `json:"date"`
+	Note         *string            `json:"note"`
+	UserID       uuid.UUID          `json:"user_id"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	SearchVector interface{}        `json:"search_vector"`
 }
 
 type User struct {
-	ID             uuid.UUID          `json:"id"`
-	Name           string             `json:"name"`
-	Username       string             `json:"username"`
-	HashedPassword string             `json:"hashed_password"`
-	Image          *string            `json:"image"`
+	ID                        uuid.UUID          `json:"id"`
+	Name                      string             `json:"name"`
+	Username                  string             `json:"username"`
+	HashedPassword            string             `json:"hashed_password"`
+	Image                     *string            `json:"image"`
+	CreatedAt                 pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                 pgtype.Timestamptz `json:"updated_at"`
+	Locale                    string             `json:"locale"`
+	DateFormat                string             `json:"date_format"`
+	Timezone                  string             `json:"timezone"`
+	Role                      string             `json:"role"`
+	BaseCurrency              string             `json:"base_currency"`
+	FirstDayOfWeek            string             `json:"first_day_of_week"`
+	FiscalYearStartMonth      int32              `json:"fiscal_year_start_month"`
+	DefaultExpenseCategory    *uuid.UUID         `json:"default_expense_category"`
+	DefaultIncomeCategory     *uuid.UUID         `json:"default_income_category"`
+	DefaultInvestmentCategory *uuid.UUID         `json:"default_investment_category"`
+	PayCycleStartDay          int32              `json:"pay_cycle_start_day"`
+	PasswordSet               bool               `json:"password_set"`
+}
+
+type UserIdentity struct {
+	ID              uuid.UUID          `json:"id"`
+	UserID          uuid.UUID          `json:"user_id"`
+	Issuer          string             `json:"issuer"`
+	Subject         string             `json:"subject"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	LastLoginAt     pgtype.Timestamptz `json:"last_login_at"`
+	AuthenticatedAt pgtype.Timestamptz `json:"authenticated_at"`
+}
+
+type UserTotp struct {
+	UserID         uuid.UUID          `json:"user_id"`
+	Secret         string             `json:"secret"`
+	EnabledAt      pgtype.Timestamptz `json:"enabled_at"`
+	LastUsedStep   int64              `json:"last_used_step"`
+	FailedAttempts int32              `json:"failed_attempts"`
+	LockedUntil    pgtype.Timestamptz `json:"locked_until"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 }
@@ -152,6 +152,77 @@ func (q *Queries) GetTransaction(ctx context.Context, userID uuid.UUID) ([]GetTr
 	return items, nil
 }
 
+const getTransactionsInRange = `-- name: GetTransactionsInRange :many
+SELECT transactions.id,
+    transactions."name",
+    transactions.amount,
+    transactions.category AS category_id,
+    categories."name" AS category,
+    transactions."date"
+FROM transactions
+INNER JOIN categories ON transactions.category = categories.id
+WHERE transactions.user_id = $1 AND transactions."date" BETWEEN $2 AND $3
+ORDER BY transactions."date"
+`
+
+type GetTransactionsInRangeParams struct {
+	UserID   uuid.UUID   `json:"user_id"`
+	FromDate pgtype.Date `json:"from_date"`
+	ToDate   pgtype.Date `json:"to_date"`
+}
+
+type GetTransactionsInRangeRow struct {
+	ID         uuid.UUID      `json:"id"`
+	Name       string         `json:"name"`
+	Amount     pgtype.Numeric `json:"amount"`
+	CategoryID uuid.UUID      `json:"category_id"`
+	Category   string         `json:"category"`
+	Date       pgtype.Date    `json:"date"`
+}
+
+func (q *Queries) GetTransactionsInRange(ctx context.Context, arg GetTransactionsInRangeParams) ([]GetTransactionsInRangeRow, error) {
+	rows, err := q.db.Query(ctx, getTransactionsInRange, arg.UserID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTransactionsInRangeRow
+	for rows.Next() {
+		var i GetTransactionsInRangeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Amount,
+			&i.CategoryID,
+			&i.Category,
+			&i.Date,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reassignTransactionCategory = `-- name: ReassignTransactionCategory :execresult
+UPDATE transactions
+SET category = $1
+WHERE category = $2 AND user_id = $3
+`
+
+type ReassignTransactionCategoryParams struct {
+	TargetCategory uuid.UUID `json:"target_category"`
+	SourceCategory uuid.UUID `json:"source_category"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) ReassignTransactionCategory(ctx context.Context, arg ReassignTransactionCategoryParams) (pgconn.CommandTag, error) {
+	return q.db.Exec(ctx, reassignTransactionCategory, arg.TargetCategory, arg.SourceCategory, arg.UserID)
+}
+
 const updateTransaction = `-- name: UpdateTransaction :one
 WITH updated AS (
     UPDATE transactions
@@ -12,9 +12,9 @@ import (
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users(id, name,username, hashed_password)
-VALUES ($1, $2, $3, $4)
-RETURNING id, name, username, hashed_password, image, created_at, updated_at
+INSERT INTO users(id, name,username, hashed_password, password_set)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, name, username, hashed_password, image, created_at, updated_at, locale, date_format, timezone, role, base_currency, first_day_of_week, fiscal_year_start_month, default_expense_category, default_income_category, default_investment_category, pay_cycle_start_day, password_set
 `
 
 type CreateUserParams struct {
@@ -22,6 +22,7 @@ type CreateUserParams struct {
 	Name           string    `json:"name"`
 	Username       string    `json:"username"`
 	HashedPassword string    `json:"hashed_password"`
+	PasswordSet    bool      `json:"password_set"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -30,6 +31,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.Name,
 		arg.Username,
 		arg.HashedPassword,
+		arg.PasswordSet,
 	)
 	var i User
 	err := row.Scan(
@@ -40,12 +42,24 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Image,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Locale,
+		&i.DateFormat,
+		&i.Timezone,
+		&i.Role,
+		&i.BaseCurrency,
+		&i.FirstDayOfWeek,
+		&i.FiscalYearStartMonth,
+		&i.DefaultExpenseCategory,
+		&i.DefaultIncomeCategory,
+		&i.DefaultInvestmentCategory,
+		&i.PayCycleStartDay,
+		&i.PasswordSet,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :many
-SELECT id, name, username, hashed_password, image, created_at, updated_at FROM users
+SELECT id, name, username, hashed_password, image, created_at, updated_at, locale, date_format, timezone, role, base_currency, first_day_of_week, fiscal_year_start_month, default_expense_category, default_income_category, default_investment_category, pay_cycle_start_day, password_set FROM users
 `
 
 func (q *Queries) GetUser(ctx context.Context) ([]User, error) {
@@ -65,6 +79,18 @@ func (q *Queries) GetUser(ctx context.Context) ([]User, error) {
 			&i.Image,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Locale,
+			&i.DateFormat,
+			&i.Timezone,
+			&i.Role,
+			&i.BaseCurrency,
+			&i.FirstDayOfWeek,
+			&i.FiscalYearStartMonth,
+			&i.DefaultExpenseCategory,
+			&i.DefaultIncomeCategory,
+			&i.DefaultInvestmentCategory,
+			&i.PayCycleStartDay,
+			&i.PasswordSet,
 		); err != nil {
 			return nil, err
 		}
@@ -77,7 +103,7 @@ func (q *Queries) GetUser(ctx context.Context) ([]User, error) {
 }
 
 const getUserById = `-- name: GetUserById :one
-SELECT id, name, username, hashed_password, image, created_at, updated_at FROM users WHERE id=$1
+SELECT id, name, username, hashed_password, image, created_at, updated_at, locale, date_format, timezone, role, base_currency, first_day_of_week, fiscal_year_start_month, default_expense_category, default_income_category, default_investment_category, pay_cycle_start_day, password_set FROM users WHERE id=$1
 `
 
 func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
@@ -91,12 +117,24 @@ func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Image,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Locale,
+		&i.DateFormat,
+		&i.Timezone,
+		&i.Role,
+		&i.BaseCurrency,
+		&i.FirstDayOfWeek,
+		&i.FiscalYearStartMonth,
+		&i.DefaultExpenseCategory,
+		&i.DefaultIncomeCategory,
+		&i.DefaultInvestmentCategory,
+		&i.PayCycleStartDay,
+		&i.PasswordSet,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, name, username, hashed_password, image, created_at, updated_at FROM users WHERE username=$1
+SELECT id, name, username, hashed_password, image, created_at, updated_at, locale, date_format, timezone, role, base_currency, first_day_of_week, fiscal_year_start_month, default_expense_category, default_income_category, default_investment_category, pay_cycle_start_day, password_set FROM users WHERE username=$1
 `
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
@@ -110,6 +148,18 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.Image,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Locale,
+		&i.DateFormat,
+		&i.Timezone,
+		&i.Role,
+		&i.BaseCurrency,
+		&i.FirstDayOfWeek,
+		&i.FiscalYearStartMonth,
+		&i.DefaultExpenseCategory,
+		&i.DefaultIncomeCategory,
+		&i.DefaultInvestmentCategory,
+		&i.PayCycleStartDay,
+		&i.PasswordSet,
 	)
 	return i, err
 }
@@ -119,7 +169,7 @@ UPDATE users
 SET name = $2,
     image = $3
 WHERE id = $1
-RETURNING id, name, username, hashed_password, image, created_at, updated_at
+RETURNING id, name, username, hashed_password, image, created_at, updated_at, locale, date_format, timezone, role, base_currency, first_day_of_week, fiscal_year_start_month, default_expense_category, default_income_category, default_investment_category, pay_cycle_start_day, password_set
 `
 
 type UpdateUserParams struct {
@@ -139,6 +189,87 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Image,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Locale,
+		&i.DateFormat,
+		&i.Timezone,
+		&i.Role,
+		&i.BaseCurrency,
+		&i.FirstDayOfWeek,
+		&i.FiscalYearStartMonth,
+		&i.DefaultExpenseCategory,
+		&i.DefaultIncomeCategory,
+		&i.DefaultInvestmentCategory,
+		&i.PayCycleStartDay,
+		&i.PasswordSet,
+	)
+	return i, err
+}
+
+const updateUserPreferences = `-- name: UpdateUserPreferences :one
+UPDATE users
+SET locale = $2,
+    date_format = $3,
+    timezone = $4,
+    base_currency = $5,
+    first_day_of_week = $6,
+    fiscal_year_start_month = $7,
+    default_expense_category = $8,
+    default_income_category = $9,
+    default_investment_category = $10,
+    pay_cycle_start_day = $11
+WHERE id = $1
+RETURNING id, name, username, hashed_password, image, created_at, updated_at, locale, date_format, timezone, role, base_currency, first_day_of_week, fiscal_year_start_month, default_expense_category, default_income_category, default_investment_category, pay_cycle_start_day, password_set
+`
+
+type UpdateUserPreferencesParams struct {
+	ID                        uuid.UUID  `json:"id"`
+	Locale                    string     `json:"locale"`
+	DateFormat                string     `json:"date_format"`
+	Timezone                  string     `json:"timezone"`
+	BaseCurrency              string     `json:"base_currency"`
+	FirstDayOfWeek            string     `json:"first_day_of_week"`
+	FiscalYearStartMonth      int32      `json:"fiscal_year_start_month"`
+	DefaultExpenseCategory    *uuid.UUID `json:"default_expense_category"`
+	DefaultIncomeCategory     *uuid.UUID `json:"default_income_category"`
+	DefaultInvestmentCategory *uuid.UUID `json:"default_investment_category"`
+	PayCycleStartDay          int32      `json:"pay_cycle_start_day"`
+}
+
+func (q *Queries) UpdateUserPreferences(ctx context.Context, arg UpdateUserPreferencesParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserPreferences,
+		arg.ID,
+		arg.Locale,
+		arg.DateFormat,
+		arg.Timezone,
+		arg.BaseCurrency,
+		arg.FirstDayOfWeek,
+		arg.FiscalYearStartMonth,
+		arg.DefaultExpenseCategory,
+		arg.DefaultIncomeCategory,
+		arg.DefaultInvestmentCategory,
+		arg.PayCycleStartDay,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Username,
+		&i.HashedPassword,
+		&i.Image,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Locale,
+		&i.DateFormat,
+		&i.Timezone,
+		&i.Role,
+		&i.BaseCurrency,
+		&i.FirstDayOfWeek,
+		&i.FiscalYearStartMonth,
+		&i.DefaultExpenseCategory,
+		&i.DefaultIncomeCategory,
+		&i.DefaultInvestmentCategory,
+		&i.PayCycleStartDay,
+		&i.PasswordSet,
 	)
 	return i, err
 }
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+)
+
+const (
+	healthStatusUp   = "up"
+	healthStatusDown = "down"
+
+	readinessTimeout = 2 * time.Second
+)
+
+// ReadinessCheck reports why the server can't serve traffic yet, or nil when it can.
+// Background workers register one so /readyz reflects their state too.
+type ReadinessCheck func(ctx context.Context) error
+
+type checkResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type readinessResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks"`
+}
+
+// AddReadinessCheck registers check under name. It must be called before the server
+// starts serving.
+func (s *Server) AddReadinessCheck(name string, check ReadinessCheck) {
+	if s.readinessChecks == nil {
+		s.readinessChecks = map[string]ReadinessCheck{}
+	}
+	s.readinessChecks[name] = check
+}
+
+func (s *Server) registerDefaultReadinessChecks() {
+	s.AddReadinessCheck("database", s.db.Ping)
+	s.AddReadinessCheck("migrations", func(ctx context.Context) error {
+		expected, err := database.ExpectedMigrationVersion()
+		if err != nil {
+			return err
+		}
+		applied, err := s.db.MigrationVersion(ctx)
+		if err != nil {
+			return err
+		}
+		if applied < expected {
+			return fmt.Errorf("database is at migration %d, expected %d", applied, expected)
+		}
+		return nil
+	})
+}
+
+// healthHandler reports the database pool statistics. It never fails the process,
+// a database outage is answered with 503.
+func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
+	logger.Debug(r.Context(), "processing health check request")
+	stats := s.db.Health(r.Context())
+
+	code := http.StatusOK
+	if stats["status"] != healthStatusUp {
+		code = http.StatusServiceUnavailable
+	}
+	writeHealthResponse(w, r, code, stats)
+}
+
+// livenessHandler answers as long as the process can serve HTTP. It doesn't touch any
+// dependency so a database outage never gets the server restarted.
+func (s *Server) livenessHandler(w http.ResponseWriter, r *http.Request) {
+	writeHealthResponse(w, r, http.StatusOK, checkResult{Status: healthStatusUp})
+}
+
+// readinessHandler runs every readiness check concurrently and answers 503 with the
+// failing checks when any of them fails.
+func (s *Server) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	response := readinessResponse{Status: healthStatusUp, Checks: map[string]checkResult{}}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range s.readinessChecks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := checkResult{Status: healthStatusUp}
+			if err := check(ctx); err != nil {
+				result = checkResult{Status: healthStatusDown, Error: err.Error()}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			response.Checks[name] = result
+			if result.Status == healthStatusDown {
+				response.Status = healthStatusDown
+			}
+		}()
+	}
+	wg.Wait()
+
+	code := http.StatusOK
+	if response.Status == healthStatusDown {
+		code = http.StatusServiceUnavailable
+		logger.Warn(r.Context(), "readiness check failed", map[string]any{"checks": response.Checks})
+	}
+	writeHealthResponse(w, r, code, response)
+}
+
+func writeHealthResponse(w http.ResponseWriter, r *http.Request, code int, payload any) {
+	resp, err := json.Marshal(payload)
+	if err != nil {
+		http.Error(w, "Failed to marshal health check response", http.StatusInternalServerError)
+		logger.Error(r.Context(), "failed to marshal health check response", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if _, err := w.Write(resp); err != nil {
+		logger.Error(r.Context(), "failed to write health check response", map[string]interface{}{
+			"error": err,
+		})
+	}
+}
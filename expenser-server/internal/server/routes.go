@@ -1,63 +1,166 @@
 package server
 
 import (
-	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/keertirajmalik/expenser/expenser-server/api"
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/handler"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
-	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/keertirajmalik/expenser/expenser-server/metrics"
+	"github.com/keertirajmalik/expenser/expenser-server/middleware"
 )
 
-func (s *Server) RegisterRoutes(config model.Config) http.Handler {
-	mux := http.NewServeMux()
-	mux.HandleFunc("GET /health", s.healthHandler)
-
-	mux.HandleFunc("POST /cxf/login", handler.HandleUserLogin(config.UserService, config.JWTSecret))
+const (
+	// apiPrefix is the prefix of the current version of the API.
+	apiPrefix = "/cxf/v1/"
+	// legacyPrefix served the API before it was versioned. Its paths stay as aliases of
+	// the v1 routes so existing clients keep working.
+	legacyPrefix = "/cxf/"
+)
 
-	mux.HandleFunc("GET /cxf/user", handler.HandleUserGet(config.UserService))
-	mux.HandleFunc("POST /cxf/user", handler.HandleUserCreate(config.UserService))
-	mux.HandleFunc("PUT /cxf/user", handler.HandleUserUpdate(config.UserService))
+// route is a mux pattern, the policy deciding who may call it and the handler serving it.
+type route struct {
+	pattern string
+	policy  middleware.Policy
+	handler http.Handler
+}
 
-	mux.HandleFunc("GET /cxf/transaction", handler.HandleTransactionGet(config.TransactionService))
-	mux.HandleFunc("POST /cxf/transaction", handler.HandleTransactionCreate(config.TransactionService))
-	mux.HandleFunc("DELETE /cxf/transaction/{id}", handler.HandleTransactionDelete(config.TransactionService))
-	mux.HandleFunc("PUT /cxf/transaction/{id}", handler.HandleTransactionUpdate(config.TransactionService))
+func (s *Server) RegisterRoutes(config model.Config) *http.ServeMux {
+	return newMux(s.routes(config), middleware.Authenticator{
+		Keys:           config.JWTKeys,
+		LookupAPIToken: config.APITokenService.LookupAPIToken,
+		IsRevoked:      config.UserService.IsUserRevoked,
+	})
+}
 
-	mux.HandleFunc("GET /cxf/category", handler.HandleCategoryGet(config.CategoryService))
-	mux.HandleFunc("POST /cxf/category", handler.HandleCategoryCreate(config.CategoryService))
-	mux.HandleFunc("DELETE /cxf/category/{id}", handler.HandleCategoryDelete(config.CategoryService))
-	mux.HandleFunc("PUT /cxf/category/{id}", handler.HandleCategoryUpdate(config.CategoryService))
+// newMux registers every route behind its policy and its legacy alias.
+func newMux(routes []route, authenticator middleware.Authenticator) *http.ServeMux {
+	mux := http.NewServeMux()
+	for _, route := range routes {
+		handler := middleware.Authorize(route.policy, authenticator)(route.handler)
+		mux.Handle(route.pattern, handler)
+		if alias, ok := legacyPattern(route.pattern); ok {
+			mux.Handle(alias, deprecated(route.pattern, handler))
+		}
+	}
+	return mux
+}
 
-	mux.HandleFunc("POST /cxf/investment", handler.HandleInvestmentCreate(config.InvestmentService))
-	mux.HandleFunc("GET /cxf/investment", handler.HandleInvestmentGet(config.InvestmentService))
-	mux.HandleFunc("PUT /cxf/investment/{id}", handler.HandleInvestmentUpdate(config.InvestmentService))
-	mux.HandleFunc("DELETE /cxf/investment/{id}", handler.HandleInvestmentDelete(config.InvestmentService))
+// legacyPattern returns the unversioned alias of a v1 pattern.
+func legacyPattern(pattern string) (string, bool) {
+	method, path, _ := strings.Cut(pattern, " ")
+	if !strings.HasPrefix(path, apiPrefix) {
+		return "", false
+	}
+	return method + " " + legacyPrefix + strings.TrimPrefix(path, apiPrefix), true
+}
 
-	mux.HandleFunc("POST /cxf/income", handler.HandleIncomeCreate(config.IncomeService))
-	mux.HandleFunc("GET /cxf/income", handler.HandleIncomeGet(config.IncomeService))
-	mux.HandleFunc("PUT /cxf/income/{id}", handler.HandleIncomeUpdate(config.IncomeService))
-	mux.HandleFunc("DELETE /cxf/income/{id}", handler.HandleIncomeDelete(config.IncomeService))
+// deprecated marks the responses of an alias so clients can move to the versioned path.
+func deprecated(pattern string, next http.Handler) http.Handler {
+	_, successor, _ := strings.Cut(pattern, " ")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		next.ServeHTTP(w, r)
+	})
+}
 
-	mux.HandleFunc("POST /cxf/bulk-import", handler.HandleTransactionImport())
-	return mux
+// Routes returns the patterns of every registered route, like "GET /cxf/v1/user",
+// without the legacy aliases. The OpenAPI spec is checked against them.
+func Routes() []string {
+	var patterns []string
+	for _, route := range (&Server{}).routes(model.Config{}) {
+		patterns = append(patterns, route.pattern)
+	}
+	return patterns
 }
 
-func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
-	logger.Debug("processing health check request")
-	resp, err := json.Marshal(s.db.Health())
-	if err != nil {
-		http.Error(w, "Failed to marshal health check response", http.StatusInternalServerError)
-		logger.Error("failed to marshal health check response", map[string]interface{}{
-			"error": err,
-		})
-		return
+// PublicRoutes returns the patterns of the routes served without a token.
+func PublicRoutes() []string {
+	var patterns []string
+	for _, route := range (&Server{}).routes(model.Config{}) {
+		if route.policy.IsPublic() {
+			patterns = append(patterns, route.pattern)
+		}
 	}
-	w.Header().Set("Content-Type", "application/json")
-	if _, err := w.Write(resp); err != nil {
-		logger.Error("failed to write health cealth check response", map[string]interface{}{
-			"error": err,
-		})
+	return patterns
+}
+
+func (s *Server) routes(config model.Config) []route {
+	return []route{
+		{"GET /health", middleware.Public, http.HandlerFunc(s.healthHandler)},
+		{"GET /livez", middleware.Public, http.HandlerFunc(s.livenessHandler)},
+		{"GET /readyz", middleware.Public, http.HandlerFunc(s.readinessHandler)},
+		{"GET /metrics", middleware.RequireRole(auth.RoleAdmin), metrics.Handler()},
+		{"GET /.well-known/jwks.json", middleware.Public, http.HandlerFunc(handler.HandleJWKSGet(config.JWTKeys))},
+		{"GET /cxf/v1/openapi.json", middleware.Public, http.HandlerFunc(handler.HandleOpenAPIGet(api.Spec))},
+
+		{"POST /cxf/v1/login", middleware.Public, http.HandlerFunc(handler.HandleUserLogin(config.UserService, config.TwoFactorService, config.JWTKeys, config.TokenTTL))},
+		{"GET /cxf/v1/oidc/login", middleware.Public, http.HandlerFunc(handler.HandleOIDCLogin(config.OIDCService))},
+		{"GET /cxf/v1/oidc/callback", middleware.Public, http.HandlerFunc(handler.HandleOIDCCallback(config.OIDCService, config.TwoFactorService, config.JWTKeys, config.TokenTTL))},
+		{"POST /cxf/v1/login/2fa", middleware.Public, http.HandlerFunc(handler.HandleTwoFactorLogin(config.UserService, config.TwoFactorService, config.JWTKeys, config.TokenTTL))},
+
+		{"GET /cxf/v1/user", middleware.Authenticated, http.HandlerFunc(handler.HandleUserGet(config.UserService))},
+		{"POST /cxf/v1/user", middleware.Public, http.HandlerFunc(handler.HandleUserCreate(config.UserService))},
+		{"PUT /cxf/v1/user", middleware.Authenticated, http.HandlerFunc(handler.HandleUserUpdate(config.UserService))},
+		{"DELETE /cxf/v1/user", middleware.Authenticated, http.HandlerFunc(handler.HandleUserDelete(config.UserService, config.TwoFactorService))},
+		{"GET /cxf/v1/user/export", middleware.Authenticated, http.HandlerFunc(handler.HandleUserExport(config.UserService, config.ReportService))},
+		{"GET /cxf/v1/user/preferences", middleware.Authenticated, http.HandlerFunc(handler.HandlePreferencesGet(config.UserService))},
+		{"PATCH /cxf/v1/user/preferences", middleware.Authenticated, http.HandlerFunc(handler.HandlePreferencesUpdate(config.UserService))},
+
+		{"GET /cxf/v1/user/tokens", middleware.Authenticated, http.HandlerFunc(handler.HandleAPITokenGet(config.APITokenService))},
+		{"POST /cxf/v1/user/tokens", middleware.Authenticated, http.HandlerFunc(handler.HandleAPITokenCreate(config.APITokenService))},
+		{"DELETE /cxf/v1/user/tokens/{id}", middleware.Authenticated, http.HandlerFunc(handler.HandleAPITokenDelete(config.APITokenService))},
+
+		{"POST /cxf/v1/user/2fa/enroll", middleware.Authenticated, http.HandlerFunc(handler.HandleTwoFactorEnroll(config.UserService, config.TwoFactorService))},
+		{"POST /cxf/v1/user/2fa/confirm", middleware.Authenticated, http.HandlerFunc(handler.HandleTwoFactorConfirm(config.TwoFactorService))},
+		{"DELETE /cxf/v1/user/2fa", middleware.Authenticated, http.HandlerFunc(handler.HandleTwoFactorDisable(config.TwoFactorService))},
+
+		{"GET /cxf/v1/admin/users", middleware.RequireRole(auth.RoleAdmin), http.HandlerFunc(handler.HandleUsersGet(config.UserService))},
+
+		{"GET /cxf/v1/transaction", middleware.Authenticated, http.HandlerFunc(handler.HandleTransactionGet(config.TransactionService))},
+		{"POST /cxf/v1/transaction", middleware.Authenticated, http.HandlerFunc(handler.HandleTransactionCreate(config.TransactionService))},
+		{"DELETE /cxf/v1/transaction/{id}", middleware.Authenticated, http.HandlerFunc(handler.HandleTransactionDelete(config.TransactionService))},
+		{"PUT /cxf/v1/transaction/{id}", middleware.Authenticated, http.HandlerFunc(handler.HandleTransactionUpdate(config.TransactionService))},
+		{"POST /cxf/v1/transaction/batch", middleware.Authenticated, http.HandlerFunc(handler.HandleTransactionBatch(config.TransactionService))},
+
+		{"GET /cxf/v1/category", middleware.Authenticated, http.HandlerFunc(handler.HandleCategoryGet(config.CategoryService))},
+		{"GET /cxf/v1/category/tree", middleware.Authenticated, http.HandlerFunc(handler.HandleCategoryTreeGet(config.CategoryService))},
+		{"GET /cxf/v1/category/templates", middleware.Authenticated, http.HandlerFunc(handler.HandleCategoryTemplatesGet(config.CategoryService))},
+		{"POST /cxf/v1/category/templates", middleware.Authenticated, http.HandlerFunc(handler.HandleCategoryTemplatesApply(config.CategoryService))},
+		{"POST /cxf/v1/category", middleware.Authenticated, http.HandlerFunc(handler.HandleCategoryCreate(config.CategoryService))},
+		{"DELETE /cxf/v1/category/{id}", middleware.Authenticated, http.HandlerFunc(handler.HandleCategoryDelete(config.CategoryService))},
+		{"PUT /cxf/v1/category/{id}", middleware.Authenticated, http.HandlerFunc(handler.HandleCategoryUpdate(config.CategoryService))},
+		{"POST /cxf/v1/category/{id}/merge", middleware.Authenticated, http.HandlerFunc(handler.HandleCategoryMerge(config.CategoryService))},
+
+		{"POST /cxf/v1/investment", middleware.Authenticated, http.HandlerFunc(handler.HandleInvestmentCreate(config.InvestmentService))},
+		{"GET /cxf/v1/investment", middleware.Authenticated, http.HandlerFunc(handler.HandleInvestmentGet(config.InvestmentService))},
+		{"PUT /cxf/v1/investment/{id}", middleware.Authenticated, http.HandlerFunc(handler.HandleInvestmentUpdate(config.InvestmentService))},
+		{"DELETE /cxf/v1/investment/{id}", middleware.Authenticated, http.HandlerFunc(handler.HandleInvestmentDelete(config.InvestmentService))},
+		{"POST /cxf/v1/investment/batch", middleware.Authenticated, http.HandlerFunc(handler.HandleInvestmentBatch(config.InvestmentService))},
+
+		{"POST /cxf/v1/income", middleware.Authenticated, http.HandlerFunc(handler.HandleIncomeCreate(config.IncomeService))},
+		{"GET /cxf/v1/income", middleware.Authenticated, http.HandlerFunc(handler.HandleIncomeGet(config.IncomeService))},
+		{"PUT /cxf/v1/income/{id}", middleware.Authenticated, http.HandlerFunc(handler.HandleIncomeUpdate(config.IncomeService))},
+		{"DELETE /cxf/v1/income/{id}", middleware.Authenticated, http.HandlerFunc(handler.HandleIncomeDelete(config.IncomeService))},
+		{"POST /cxf/v1/income/batch", middleware.Authenticated, http.HandlerFunc(handler.HandleIncomeBatch(config.IncomeService))},
+
+		{"GET /cxf/v1/goal", middleware.Authenticated, http.HandlerFunc(handler.HandleGoalGet(config.GoalService))},
+		{"GET /cxf/v1/goal/{id}", middleware.Authenticated, http.HandlerFunc(handler.HandleGoalGetById(config.GoalService))},
+		{"POST /cxf/v1/goal", middleware.Authenticated, http.HandlerFunc(handler.HandleGoalCreate(config.GoalService))},
+		{"PUT /cxf/v1/goal/{id}", middleware.Authenticated, http.HandlerFunc(handler.HandleGoalUpdate(config.GoalService))},
+		{"DELETE /cxf/v1/goal/{id}", middleware.Authenticated, http.HandlerFunc(handler.HandleGoalDelete(config.GoalService))},
+
+		{"GET /cxf/v1/summary", middleware.Authenticated, http.HandlerFunc(handler.HandleSummaryGet(config.ReportService))},
+		{"GET /cxf/v1/insights", middleware.Authenticated, http.HandlerFunc(handler.HandleInsightsGet(config.ReportService))},
+		{"GET /cxf/v1/forecast", middleware.Authenticated, http.HandlerFunc(handler.HandleForecastGet(config.ReportService))},
+		{"GET /cxf/v1/tax/summary", middleware.Authenticated, http.HandlerFunc(handler.HandleTaxSummaryGet(config.ReportService))},
+		{"GET /cxf/v1/tax/summary/export", middleware.Authenticated, http.HandlerFunc(handler.HandleTaxSummaryExport(config.ReportService, config.UserService))},
+		{"GET /cxf/v1/search", middleware.Authenticated, http.HandlerFunc(handler.HandleSearch(config.SearchService, config.ReportService))},
+
+		{"POST /cxf/v1/bulk-import", middleware.Authenticated, http.HandlerFunc(handler.HandleTransactionImport(config.UserService))},
 	}
-	logger.Info("health check completed successfully")
 }
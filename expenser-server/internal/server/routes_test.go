@@ -0,0 +1,100 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/keertirajmalik/expenser/expenser-server/api"
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/keertirajmalik/expenser/expenser-server/middleware"
+)
+
+func TestSpecMatchesRoutes(t *testing.T) {
+	if err := api.Verify(Routes(), PublicRoutes()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRouteAuthPolicy calls every route and its legacy alias with the handlers swapped
+// for one answering 418, so only the policy decides whether a request gets through.
+func TestRouteAuthPolicy(t *testing.T) {
+	// The rejected requests are logged by the auth middleware, keep them off the output.
+	if err := logger.Configure(io.Discard, "error", logger.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := auth.LoadKeys("", nil, "test-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userToken, err := keys.MakeJWT(uuid.New(), auth.RoleUser, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	adminToken, err := keys.MakeJWT(uuid.New(), auth.RoleAdmin, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes := (&Server{}).routes(model.Config{})
+	for i := range routes {
+		routes[i].handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+	}
+	mux := newMux(routes, middleware.Authenticator{Keys: keys})
+
+	for _, route := range routes {
+		patterns := []string{route.pattern}
+		if alias, ok := legacyPattern(route.pattern); ok {
+			patterns = append(patterns, alias)
+		}
+		requiresRole := strings.HasPrefix(route.policy.String(), "role:")
+
+		for _, pattern := range patterns {
+			t.Run(pattern, func(t *testing.T) {
+				wantAnonymous, wantUser := http.StatusUnauthorized, http.StatusTeapot
+				switch {
+				case route.policy.IsPublic():
+					wantAnonymous = http.StatusTeapot
+				case requiresRole:
+					wantUser = http.StatusForbidden
+				}
+
+				if got := requestStatus(mux, pattern, ""); got != wantAnonymous {
+					t.Errorf("without a token: got %d, want %d", got, wantAnonymous)
+				}
+				if got := requestStatus(mux, pattern, "not-a-token"); !route.policy.IsPublic() && got != http.StatusUnauthorized {
+					t.Errorf("with an invalid token: got %d, want %d", got, http.StatusUnauthorized)
+				}
+				if got := requestStatus(mux, pattern, userToken); got != wantUser {
+					t.Errorf("with a user token: got %d, want %d", got, wantUser)
+				}
+				if got := requestStatus(mux, pattern, adminToken); got != http.StatusTeapot {
+					t.Errorf("with an admin token: got %d, want %d", got, http.StatusTeapot)
+				}
+			})
+		}
+	}
+}
+
+// requestStatus calls pattern on mux with token as the bearer token, when set, and
+// returns the status of the response.
+func requestStatus(mux *http.ServeMux, pattern, token string) int {
+	method, path, _ := strings.Cut(pattern, " ")
+	path = strings.NewReplacer("{id}", "00000000-0000-0000-0000-000000000000").Replace(path)
+	request := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+	return recorder.Code
+}
@@ -1,85 +1,134 @@
 package server
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
-	"strconv"
-	"time"
 
+	"github.com/keertirajmalik/expenser/expenser-server/auth"
+	"github.com/keertirajmalik/expenser/expenser-server/config"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/database"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/model"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/repository"
 	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/keertirajmalik/expenser/expenser-server/metrics"
 	"github.com/keertirajmalik/expenser/expenser-server/middleware"
+	"github.com/keertirajmalik/expenser/expenser-server/oidc"
 )
 
 type Server struct {
 	port int
 
-	db database.Service
+	db              database.Service
+	readinessChecks map[string]ReadinessCheck
 }
 
-func LoadConfig() string {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Fatal("JWT_SECRET environment variable is not set")
+func NewServer(cfg config.Config) *http.Server {
+	NewServer := &Server{
+		port: cfg.Port,
+		db:   database.New(cfg.Database),
 	}
 
-	return jwtSecret
-}
+	NewServer.registerDefaultReadinessChecks()
 
-func NewServer() *http.Server {
-	port, _ := strconv.Atoi(os.Getenv("PORT"))
-	NewServer := &Server{
-		port: port,
-		db:   database.New(),
+	db := NewServer.db.GetConnection()
+	queries := repository.New(db)
+	metrics.RegisterDBPool(db)
+
+	jwtKeys, err := auth.LoadKeys(cfg.JWT.SigningKey, cfg.JWT.VerificationKeys, cfg.JWTSecret)
+	if err != nil {
+		logger.Error(context.Background(), "failed to load the JWT keys", map[string]any{"error": err})
+		os.Exit(1)
+	}
+	if kid := jwtKeys.SigningKeyID(); kid != "" {
+		logger.Info(context.Background(), "signing access tokens with asymmetric key", map[string]any{"kid": kid})
 	}
 
-	queries := repository.New(NewServer.db.GetConnection())
+	deductionCaps, err := cfg.Tax.Caps()
+	if err != nil {
+		logger.Error(context.Background(), "invalid tax deduction caps", map[string]any{"error": err})
+		os.Exit(1)
+	}
 
 	config := model.Config{
-		JWTSecret: LoadConfig(),
+		JWTKeys:  jwtKeys,
+		TokenTTL: cfg.TokenTTL,
 		UserService: model.UserService{
 			Queries: queries,
+			DB:      db,
 		},
 		TransactionService: model.TransactionService{
 			Queries: queries,
+			DB:      db,
 		},
 		CategoryService: model.CategoryService{
 			Queries: queries,
+			DB:      db,
 		},
 		InvestmentService: model.InvestmentService{
 			Queries: queries,
+			DB:      db,
 		},
 		IncomeService: model.IncomeService{
 			Queries: queries,
+			DB:      db,
+		},
+		ReportService: model.ReportService{
+			Queries:       queries,
+			DeductionCaps: deductionCaps,
+		},
+		GoalService: model.GoalService{
+			Queries: queries,
+			DB:      db,
+		},
+		APITokenService: model.APITokenService{
+			Queries: queries,
+		},
+		TwoFactorService: model.TwoFactorService{
+			Queries: queries,
+			DB:      db,
+		},
+		OIDCService: model.OIDCService{
+			Queries:       queries,
+			DB:            db,
+			UsernameClaim: cfg.OIDC.UsernameClaim,
+			NameClaim:     cfg.OIDC.NameClaim,
+			LinkExisting:  cfg.OIDC.LinkExisting,
+			PostLoginURL:  cfg.OIDC.PostLoginURL,
+		},
+		SearchService: model.SearchService{
+			Queries: queries,
 		},
 	}
+	if cfg.OIDC.Enabled() {
+		config.OIDCService.Provider = oidc.New(oidc.Config{
+			Issuer:       cfg.OIDC.Issuer,
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			RedirectURL:  cfg.OIDC.RedirectURL,
+			Scopes:       cfg.OIDC.Scopes,
+		})
+		logger.Info(context.Background(), "oidc login enabled", map[string]any{"issuer": cfg.OIDC.Issuer})
+	}
 
+	routes := NewServer.RegisterRoutes(config)
 	stack := middleware.CreateStack(
-		middleware.AllowCors,
+		middleware.Metrics(routes),
+		middleware.AllowCors(cfg.CORSOrigins),
+		middleware.RequestID,
 		middleware.Logging,
-		middleware.AuthMiddleware(config.JWTSecret),
 	)
 
 	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", NewServer.port),
-		Handler:      stack(NewServer.RegisterRoutes(config)),
-		IdleTimeout:  time.Minute,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		Addr:              fmt.Sprintf(":%d", NewServer.port),
+		Handler:           stack(routes),
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
 	}
 
-	log.Println(`
- _____
-| ____| __  __  _ __     ___   _ __    ___    ___   _ __
-|  _|   \ \/ / | '_ \   / _ \ | '_ \  / __|  / _ \ | '__|
-| |___   >  <  | |_) | |  __/ | | | | \__ \ |  __/ | |
-|_____| /_/\_\ | .__/   \___| |_| |_| |___/  \___| |_|
-               |_|                                       `)
-
-	logger.Info(fmt.Sprintf("Server is running on port %d", port))
+	logger.Info(context.Background(), "Server is running", map[string]any{"port": cfg.Port})
 	return server
 }
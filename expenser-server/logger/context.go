@@ -0,0 +1,18 @@
+package logger
+
+import "context"
+
+type contextKey string
+
+const requestIDKey contextKey = "requestID"
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID. Every record
+// logged with the returned context includes it.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
@@ -1,57 +1,140 @@
 package logger
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"os"
 	"runtime"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
-type customLogger struct{}
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
 
-func (c customLogger) Println(level string, v ...interface{}) {
-	file, line := getCallerInfo(3)
-	timestamp := time.Now().UTC().Format(time.RFC3339)
-	message := fmt.Sprintf("%v", v[0])
+var defaultLogger atomic.Pointer[slog.Logger]
 
-	if file == "unknown" {
-		log.Printf("%s [%s] %s", timestamp, level, message)
-		return
+func init() {
+	defaultLogger.Store(newLogger(os.Stdout, slog.LevelInfo, FormatJSON))
+}
+
+// Configure replaces the logger used by the package level functions. Records below
+// level are dropped and format is either FormatJSON or FormatText.
+func Configure(w io.Writer, level, format string) error {
+	parsedLevel, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(format) {
+	case "", FormatJSON:
+		format = FormatJSON
+	case FormatText:
+		format = FormatText
+	default:
+		return fmt.Errorf("invalid log format %q (must be %s or %s)", format, FormatJSON, FormatText)
 	}
-	log.Printf("%s [%s] %s | Caller: %s:%d", timestamp, level, message, file, line)
+
+	defaultLogger.Store(newLogger(w, parsedLevel, format))
+	return nil
 }
 
-func getCallerInfo(skip int) (string, int) {
-	for i := skip; i < skip+10; i++ {
-		_, file, line, ok := runtime.Caller(i)
-		if ok && strings.Contains(file, "expenser-server") && !strings.Contains(file, "logging") {
-			shortFile := file[strings.Index(file, "expenser-server/"):]
-			return shortFile, line
-		}
+// ParseLevel parses debug, info, warn or error. An empty string is info.
+func ParseLevel(level string) (slog.Level, error) {
+	if level == "" {
+		return slog.LevelInfo, nil
+	}
+
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(level)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q (must be debug, info, warn or error)", level)
 	}
-	return "unknown", 0
+	return parsed, nil
 }
 
-var logger = customLogger{}
+func newLogger(w io.Writer, level slog.Level, format string) *slog.Logger {
+	options := &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       level,
+		ReplaceAttr: shortenSource,
+	}
+	if format == FormatText {
+		return slog.New(slog.NewTextHandler(w, options))
+	}
+	return slog.New(slog.NewJSONHandler(w, options))
+}
 
-func init() {
-	log.SetFlags(0)
+// shortenSource trims the source file to the path inside the repository.
+func shortenSource(_ []string, attr slog.Attr) slog.Attr {
+	if attr.Key != slog.SourceKey {
+		return attr
+	}
+	source, ok := attr.Value.Any().(*slog.Source)
+	if !ok {
+		return attr
+	}
+	if index := strings.Index(source.File, "expenser-server/"); index >= 0 {
+		source.File = source.File[index:]
+	}
+	return attr
+}
+
+func Debug(ctx context.Context, message string, details ...map[string]any) {
+	log(ctx, slog.LevelDebug, message, details)
 }
 
-func Info(message string) {
-	logger.Println("INFO", message)
+func Info(ctx context.Context, message string, details ...map[string]any) {
+	log(ctx, slog.LevelInfo, message, details)
 }
 
-func Warn(message string) {
-	logger.Println("WARN", message)
+func Warn(ctx context.Context, message string, details ...map[string]any) {
+	log(ctx, slog.LevelWarn, message, details)
 }
 
-func Error(message string, details map[string]interface{}) {
-	logger.Error(message, details)
+func Error(ctx context.Context, message string, details ...map[string]any) {
+	log(ctx, slog.LevelError, message, details)
 }
 
-func Debug(message string) {
-	logger.Println("DEBUG", message)
+func log(ctx context.Context, level slog.Level, message string, details []map[string]any) {
+	l := defaultLogger.Load()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !l.Enabled(ctx, level) {
+		return
+	}
+
+	// Skip runtime.Callers, log and the exported wrapper so the source points at the caller.
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	record := slog.NewRecord(time.Now(), level, message, pcs[0])
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	for _, detail := range details {
+		keys := make([]string, 0, len(detail))
+		for key := range detail {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			record.AddAttrs(attr(key, detail[key]))
+		}
+	}
+
+	_ = l.Handler().Handle(ctx, record)
 }
 
+func attr(key string, value any) slog.Attr {
+	if err, ok := value.(error); ok && err != nil {
+		return slog.String(key, err.Error())
+	}
+	return slog.Any(key, value)
+}
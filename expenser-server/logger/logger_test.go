@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		level   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"", slog.LevelInfo, false},
+		{"debug", slog.LevelDebug, false},
+		{"INFO", slog.LevelInfo, false},
+		{"warn", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", slog.LevelInfo, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.level)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v, error %v", tt.level, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// configure points the package logger at a buffer for the test.
+func configure(t *testing.T, level, format string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Configure(&buf, level, format); err != nil {
+		t.Fatalf("Configure(%q, %q): %v", level, format, err)
+	}
+	t.Cleanup(func() { _ = Configure(os.Stdout, "info", FormatJSON) })
+	return &buf
+}
+
+func TestConfigureJSON(t *testing.T) {
+	buf := configure(t, "info", "")
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	Info(ctx, "created", map[string]any{"user_id": 7, "error": errors.New("boom")})
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("output is not JSON: %v: %s", err, buf)
+	}
+	want := map[string]any{"level": "INFO", "msg": "created", "request_id": "req-1", "user_id": float64(7), "error": "boom"}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("%s = %v, want %v", key, record[key], value)
+		}
+	}
+	source, _ := record["source"].(map[string]any)
+	if file, _ := source["file"].(string); !strings.HasSuffix(file, "logger/logger_test.go") {
+		t.Errorf("source file = %q, want the caller", file)
+	}
+}
+
+func TestConfigureText(t *testing.T) {
+	buf := configure(t, "debug", "TEXT")
+
+	Debug(ContextWithRequestID(context.Background(), "req-2"), "loaded")
+
+	for _, want := range []string{"level=DEBUG", "msg=loaded", "request_id=req-2"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output %q is missing %s", buf, want)
+		}
+	}
+}
+
+func TestLogWithoutRequestID(t *testing.T) {
+	buf := configure(t, "info", FormatText)
+
+	Info(context.Background(), "started")
+	// A nil context is tolerated.
+	Info(nil, "started")
+
+	if strings.Contains(buf.String(), "request_id") {
+		t.Errorf("output %q has a request_id without one in the context", buf)
+	}
+	if got := strings.Count(buf.String(), "msg=started"); got != 2 {
+		t.Errorf("got %d records, want 2: %s", got, buf)
+	}
+}
+
+func TestConfigureLevel(t *testing.T) {
+	buf := configure(t, "warn", FormatText)
+
+	Info(context.Background(), "dropped")
+	Warn(context.Background(), "kept")
+
+	if strings.Contains(buf.String(), "dropped") || !strings.Contains(buf.String(), "kept") {
+		t.Errorf("output %q, want only the warning", buf)
+	}
+}
+
+func TestConfigureInvalid(t *testing.T) {
+	if err := Configure(&bytes.Buffer{}, "verbose", FormatJSON); err == nil {
+		t.Error("Configure accepted an invalid level")
+	}
+	if err := Configure(&bytes.Buffer{}, "info", "xml"); err == nil {
+		t.Error("Configure accepted an invalid format")
+	}
+}
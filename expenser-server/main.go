@@ -2,17 +2,22 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"errors"
+	"flag"
 	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	// Embed the zone database, the alpine image has none and user timezones must resolve.
+	_ "time/tzdata"
 
+	"github.com/keertirajmalik/expenser/expenser-server/config"
 	"github.com/keertirajmalik/expenser/expenser-server/internal/server"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
 )
 
-func gracefulShutdown(apiServer *http.Server, done chan bool) {
+func gracefulShutdown(apiServer *http.Server, timeout time.Duration, done chan bool) {
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -20,34 +25,49 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 	// Listen for the interrupt signal.
 	<-ctx.Done()
 
-	log.Println("shutting down gracefully, press Ctrl+C again to force")
+	logger.Info(context.Background(), "shutting down gracefully, press Ctrl+C again to force")
 
-	// The context is used to inform the server it has 5 seconds to finish
+	// The context is used to inform the server it has timeout to finish
 	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	if err := apiServer.Shutdown(ctx); err != nil {
-		log.Printf("Server forced to shutdown with error: %v", err)
+		logger.Error(context.Background(), "server forced to shut down", map[string]any{"error": err})
 	}
 
-	log.Println("Server exiting")
+	logger.Info(context.Background(), "server exiting")
 
 	// Notify the main goroutine that the shutdown is complete
 	done <- true
 }
 
 func main() {
-	server := server.NewServer()
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		logger.Error(context.Background(), "invalid configuration", map[string]any{"error": err})
+		os.Exit(1)
+	}
+	if err := logger.Configure(os.Stdout, cfg.Log.Level, cfg.Log.Format); err != nil {
+		logger.Error(context.Background(), "invalid log configuration", map[string]any{"error": err})
+		os.Exit(1)
+	}
+	logger.Info(context.Background(), "configuration loaded", cfg.Redacted())
+
+	server := server.NewServer(cfg)
 
 	done := make(chan bool, 1)
 
-	go gracefulShutdown(server, done)
+	go gracefulShutdown(server, cfg.HTTP.ShutdownTimeout, done)
 
-	err := server.ListenAndServe()
+	err = server.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
-		panic(fmt.Sprintf("http server error: %s", err))
+		logger.Error(context.Background(), "http server error", map[string]any{"error": err})
+		os.Exit(1)
 	}
 
 	<-done
-	log.Println("Graceful shutdown complete.")
+	logger.Info(context.Background(), "graceful shutdown complete")
 }
@@ -0,0 +1,72 @@
+package metrics
+
+import "github.com/jackc/pgx/v5/pgxpool"
+
+var (
+	HTTPRequestDuration = Default.NewHistogramVec(
+		"expenser_http_request_duration_seconds",
+		"Duration of HTTP requests by route pattern and status code.",
+		DefaultBuckets, "method", "route", "status",
+	)
+
+	DBQueryDuration = Default.NewHistogramVec(
+		"expenser_db_query_duration_seconds",
+		"Duration of database queries by sqlc query name.",
+		DefaultBuckets, "query", "status",
+	)
+
+	ImportsTotal = Default.NewCounterVec(
+		"expenser_imports_total",
+		"Bulk imports by result.",
+		"result",
+	)
+
+	ImportedRowsTotal = Default.NewCounterVec(
+		"expenser_imported_rows_total",
+		"Rows read from successful bulk imports.",
+	)
+
+	LoginsTotal = Default.NewCounterVec(
+		"expenser_logins_total",
+		"Successful logins.",
+	)
+
+	LoginFailuresTotal = Default.NewCounterVec(
+		"expenser_login_failures_total",
+		"Failed logins by reason.",
+		"reason",
+	)
+)
+
+// RegisterDBPool exposes the statistics of pool. They are read on every scrape.
+func RegisterDBPool(pool *pgxpool.Pool) {
+	gauges := []struct {
+		name, help string
+		read       func(stat *pgxpool.Stat) float64
+	}{
+		{"expenser_db_pool_total_conns", "Connections currently open in the pool.", func(s *pgxpool.Stat) float64 { return float64(s.TotalConns()) }},
+		{"expenser_db_pool_idle_conns", "Idle connections in the pool.", func(s *pgxpool.Stat) float64 { return float64(s.IdleConns()) }},
+		{"expenser_db_pool_acquired_conns", "Connections currently in use.", func(s *pgxpool.Stat) float64 { return float64(s.AcquiredConns()) }},
+		{"expenser_db_pool_constructing_conns", "Connections being established.", func(s *pgxpool.Stat) float64 { return float64(s.ConstructingConns()) }},
+		{"expenser_db_pool_max_conns", "Maximum size of the pool.", func(s *pgxpool.Stat) float64 { return float64(s.MaxConns()) }},
+	}
+	for _, gauge := range gauges {
+		read := gauge.read
+		Default.NewGaugeFunc(gauge.name, gauge.help, func() float64 { return read(pool.Stat()) })
+	}
+
+	counters := []struct {
+		name, help string
+		read       func(stat *pgxpool.Stat) float64
+	}{
+		{"expenser_db_pool_acquires_total", "Connections acquired from the pool.", func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) }},
+		{"expenser_db_pool_empty_acquires_total", "Acquires that had to wait because the pool was empty.", func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) }},
+		{"expenser_db_pool_canceled_acquires_total", "Acquires canceled by their context.", func(s *pgxpool.Stat) float64 { return float64(s.CanceledAcquireCount()) }},
+		{"expenser_db_pool_acquire_duration_seconds_total", "Total time spent acquiring connections.", func(s *pgxpool.Stat) float64 { return s.AcquireDuration().Seconds() }},
+		{"expenser_db_pool_new_conns_total", "Connections opened by the pool.", func(s *pgxpool.Stat) float64 { return float64(s.NewConnsCount()) }},
+	}
+	for _, counter := range counters {
+		read := counter.read
+		Default.NewCounterFunc(counter.name, counter.help, func() float64 { return read(pool.Stat()) })
+	}
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	name  string
+	start time.Time
+}
+
+// QueryTracer records the duration of every query in DBQueryDuration, labelled with
+// the name sqlc puts in front of the SQL.
+type QueryTracer struct{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{name: queryName(data.SQL), start: time.Now()})
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	status := "ok"
+	if data.Err != nil {
+		status = "error"
+	}
+	DBQueryDuration.Observe(time.Since(start.start).Seconds(), start.name, status)
+}
+
+// queryName extracts X from a "-- name: X :one" header. Statements without one, like
+// the BEGIN and COMMIT of transactions, are grouped under "other".
+func queryName(sql string) string {
+	header, ok := strings.CutPrefix(strings.TrimSpace(sql), "-- name:")
+	if !ok {
+		return "other"
+	}
+	fields := strings.Fields(header)
+	if len(fields) == 0 {
+		return "other"
+	}
+	return fields[0]
+}
@@ -0,0 +1,177 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// labelSet keeps one series per distinct combination of label values.
+type labelSet[T any] struct {
+	mu         sync.Mutex
+	labelNames []string
+	series     map[string]*series[T]
+}
+
+type series[T any] struct {
+	labelValues []string
+	value       T
+}
+
+func newLabelSet[T any](labelNames []string) labelSet[T] {
+	return labelSet[T]{labelNames: labelNames, series: map[string]*series[T]{}}
+}
+
+// with runs fn on the series of labelValues while holding the lock, creating it first
+// with init when needed.
+func (l *labelSet[T]) with(labelValues []string, init func() T, fn func(value *T)) {
+	if len(labelValues) != len(l.labelNames) {
+		panic(fmt.Sprintf("metrics: got %d label values for labels %v", len(labelValues), l.labelNames))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s, ok := l.series[key]
+	if !ok {
+		s = &series[T]{labelValues: append([]string(nil), labelValues...), value: init()}
+		l.series[key] = s
+	}
+	fn(&s.value)
+}
+
+// each calls fn for every series ordered by label values.
+func (l *labelSet[T]) each(fn func(labelValues []string, value T)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	keys := make([]string, 0, len(l.series))
+	for key := range l.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fn(l.series[key].labelValues, l.series[key].value)
+	}
+}
+
+// CounterVec is a monotonically increasing value partitioned by labels.
+type CounterVec struct {
+	metricName, metricHelp string
+	values                 labelSet[float64]
+}
+
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{metricName: name, metricHelp: help, values: newLabelSet[float64](labelNames)}
+	r.register(c)
+	return c
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic("metrics: counters can't decrease")
+	}
+	c.values.with(labelValues, func() float64 { return 0 }, func(value *float64) { *value += delta })
+}
+
+func (c *CounterVec) name() string       { return c.metricName }
+func (c *CounterVec) help() string       { return c.metricHelp }
+func (c *CounterVec) metricType() string { return "counter" }
+
+func (c *CounterVec) collect() []sample {
+	var samples []sample
+	c.values.each(func(labelValues []string, value float64) {
+		samples = append(samples, sample{labelNames: c.values.labelNames, labelValues: labelValues, value: value})
+	})
+	return samples
+}
+
+// HistogramVec counts observations into cumulative buckets partitioned by labels.
+type HistogramVec struct {
+	metricName, metricHelp string
+	buckets                []float64
+	values                 labelSet[*histogram]
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	h := &HistogramVec{metricName: name, metricHelp: help, buckets: buckets, values: newLabelSet[*histogram](labelNames)}
+	r.register(h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.values.with(labelValues, func() *histogram {
+		return &histogram{counts: make([]uint64, len(h.buckets))}
+	}, func(hist **histogram) {
+		for i, upperBound := range h.buckets {
+			if value <= upperBound {
+				(*hist).counts[i]++
+			}
+		}
+		(*hist).count++
+		(*hist).sum += value
+	})
+}
+
+func (h *HistogramVec) name() string       { return h.metricName }
+func (h *HistogramVec) help() string       { return h.metricHelp }
+func (h *HistogramVec) metricType() string { return "histogram" }
+
+func (h *HistogramVec) collect() []sample {
+	labelNames := h.values.labelNames
+	bucketLabelNames := append(append([]string(nil), labelNames...), "le")
+
+	var samples []sample
+	h.values.each(func(labelValues []string, hist *histogram) {
+		for i, upperBound := range h.buckets {
+			samples = append(samples, sample{
+				suffix:      "_bucket",
+				labelNames:  bucketLabelNames,
+				labelValues: append(append([]string(nil), labelValues...), formatValue(upperBound)),
+				value:       float64(hist.counts[i]),
+			})
+		}
+		samples = append(samples,
+			sample{suffix: "_bucket", labelNames: bucketLabelNames, labelValues: append(append([]string(nil), labelValues...), "+Inf"), value: float64(hist.count)},
+			sample{suffix: "_sum", labelNames: labelNames, labelValues: labelValues, value: hist.sum},
+			sample{suffix: "_count", labelNames: labelNames, labelValues: labelValues, value: float64(hist.count)},
+		)
+	})
+	return samples
+}
+
+// funcMetric reads its value when the registry is scraped.
+type funcMetric struct {
+	metricName, metricHelp, kind string
+	read                         func() float64
+}
+
+// NewGaugeFunc registers a gauge whose value is read from fn on every scrape.
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.register(&funcMetric{metricName: name, metricHelp: help, kind: "gauge", read: fn})
+}
+
+// NewCounterFunc registers a counter whose value is read from fn on every scrape.
+func (r *Registry) NewCounterFunc(name, help string, fn func() float64) {
+	r.register(&funcMetric{metricName: name, metricHelp: help, kind: "counter", read: fn})
+}
+
+func (f *funcMetric) name() string       { return f.metricName }
+func (f *funcMetric) help() string       { return f.metricHelp }
+func (f *funcMetric) metricType() string { return f.kind }
+
+func (f *funcMetric) collect() []sample {
+	return []sample{{value: f.read()}}
+}
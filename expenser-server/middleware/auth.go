@@ -2,63 +2,166 @@ package middleware
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
-	"strings"
 
+	"github.com/google/uuid"
 	"github.com/keertirajmalik/expenser/expenser-server/auth"
 	"github.com/keertirajmalik/expenser/expenser-server/logger"
+	"github.com/keertirajmalik/expenser/expenser-server/problem"
 )
 
-type errorResponse struct {
-	Error string `json:"error"`
+// Policy declares who may call a route. Every route picks one when it is registered,
+// there is no default.
+type Policy struct {
+	public bool
+	role   string
 }
 
-func AuthMiddleware(jwtSecret string) Middleware {
+var (
+	// Public routes are served without a token.
+	Public = Policy{public: true}
+	// Authenticated routes need a valid token of any role.
+	Authenticated = Policy{}
+)
+
+// RequireRole is the policy of routes that need a valid token with the given role.
+func RequireRole(role string) Policy {
+	return Policy{role: role}
+}
+
+func (p Policy) IsPublic() bool {
+	return p.public
+}
+
+func (p Policy) String() string {
+	switch {
+	case p.public:
+		return "public"
+	case p.role != "":
+		return "role:" + p.role
+	default:
+		return "authenticated"
+	}
+}
+
+// APITokenLookup returns who the personal API token with the given hash authenticates
+// as, ok is false when the token is unknown or expired.
+type APITokenLookup func(ctx context.Context, hash string) (identity auth.Identity, ok bool, err error)
+
+// RevocationCheck reports whether the access tokens of the user were revoked.
+type RevocationCheck func(ctx context.Context, userID uuid.UUID) (bool, error)
+
+// Authenticator checks the bearer tokens: login JWTs verified with Keys and checked with
+// IsRevoked, and personal API tokens resolved by LookupAPIToken.
+type Authenticator struct {
+	Keys           *auth.Keys
+	LookupAPIToken APITokenLookup
+	IsRevoked      RevocationCheck
+}
+
+// authenticate returns who token authenticates as. The error is an *authError carrying
+// the status to answer with.
+func (a Authenticator) authenticate(ctx context.Context, token string) (auth.Identity, error) {
+	if !auth.IsAPIToken(token) {
+		userID, role, err := a.Keys.ValidateJWT(token)
+		if err != nil {
+			return auth.Identity{}, &authError{status: http.StatusUnauthorized, err: err}
+		}
+		if a.IsRevoked != nil {
+			revoked, err := a.IsRevoked(ctx, userID)
+			if err != nil {
+				return auth.Identity{}, &authError{status: http.StatusInternalServerError, err: err}
+			}
+			if revoked {
+				return auth.Identity{}, &authError{status: http.StatusUnauthorized, err: errors.New("token has been revoked")}
+			}
+		}
+		return auth.Identity{UserID: userID, Role: role}, nil
+	}
+
+	if a.LookupAPIToken == nil {
+		return auth.Identity{}, &authError{status: http.StatusUnauthorized, err: errors.New("api tokens aren't accepted")}
+	}
+	identity, ok, err := a.LookupAPIToken(ctx, auth.HashAPIToken(token))
+	if err != nil {
+		return auth.Identity{}, &authError{status: http.StatusInternalServerError, err: err}
+	}
+	if !ok {
+		return auth.Identity{}, &authError{status: http.StatusUnauthorized, err: errors.New("invalid or expired api token")}
+	}
+	return identity, nil
+}
+
+type authError struct {
+	status int
+	err    error
+}
+
+func (e *authError) Error() string {
+	return e.err.Error()
+}
+
+// readOnlyMethods are the methods read scoped API tokens may call.
+var readOnlyMethods = map[string]bool{
+	http.MethodGet:  true,
+	http.MethodHead: true,
+}
+
+// Authorize enforces policy on the route it wraps. The user, role and, for API tokens,
+// scope of the token are added to the request context.
+func Authorize(policy Policy, authenticator Authenticator) Middleware {
 	return func(next http.Handler) http.Handler {
+		if policy.public {
+			return next
+		}
+
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if strings.Contains(r.URL.Path, "login") || (strings.Contains(r.URL.Path, "user") && r.Method == "POST") {
-				next.ServeHTTP(w, r)
+			token, err := auth.GetBearerToken(r.Header)
+			if err != nil {
+				logger.Error(r.Context(), "error with token", map[string]any{"error": err})
+				respondWithJson(r.Context(), w, http.StatusUnauthorized, err)
 				return
 			}
 
-			token, err := auth.GetBearerToken(r.Header)
+			identity, err := authenticator.authenticate(r.Context(), token)
 			if err != nil {
-				logger.Error("error with token", map[string]any{"error": err})
-				respondWithJson(w, err)
+				logger.Error(r.Context(), "error with token", map[string]any{"error": err})
+				status := err.(*authError).status
+				if status == http.StatusInternalServerError {
+					err = errors.New("Something went wrong, please try again later")
+				}
+				respondWithJson(r.Context(), w, status, err)
 				return
 			}
 
-			userID, err := auth.ValidateJWT(token, []byte(jwtSecret))
-			if err != nil {
-				logger.Error("error with token", map[string]any{"error": err})
-				respondWithJson(w, err)
+			if policy.role != "" && identity.Role != policy.role {
+				logger.Warn(r.Context(), "role not allowed on route", map[string]any{"userID": identity.UserID, "role": identity.Role, "required": policy.role})
+				respondWithJson(r.Context(), w, http.StatusForbidden, fmt.Errorf("requires the %s role", policy.role))
+				return
+			}
+
+			if identity.Scope == auth.ScopeRead && !readOnlyMethods[r.Method] {
+				respondWithJson(r.Context(), w, http.StatusForbidden, errors.New("api token is read-only"))
 				return
 			}
 
-			// Add userID to request context
-			ctx := context.WithValue(r.Context(), auth.UserIDKey, userID)
+			// Add userID, role and scope to request context
+			ctx := context.WithValue(r.Context(), auth.UserIDKey, identity.UserID)
+			ctx = context.WithValue(ctx, auth.RoleKey, identity.Role)
+			if identity.Scope != "" {
+				ctx = context.WithValue(ctx, auth.ScopeKey, identity.Scope)
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
-func respondWithJson(w http.ResponseWriter, err error) {
-	dat, err := json.Marshal(errorResponse{
-		Error: err.Error(),
-	})
-	if err != nil {
-		logger.Error("Error marshalling JSON", map[string]any{"error": err})
-		w.WriteHeader(500)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusUnauthorized)
-	_, err = w.Write(dat)
-	if err != nil {
-		logger.Error("error while writing the response", map[string]any{"error": err})
-		w.WriteHeader(500)
-		return
+func respondWithJson(ctx context.Context, w http.ResponseWriter, status int, err error) {
+	p := problem.New(status, "", err.Error())
+	p.RequestID = w.Header().Get(RequestIDHeader)
+	if err := problem.Write(w, p); err != nil {
+		logger.Error(ctx, "error while writing the response", map[string]any{"error": err})
 	}
 }
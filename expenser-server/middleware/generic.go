@@ -1,18 +1,15 @@
 package middleware
 
-import (
-	"net/http"
+import "github.com/rs/cors"
 
-	"github.com/rs/cors"
-)
-
-func AllowCors(next http.Handler) http.Handler {
+func AllowCors(origins []string) Middleware {
 	c := cors.New(cors.Options{
-		AllowedOrigins:   []string{"*"}, // Adjust this to your needs
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
-		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowedOrigins:   origins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type", RequestIDHeader},
+		ExposedHeaders:   []string{RequestIDHeader},
 		AllowCredentials: true,
 	})
 
-	return c.Handler(next)
+	return c.Handler
 }
@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"fmt"
 	"net/http"
 	"time"
 
@@ -29,14 +28,12 @@ func Logging(next http.Handler) http.Handler {
 
 		next.ServeHTTP(wrapped, r)
 
-		duration := time.Since(start)
-		logMessage := fmt.Sprintf("HTTP Request: %d | %s | %s | %s",
-			wrapped.statusCode, // Status code
-			r.Method,           // HTTP method
-			r.URL.Path,         // Request path
-			duration,           // Duration
-		)
-		logger.Info(logMessage)
+		logger.Info(r.Context(), "HTTP Request", map[string]any{
+			"status":      wrapped.statusCode,
+			"method":      r.Method,
+			"path":        r.URL.Path,
+			"duration_ms": time.Since(start).Milliseconds(),
+		})
 	})
 }
 
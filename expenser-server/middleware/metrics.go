@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/keertirajmalik/expenser/expenser-server/metrics"
+)
+
+// routeMatcher is implemented by *http.ServeMux.
+type routeMatcher interface {
+	Handler(r *http.Request) (http.Handler, string)
+}
+
+// Metrics records the latency of every request by the route pattern it matched, so
+// paths carrying IDs don't each get their own series.
+func Metrics(routes routeMatcher) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			wrapped := &wrappedWriter{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
+
+			_, route := routes.Handler(r)
+			if route == "" {
+				route = "unmatched"
+			}
+
+			next.ServeHTTP(wrapped, r)
+
+			metrics.HTTPRequestDuration.Observe(time.Since(start).Seconds(), r.Method, route, strconv.Itoa(wrapped.statusCode))
+		})
+	}
+}
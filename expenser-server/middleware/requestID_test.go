@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/keertirajmalik/expenser/expenser-server/logger"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		wantSame bool
+	}{
+		{"none sent", "", false},
+		{"valid", "3f2c9a1e-req", true},
+		{"longest valid", strings.Repeat("a", maxRequestIDLength), true},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1), false},
+		{"with a space", "req 1", false},
+		{"with a newline", "req\n1", false},
+		{"not ASCII", "réq-1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = logger.RequestIDFromContext(r.Context())
+			}))
+
+			request := httptest.NewRequest(http.MethodGet, "/cxf/v1/user", nil)
+			if tt.incoming != "" {
+				request.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			got := recorder.Header().Get(RequestIDHeader)
+			if got != seen {
+				t.Errorf("response header %q, context %q, want the same ID", got, seen)
+			}
+			if tt.wantSame {
+				if got != tt.incoming {
+					t.Errorf("request ID = %q, want the incoming %q", got, tt.incoming)
+				}
+				return
+			}
+			if _, err := uuid.Parse(got); err != nil {
+				t.Errorf("request ID = %q, want a generated UUID", got)
+			}
+		})
+	}
+}
+
+func TestRequestIDIsUniquePerRequest(t *testing.T) {
+	handler := RequestID(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	ids := map[string]bool{}
+	for range 3 {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		ids[recorder.Header().Get(RequestIDHeader)] = true
+	}
+	if len(ids) != 3 {
+		t.Errorf("got %d distinct IDs for 3 requests", len(ids))
+	}
+}
@@ -0,0 +1,114 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/keertirajmalik/expenser/expenser-server/oidc/oidctest"
+)
+
+const redirectURL = "http://localhost:8080/cxf/v1/oidc/callback"
+
+// signIn starts a login at devidp and signs in email, returning the code the callback
+// gets and the verifier and nonce of the login.
+func signIn(t *testing.T, provider *Provider, idp *oidctest.Server, email string, verified bool) (code, verifier, nonce string) {
+	t.Helper()
+	ctx := context.Background()
+	verifier, challenge, err := NewPKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce, _ = RandomString()
+	authURL, err := provider.AuthCodeURL(ctx, "state", nonce, challenge, false)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	callback, err := idp.SignIn(ctx, authURL, email, verified)
+	if err != nil {
+		t.Fatalf("SignIn: %v", err)
+	}
+	if got := callback.Query().Get("state"); got != "state" {
+		t.Fatalf("callback state = %q, want state", got)
+	}
+	return callback.Query().Get("code"), verifier, nonce
+}
+
+func TestExchange(t *testing.T) {
+	idp, err := oidctest.NewServer("expenser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idp.Close()
+	provider := New(Config{Issuer: idp.URL, ClientID: "expenser", RedirectURL: redirectURL, Scopes: []string{"openid", "email"}})
+
+	t.Run("verified email", func(t *testing.T) {
+		code, verifier, nonce := signIn(t, provider, idp, "ada@example.com", true)
+		claims, err := provider.Exchange(context.Background(), code, verifier, nonce)
+		if err != nil {
+			t.Fatalf("Exchange: %v", err)
+		}
+		if claims.String("sub") != "ada@example.com" || claims.String("email") != "ada@example.com" || claims["email_verified"] != true {
+			t.Errorf("claims = %v", claims)
+		}
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		code, verifier, nonce := signIn(t, provider, idp, "ada@example.com", false)
+		claims, err := provider.Exchange(context.Background(), code, verifier, nonce)
+		if err != nil {
+			t.Fatalf("Exchange: %v", err)
+		}
+		if claims["email_verified"] != false {
+			t.Errorf("email_verified = %v, want false", claims["email_verified"])
+		}
+	})
+
+	t.Run("wrong verifier", func(t *testing.T) {
+		code, _, nonce := signIn(t, provider, idp, "ada@example.com", true)
+		if _, err := provider.Exchange(context.Background(), code, "not-the-verifier", nonce); err == nil {
+			t.Error("code redeemed with the wrong verifier")
+		}
+	})
+
+	t.Run("wrong nonce", func(t *testing.T) {
+		code, verifier, _ := signIn(t, provider, idp, "ada@example.com", true)
+		if _, err := provider.Exchange(context.Background(), code, verifier, "not-the-nonce"); err == nil {
+			t.Error("id token accepted with the wrong nonce")
+		}
+	})
+
+	t.Run("code used twice", func(t *testing.T) {
+		code, verifier, nonce := signIn(t, provider, idp, "ada@example.com", true)
+		if _, err := provider.Exchange(context.Background(), code, verifier, nonce); err != nil {
+			t.Fatalf("Exchange: %v", err)
+		}
+		if _, err := provider.Exchange(context.Background(), code, verifier, nonce); err == nil {
+			t.Error("code redeemed twice")
+		}
+	})
+}
+
+func TestAuthCodeURLReauth(t *testing.T) {
+	idp, err := oidctest.NewServer("expenser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idp.Close()
+	provider := New(Config{Issuer: idp.URL, ClientID: "expenser", RedirectURL: redirectURL})
+
+	for _, reauth := range []bool{false, true} {
+		authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "challenge", reauth)
+		if err != nil {
+			t.Fatalf("AuthCodeURL: %v", err)
+		}
+		u, err := url.Parse(authURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		query := u.Query()
+		if got := query.Get("prompt") == "login" && query.Get("max_age") == "0"; got != reauth {
+			t.Errorf("reauth %v: prompt %q, max_age %q", reauth, query.Get("prompt"), query.Get("max_age"))
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// Package oidctest is a stand-in OpenID provider for developing and testing the OIDC
+// login without a real identity provider. It signs in whoever types an email address,
+// so never expose it.
+package oidctest
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "devidp"
+
+// authorization is what an issued code stands for until it is redeemed.
+type authorization struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	email         string
+	emailVerified bool
+	name          string
+	authTime      time.Time
+	expiresAt     time.Time
+}
+
+// Provider serves discovery, keys, a sign in form and the token endpoint for one client.
+type Provider struct {
+	issuer   string
+	clientID string
+	key      ed25519.PrivateKey
+	mux      *http.ServeMux
+
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+var loginPage = template.Must(template.New("login").Parse(`<!doctype html>
+<title>devidp</title>
+<h1>devidp sign in</h1>
+<form method="post">
+  {{range $name, $values := .}}<input type="hidden" name="{{$name}}" value="{{index $values 0}}">{{end}}
+  <p><label>Email <input name="email" type="email" required></label></p>
+  <p><label><input name="email_verified" type="checkbox" value="true" checked> Email verified</label></p>
+  <p><label>Name <input name="name"></label></p>
+  <button>Sign in</button>
+</form>
+`))
+
+// NewProvider returns a provider reachable at issuer accepting only clientID, signing
+// ID tokens with a new Ed25519 key.
+func NewProvider(issuer, clientID string) (*Provider, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	p := &Provider{issuer: issuer, clientID: clientID, key: key, codes: map[string]authorization{}}
+
+	p.mux = http.NewServeMux()
+	p.mux.HandleFunc("GET /.well-known/openid-configuration", p.handleDiscovery)
+	p.mux.HandleFunc("GET /jwks", p.handleJWKS)
+	p.mux.HandleFunc("GET /authorize", p.handleAuthorizeForm)
+	p.mux.HandleFunc("POST /authorize", p.handleAuthorize)
+	p.mux.HandleFunc("POST /token", p.handleToken)
+	return p, nil
+}
+
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mux.ServeHTTP(w, r)
+}
+
+// Server is a Provider listening on a local port, for tests.
+type Server struct {
+	*httptest.Server
+	Provider *Provider
+}
+
+// NewServer starts a provider for clientID on a local port. Close it when done.
+func NewServer(clientID string) (*Server, error) {
+	server := httptest.NewUnstartedServer(nil)
+	provider, err := NewProvider("http://"+server.Listener.Addr().String(), clientID)
+	if err != nil {
+		server.Close()
+		return nil, err
+	}
+	server.Config.Handler = provider
+	server.Start()
+	return &Server{Server: server, Provider: provider}, nil
+}
+
+// SignIn does what a browser sent to authURL does: it submits the sign in form for
+// email and returns the callback URL the provider redirects to, with the code and state.
+func (s *Server) SignIn(ctx context.Context, authURL, email string, emailVerified bool) (*url.URL, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return nil, err
+	}
+	form := u.Query()
+	form.Set("email", email)
+	if emailVerified {
+		form.Set("email_verified", "true")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL+"/authorize", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusFound {
+		return nil, fmt.Errorf("oidctest: sign in answered %s", response.Status)
+	}
+	return url.Parse(response.Header.Get("Location"))
+}
+
+func (p *Provider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"EdDSA"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *Provider) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	public := p.key.Public().(ed25519.PublicKey)
+	writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+		"kty": "OKP",
+		"crv": "Ed25519",
+		"kid": keyID,
+		"alg": "EdDSA",
+		"use": "sig",
+		"x":   base64.RawURLEncoding.EncodeToString(public),
+	}}})
+}
+
+func (p *Provider) handleAuthorizeForm(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != p.clientID || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "unknown client or missing PKCE", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	loginPage.Execute(w, query)
+}
+
+func (p *Provider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	redirectURI, err := url.Parse(r.PostForm.Get("redirect_uri"))
+	if err != nil || r.PostForm.Get("client_id") != p.clientID || r.PostForm.Get("email") == "" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	p.mu.Lock()
+	p.codes[code] = authorization{
+		clientID:      p.clientID,
+		redirectURI:   redirectURI.String(),
+		nonce:         r.PostForm.Get("nonce"),
+		codeChallenge: r.PostForm.Get("code_challenge"),
+		email:         r.PostForm.Get("email"),
+		emailVerified: r.PostForm.Get("email_verified") == "true",
+		name:          r.PostForm.Get("name"),
+		authTime:      time.Now(),
+		expiresAt:     time.Now().Add(time.Minute),
+	}
+	p.mu.Unlock()
+
+	query := redirectURI.Query()
+	query.Set("code", code)
+	query.Set("state", r.PostForm.Get("state"))
+	redirectURI.RawQuery = query.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *Provider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	auth, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	switch {
+	case r.PostForm.Get("grant_type") != "authorization_code":
+		tokenError(w, "unsupported_grant_type")
+		return
+	case !ok || time.Now().After(auth.expiresAt):
+		tokenError(w, "invalid_grant")
+		return
+	case r.PostForm.Get("redirect_uri") != auth.redirectURI:
+		tokenError(w, "invalid_grant")
+		return
+	case base64.RawURLEncoding.EncodeToString(sum[:]) != auth.codeChallenge:
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{
+		"iss":            p.issuer,
+		"aud":            auth.clientID,
+		"sub":            auth.email,
+		"email":          auth.email,
+		"email_verified": auth.emailVerified,
+		"name":           auth.name,
+		"nonce":          auth.nonce,
+		"auth_time":      auth.authTime.Unix(),
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	})
+	token.Header["kid"] = keyID
+	idToken, err := token.SignedString(p.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	raw := make([]byte, 24)
+	rand.Read(raw)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
@@ -0,0 +1,73 @@
+// Package problem writes RFC 7807 application/problem+json error responses.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const ContentType = "application/problem+json"
+
+// typePrefix turns a stable error code into the problem type URI.
+const typePrefix = "urn:expenser:problem:"
+
+type Details struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Code is the stable error code clients switch on.
+	Code string `json:"code"`
+	// Error repeats Detail for clients reading the previous {"error": "..."} body.
+	Error     string `json:"error"`
+	Errors    any    `json:"errors,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// New returns the problem for status. An empty code falls back to the generic code
+// of the status, like not_found for 404.
+func New(status int, code, detail string) Details {
+	if code == "" {
+		code = StatusCode(status)
+	}
+	return Details{
+		Type:   typePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+		Error:  detail,
+	}
+}
+
+// StatusCode returns the generic error code of an HTTP status.
+func StatusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "status_" + strconv.Itoa(status)
+	}
+	switch status {
+	case http.StatusInternalServerError:
+		return "internal_error"
+	case http.StatusRequestEntityTooLarge:
+		return "payload_too_large"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+}
+
+// Write sends p with the status it carries.
+func Write(w http.ResponseWriter, p Details) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return err
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(p.Status)
+	_, err = w.Write(body)
+	return err
+}
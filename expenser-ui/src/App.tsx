@@ -1,4 +1,5 @@
 import { LoginPage } from "@/app/auth/login";
+import { OIDCCallbackPage } from "@/app/auth/oidc-callback";
 import { SignupPage } from "@/app/auth/signup";
 import Category from "@/app/category/category";
 import Dashboard from "@/app/dashboard";
@@ -24,6 +25,7 @@ function AuthRoutes() {
     <Routes>
       <Route path={ROUTES.AUTH.LOGIN} element={<LoginPage />} />
       <Route path={ROUTES.AUTH.SIGNUP} element={<SignupPage />} />
+      <Route path={ROUTES.AUTH.OIDC} element={<OIDCCallbackPage />} />
       <Route path="*" element={<Navigate to="/auth/login" />} />
     </Routes>
   );
@@ -11,26 +11,27 @@ import { Label } from "@/components/ui/label";
 import { apiRequest } from "@/lib/apiRequest";
 import { AlertCircle, Eye, EyeOff } from "lucide-react";
 import { FormEvent, useState } from "react";
-import { Link } from "react-router";
+import { Link, useLocation } from "react-router";
 import { Alert, AlertDescription, AlertTitle } from "@/components/ui/alert";
 import { useAuth } from "@/providers/auth-provider";
 
 function LoginForm() {
   const { handleLogin } = useAuth();
+  const location = useLocation();
 
   const [showPassword, setShowPassword] = useState(false);
   const [username, setUsername] = useState("");
   const [password, setPassword] = useState("");
   const [error, setError] = useState("");
+  // Single sign-on logins of users with two-factor authentication come back with a
+  // challenge token to finish here.
+  const [challengeToken, setChallengeToken] = useState<string>(
+    location.state?.challengeToken ?? "",
+  );
+  const [code, setCode] = useState("");
 
-  const handleSubmit = (e: FormEvent) => {
-    e.preventDefault();
-    if (!username || !password) {
-      setError("Username and password are required");
-      return;
-    }
-
-    apiRequest("/cxf/login", "POST", { username, password })
+  const submit = (path: string, body: object) => {
+    apiRequest(path, "POST", body)
       .then(async (response) => {
         if (!response.ok) {
           const data = await response.json();
@@ -39,6 +40,11 @@ function LoginForm() {
         return response.json();
       })
       .then((res) => {
+        if (res.two_factor_required) {
+          setError("");
+          setChallengeToken(res.challenge_token);
+          return;
+        }
         handleLogin(res.token);
       })
       .catch((error) => {
@@ -46,6 +52,24 @@ function LoginForm() {
       });
   };
 
+  const handleSubmit = (e: FormEvent) => {
+    e.preventDefault();
+    if (challengeToken) {
+      if (!code) {
+        setError("Code is required");
+        return;
+      }
+      submit("/cxf/v1/login/2fa", { challenge_token: challengeToken, code });
+      return;
+    }
+    if (!username || !password) {
+      setError("Username and password are required");
+      return;
+    }
+
+    submit("/cxf/v1/login", { username, password });
+  };
+
   return (
     <Card className="mx-auto max-w-sm">
       <CardHeader>
@@ -63,52 +87,72 @@ function LoginForm() {
               <AlertDescription>{error}</AlertDescription>
             </Alert>
           )}
-          <div className="grid gap-2">
-            <Label htmlFor="email">Email</Label>
-            <Input
-              id="email"
-              type="email"
-              placeholder="m@example.com"
-              required
-              autoComplete="email"
-              onChange={(e) => setUsername(e.target.value)}
-            />
-          </div>
-          <div className="grid gap-2">
-            <div className="flex items-center">
-              <Label htmlFor="password">Password</Label>
-              {/* <Link to="#" className="ml-auto inline-block text-sm underline">
-                Forgot your password?
-              </Link> */}
-            </div>
-            <div className="relative">
+          {challengeToken ? (
+            <div className="grid gap-2">
+              <Label htmlFor="code">Authentication code</Label>
               <Input
-                id="password"
-                type={showPassword ? "text" : "password"}
-                onChange={(e) => setPassword(e.target.value)}
+                id="code"
+                placeholder="123456 or a recovery code"
                 required
+                autoComplete="one-time-code"
+                onChange={(e) => setCode(e.target.value)}
               />
-              <Button
-                type="button"
-                variant="ghost"
-                size="icon"
-                className="absolute right-0 top-0 h-full px-3 py-2 hover:bg-transparent"
-                onClick={() => setShowPassword(!showPassword)}
-              >
-                {showPassword ? (
-                  <EyeOff className="h-4 w-4" />
-                ) : (
-                  <Eye className="h-4 w-4" />
-                )}
-                <span className="sr-only">
-                  {showPassword ? "Hide password" : "Show password"}
-                </span>
-              </Button>
             </div>
-          </div>
+          ) : (
+            <>
+              <div className="grid gap-2">
+                <Label htmlFor="email">Email</Label>
+                <Input
+                  id="email"
+                  type="email"
+                  placeholder="m@example.com"
+                  required
+                  autoComplete="email"
+                  onChange={(e) => setUsername(e.target.value)}
+                />
+              </div>
+              <div className="grid gap-2">
+                <div className="flex items-center">
+                  <Label htmlFor="password">Password</Label>
+                  {/* <Link to="#" className="ml-auto inline-block text-sm underline">
+                    Forgot your password?
+                  </Link> */}
+                </div>
+                <div className="relative">
+                  <Input
+                    id="password"
+                    type={showPassword ? "text" : "password"}
+                    onChange={(e) => setPassword(e.target.value)}
+                    required
+                  />
+                  <Button
+                    type="button"
+                    variant="ghost"
+                    size="icon"
+                    className="absolute right-0 top-0 h-full px-3 py-2 hover:bg-transparent"
+                    onClick={() => setShowPassword(!showPassword)}
+                  >
+                    {showPassword ? (
+                      <EyeOff className="h-4 w-4" />
+                    ) : (
+                      <Eye className="h-4 w-4" />
+                    )}
+                    <span className="sr-only">
+                      {showPassword ? "Hide password" : "Show password"}
+                    </span>
+                  </Button>
+                </div>
+              </div>
+            </>
+          )}
           <Button type="submit" className="w-full" onClick={handleSubmit}>
             Login
           </Button>
+          {!challengeToken && (
+            <Button variant="outline" className="w-full" asChild>
+              <a href="/cxf/v1/oidc/login">Login with single sign-on</a>
+            </Button>
+          )}
         </div>
         <div className="mt-4 text-center text-sm">
           Don&apos;t have an account?{" "}
@@ -0,0 +1,48 @@
+import { Alert, AlertDescription, AlertTitle } from "@/components/ui/alert";
+import { useAuth } from "@/providers/auth-provider";
+import { AlertCircle } from "lucide-react";
+import { useEffect, useState } from "react";
+import { Link, useNavigate } from "react-router";
+
+// The server redirects here after an OIDC login with the token, the two-factor
+// challenge token or the error in the URL fragment.
+export function OIDCCallbackPage() {
+  const { handleLogin } = useAuth();
+  const navigate = useNavigate();
+  const [params] = useState(
+    () => new URLSearchParams(window.location.hash.slice(1)),
+  );
+  const token = params.get("token");
+  const challengeToken = params.get("challenge_token");
+  const error =
+    token || challengeToken ? "" : (params.get("error") ?? "Login failed");
+
+  useEffect(() => {
+    window.history.replaceState(null, "", window.location.pathname);
+    if (token) {
+      handleLogin(token);
+    } else if (challengeToken) {
+      navigate("/auth/login", {
+        replace: true,
+        state: { challengeToken },
+      });
+    }
+  }, [handleLogin, navigate, token, challengeToken]);
+
+  return (
+    <div className="flex h-screen w-full items-center justify-center px-4">
+      {error && (
+        <Alert variant="destructive" className="max-w-sm">
+          <AlertCircle className="h-4 w-4" />
+          <AlertTitle>Error</AlertTitle>
+          <AlertDescription>
+            {error}{" "}
+            <Link to="/auth/login" className="underline">
+              Back to login
+            </Link>
+          </AlertDescription>
+        </Alert>
+      )}
+    </div>
+  );
+}
@@ -13,6 +13,7 @@ import { useGetIncomeQuery } from "@/hooks/use-income-query";
 import { useGetInvestmentQuery } from "@/hooks/use-investment-query";
 import { useIsMobile } from "@/hooks/use-mobile";
 import { createChartConfig, generateColors } from "@/lib/chart-util";
+import { API_DATE_FORMAT } from "@/lib/dateUtil";
 import { useTheme } from "@/providers/theme-provider";
 import { Expense } from "@/types/expense";
 import { Income } from "@/types/income";
@@ -143,7 +144,7 @@ const generatepieChartData = (
 };
 
 const formatDate = (date: string) =>
-  format(parse(date, "dd/MM/yyyy", new Date()), "MMM/yy");
+  format(parse(date, API_DATE_FORMAT, new Date()), "MMM/yy");
 
 const generateLineChartData = (
   expenses: Expense[],
@@ -14,6 +14,7 @@ import {
   useDeleteExpenseMutation,
   useUpdateExpenseMutation,
 } from "@/hooks/use-expense-query";
+import { formatDisplayDate } from "@/lib/dateUtil";
 import { Expense } from "@/types/expense";
 import { TransactionFormSchema } from "@/types/form-schema/transaction";
 import { ColumnDef } from "@tanstack/react-table";
@@ -65,6 +66,7 @@ export const columns: ColumnDef<Expense>[] = [
     header: ({ column }) => {
       return <DataTableColumnHeader column={column} title="Date" />;
     },
+    cell: ({ row }) => formatDisplayDate(row.getValue<string>("date")),
   },
   {
     accessorKey: "note",
@@ -14,6 +14,7 @@ import {
   useDeleteIncomeMutation,
   useUpdateIncomeMutation,
 } from "@/hooks/use-income-query";
+import { formatDisplayDate } from "@/lib/dateUtil";
 import { Income } from "@/types/income";
 import { TransactionFormSchema } from "@/types/form-schema/transaction";
 import { ColumnDef } from "@tanstack/react-table";
@@ -65,6 +66,7 @@ export const columns: ColumnDef<Income>[] = [
     header: ({ column }) => {
       return <DataTableColumnHeader column={column} title="Date" />;
     },
+    cell: ({ row }) => formatDisplayDate(row.getValue<string>("date")),
   },
   {
     accessorKey: "note",
@@ -14,6 +14,7 @@ import {
   useDeleteInvestmentMutation,
   useUpdateInvestmentMutation,
 } from "@/hooks/use-investment-query";
+import { formatDisplayDate } from "@/lib/dateUtil";
 import { Investment } from "@/types/investment";
 import { TransactionFormSchema } from "@/types/form-schema/transaction";
 import { ColumnDef } from "@tanstack/react-table";
@@ -65,6 +66,7 @@ export const columns: ColumnDef<Investment>[] = [
     header: ({ column }) => {
       return <DataTableColumnHeader column={column} title="Date" />;
     },
+    cell: ({ row }) => formatDisplayDate(row.getValue<string>("date")),
   },
   {
     accessorKey: "note",
@@ -60,7 +60,7 @@ export default function TransactionImportPage({
     formData.append("file", files[0]);
 
     try {
-      const response = await fetch("/cxf/bulk-import", {
+      const response = await fetch("/cxf/v1/bulk-import", {
         method: "POST",
         headers: {
           Authorization: `Bearer ${localStorage.getItem("token") || ""}`,
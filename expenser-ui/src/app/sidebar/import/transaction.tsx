@@ -6,7 +6,7 @@ import TransactionReviewPage from "@/app/sidebar/import/transaction-review";
 
 type ApiTransaction = {
   name: string;
-  date: string; // "11/09/2025"
+  date: string; // "2025-09-11"
   expense: boolean;
   amount: string;
 };
@@ -5,6 +5,7 @@ import {
   ChartTooltip,
   ChartTooltipContent,
 } from "@/components/ui/chart";
+import { API_DATE_FORMAT } from "@/lib/dateUtil";
 import { Expense } from "@/types/expense";
 import { compareAsc, format, parse } from "date-fns";
 import { Bar, BarChart, CartesianGrid, LabelList, XAxis } from "recharts";
@@ -46,7 +47,7 @@ interface ChartData {
 }
 
 const parseDate = (dateStr: Date) =>
-  parse(dateStr.toString(), "dd/MM/yyyy", new Date());
+  parse(dateStr.toString(), API_DATE_FORMAT, new Date());
 
 export function BarChartComponent({ data }: BarChartProps) {
   const chartData = generateChartData(data);
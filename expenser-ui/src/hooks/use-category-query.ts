@@ -9,7 +9,7 @@ export const useGetCategoryQuery = () => {
   const query = useQuery({
     queryKey: ["categories"],
     queryFn: async (): Promise<Category[]> => {
-      const res = await apiRequest("/cxf/category", "GET");
+      const res = await apiRequest("/cxf/v1/category", "GET");
       return res.json();
     },
   });
@@ -21,7 +21,7 @@ export const useCreateCategoryMutation = () => {
 
   const mutation = useMutation({
     mutationFn: async (data: z.infer<typeof CategoryFormSchema>) => {
-      const res = await apiRequest("/cxf/category", "POST", data);
+      const res = await apiRequest("/cxf/v1/category", "POST", data);
       if (!res.ok) {
         const errorData = await res.json();
         throw new Error(errorData.error);
@@ -45,7 +45,7 @@ export const useUpdateCategoryMutation = () => {
       id: string;
     }) => {
       const res = await apiRequest(
-        `/cxf/category/${data.id}`,
+        `/cxf/v1/category/${data.id}`,
         "PUT",
         data.category,
       );
@@ -69,7 +69,7 @@ export const useDeleteCategoryMutation = () => {
   const queryClient = useQueryClient();
   const mutation = useMutation({
     mutationFn: async (id: string) => {
-      const res = await apiRequest(`/cxf/category/${id}`, "DELETE");
+      const res = await apiRequest(`/cxf/v1/category/${id}`, "DELETE");
 
       if (!res.ok) {
         const errorData = await res.json();
@@ -1,16 +1,16 @@
 import { apiRequest } from "@/lib/apiRequest";
+import { formatApiDate } from "@/lib/dateUtil";
 import { showToast } from "@/lib/showToast";
 import { Expense } from "@/types/expense";
 import { TransactionFormSchema } from "@/types/form-schema/transaction";
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
-import { format } from "date-fns";
 import { z } from "zod";
 
 export const useGetExpensesQuery = () => {
   const query = useQuery({
     queryKey: ["expenses"],
     queryFn: async (): Promise<Expense[]> => {
-      const res = await apiRequest("/cxf/transaction", "GET");
+      const res = await apiRequest("/cxf/v1/transaction", "GET");
       return res.json();
     },
   });
@@ -25,10 +25,10 @@ export const useCreateExpenseMutation = () => {
       const expenseData = {
         ...data,
         amount: parseFloat(data.amount),
-        date: format(data.date, "dd/MM/yyyy"),
+        date: formatApiDate(data.date),
       };
 
-      const res = await apiRequest("/cxf/transaction", "POST", expenseData);
+      const res = await apiRequest("/cxf/v1/transaction", "POST", expenseData);
       if (!res.ok) {
         throw new Error(`Failed to save expense: ${res.statusText}`);
       }
@@ -58,10 +58,10 @@ export const useUpdateExpenseMutation = () => {
           : (() => {
               throw new Error("Invalid amount format");
             })(),
-        date: format(data.expense.date, "dd/MM/yyyy"),
+        date: formatApiDate(data.expense.date),
       };
       const res = await apiRequest(
-        `/cxf/transaction/${data.id}`,
+        `/cxf/v1/transaction/${data.id}`,
         "PUT",
         expenseData,
       );
@@ -82,7 +82,7 @@ export const useDeleteExpenseMutation = () => {
   const queryClient = useQueryClient();
   const mutation = useMutation({
     mutationFn: async (id: string) => {
-      const res = await apiRequest(`/cxf/transaction/${id}`, "DELETE");
+      const res = await apiRequest(`/cxf/v1/transaction/${id}`, "DELETE");
       if (!res.ok) {
         const errorData = await res.json();
         throw new Error(errorData.error);
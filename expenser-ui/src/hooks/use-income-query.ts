@@ -1,16 +1,16 @@
 import { apiRequest } from "@/lib/apiRequest";
+import { formatApiDate } from "@/lib/dateUtil";
 import { showToast } from "@/lib/showToast";
 import { Income } from "@/types/income";
 import { TransactionFormSchema } from "@/types/form-schema/transaction";
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
-import { format } from "date-fns";
 import { z } from "zod";
 
 export const useGetIncomeQuery = () => {
   const query = useQuery({
     queryKey: ["incomes"],
     queryFn: async (): Promise<Income[]> => {
-      const res = await apiRequest("/cxf/income", "GET");
+      const res = await apiRequest("/cxf/v1/income", "GET");
       return res.json();
     },
   });
@@ -25,10 +25,10 @@ export const useCreateIncomeMutation = () => {
       const incomeData = {
         ...data,
         amount: parseFloat(data.amount),
-        date: format(data.date, "dd/MM/yyyy"),
+        date: formatApiDate(data.date),
       };
 
-      const res = await apiRequest("/cxf/income", "POST", incomeData);
+      const res = await apiRequest("/cxf/v1/income", "POST", incomeData);
       if (!res.ok) {
         throw new Error(`Failed to save income: ${res.statusText}`);
       }
@@ -58,9 +58,9 @@ export const useUpdateIncomeMutation = () => {
           : (() => {
               throw new Error("Invalid amount format");
             })(),
-        date: format(data.income.date, "dd/MM/yyyy"),
+        date: formatApiDate(data.income.date),
       };
-      const res = await apiRequest(`/cxf/income/${data.id}`, "PUT", incomeData);
+      const res = await apiRequest(`/cxf/v1/income/${data.id}`, "PUT", incomeData);
       if (!res.ok) {
         const errorData = await res.json();
         throw new Error(errorData.error);
@@ -78,7 +78,7 @@ export const useDeleteIncomeMutation = () => {
   const queryClient = useQueryClient();
   const mutation = useMutation({
     mutationFn: async (id: string) => {
-      const res = await apiRequest(`/cxf/income/${id}`, "DELETE");
+      const res = await apiRequest(`/cxf/v1/income/${id}`, "DELETE");
       if (!res.ok) {
         const errorData = await res.json();
         throw new Error(errorData.error);
@@ -1,16 +1,16 @@
 import { apiRequest } from "@/lib/apiRequest";
+import { formatApiDate } from "@/lib/dateUtil";
 import { showToast } from "@/lib/showToast";
 import { Investment } from "@/types/investment";
 import { TransactionFormSchema } from "@/types/form-schema/transaction";
 import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
-import { format } from "date-fns";
 import { z } from "zod";
 
 export const useGetInvestmentQuery = () => {
   const query = useQuery({
     queryKey: ["investments"],
     queryFn: async (): Promise<Investment[]> => {
-      const res = await apiRequest("/cxf/investment", "GET");
+      const res = await apiRequest("/cxf/v1/investment", "GET");
       return res.json();
     },
   });
@@ -25,10 +25,10 @@ export const useCreateInvestmentMutation = () => {
       const investmentData = {
         ...data,
         amount: parseFloat(data.amount),
-        date: format(data.date, "dd/MM/yyyy"),
+        date: formatApiDate(data.date),
       };
 
-      const res = await apiRequest("/cxf/investment", "POST", investmentData);
+      const res = await apiRequest("/cxf/v1/investment", "POST", investmentData);
       if (!res.ok) {
         throw new Error(`Failed to save investment: ${res.statusText}`);
       }
@@ -59,10 +59,10 @@ export const useUpdateInvestmentMutation = () => {
           : (() => {
               throw new Error("Invalid amount format");
             })(),
-        date: format(data.investment.date, "dd/MM/yyyy"),
+        date: formatApiDate(data.investment.date),
       };
       const res = await apiRequest(
-        `/cxf/investment/${data.id}`,
+        `/cxf/v1/investment/${data.id}`,
         "PUT",
         investmentData,
       );
@@ -84,7 +84,7 @@ export const useDeleteInvestmentMutation = () => {
   const queryClient = useQueryClient();
   const mutation = useMutation({
     mutationFn: async (id: string) => {
-      const res = await apiRequest(`/cxf/investment/${id}`, "DELETE");
+      const res = await apiRequest(`/cxf/v1/investment/${id}`, "DELETE");
       if (!res.ok) {
         const errorData = await res.json();
         throw new Error(errorData.error);
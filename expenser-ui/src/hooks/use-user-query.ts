@@ -7,7 +7,7 @@ export const useGetUserQuery = () => {
   const query = useQuery({
     queryKey: ["user"],
     queryFn: async (): Promise<User> => {
-      const res = await apiRequest("/cxf/user", "GET");
+      const res = await apiRequest("/cxf/v1/user", "GET");
       return res.json();
     },
   });
@@ -22,7 +22,7 @@ export const useCreateUserMutation = () => {
       username: string;
       password: string;
     }) => {
-      const res = await apiRequest("/cxf/user", "POST", data);
+      const res = await apiRequest("/cxf/v1/user", "POST", data);
       if (!res.ok) {
         const errorData = await res.json();
         throw new Error(errorData.error);
@@ -41,7 +41,7 @@ export const useUpdateUserMutation = () => {
 
   const mutation = useMutation({
     mutationFn: async (data: { name: string; image: string }) => {
-      const res = await apiRequest("/cxf/user", "PUT", {
+      const res = await apiRequest("/cxf/v1/user", "PUT", {
         name: data.name,
         image: data.image,
       });
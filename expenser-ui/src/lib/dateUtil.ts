@@ -1,10 +1,24 @@
-import { parse } from "date-fns";
+import { format, parse } from "date-fns";
+
+// API_DATE_FORMAT is the ISO 8601 format the API accepts and returns dates in.
+export const API_DATE_FORMAT = "yyyy-MM-dd";
+
+// DISPLAY_DATE_FORMAT is used to present dates in the tables.
+export const DISPLAY_DATE_FORMAT = "dd/MM/yyyy";
 
 export const parseDate = (dateStr: string): Date => {
   try {
-    return parse(dateStr, "dd/MM/yyyy", new Date());
+    return parse(dateStr, API_DATE_FORMAT, new Date());
   } catch (error) {
     console.error("Failed to parse date:", error);
     return new Date();
   }
 };
+
+export const formatApiDate = (date: Date): string =>
+  format(date, API_DATE_FORMAT);
+
+export const formatDisplayDate = (dateStr: string): string => {
+  const date = parseDate(dateStr);
+  return isNaN(date.getTime()) ? dateStr : format(date, DISPLAY_DATE_FORMAT);
+};
@@ -3,6 +3,7 @@ export const ROUTES = {
     BASE: "/auth",
     LOGIN: "/login",
     SIGNUP: "/signup",
+    OIDC: "/oidc",
   },
   MAIN: {
     DASHBOARD: "/dashboard",